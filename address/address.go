@@ -0,0 +1,134 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package address encodes and decodes Decred pay-to-pubkey-hash addresses,
+// the base58 check encoding of a two-byte network identifier followed by the
+// Hash160 of a secp256k1 public key.
+package address
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rickiey/blake256"
+	"github.com/rickiey/blake256/internal/base58"
+	"github.com/rickiey/blake256/internal/ripemd160"
+	"github.com/rickiey/blake256/secp256k1"
+)
+
+// Hash160Size is the size of a Hash160 digest.
+const Hash160Size = ripemd160.Size
+
+// Params holds the address identifiers of a Decred network.
+type Params struct {
+	// Name is the name of the network.
+	Name string
+
+	// PubKeyHashAddrID is the version prefix of secp256k1 pay-to-pubkey-hash
+	// addresses.
+	PubKeyHashAddrID [2]byte
+
+	// ScriptHashAddrID is the version prefix of pay-to-script-hash
+	// addresses.
+	ScriptHashAddrID [2]byte
+}
+
+var (
+	// MainNetParams are the address identifiers of the main network.
+	MainNetParams = &Params{
+		Name:             "mainnet",
+		PubKeyHashAddrID: [2]byte{0x07, 0x3f}, // starts with Ds
+		ScriptHashAddrID: [2]byte{0x07, 0x1a}, // starts with Dc
+	}
+
+	// TestNet3Params are the address identifiers of the test network.
+	TestNet3Params = &Params{
+		Name:             "testnet3",
+		PubKeyHashAddrID: [2]byte{0x0f, 0x21}, // starts with Ts
+		ScriptHashAddrID: [2]byte{0x0e, 0xfc}, // starts with Tc
+	}
+
+	// SimNetParams are the address identifiers of the simulation network.
+	SimNetParams = &Params{
+		Name:             "simnet",
+		PubKeyHashAddrID: [2]byte{0x0e, 0x91}, // starts with Ss
+		ScriptHashAddrID: [2]byte{0x0e, 0x6c}, // starts with Sc
+	}
+
+	// RegNetParams are the address identifiers of the regression test
+	// network.
+	RegNetParams = &Params{
+		Name:             "regnet",
+		PubKeyHashAddrID: [2]byte{0x0e, 0x00}, // starts with Rs
+		ScriptHashAddrID: [2]byte{0x0d, 0xdb}, // starts with Rc
+	}
+)
+
+var (
+	// ErrWrongNetwork is returned when decoding an address whose version
+	// belongs to a different network or address type.
+	ErrWrongNetwork = errors.New("address: address is not for the expected network or type")
+
+	// ErrInvalidLength is returned when decoding an address whose payload is
+	// not a Hash160.
+	ErrInvalidLength = errors.New("address: invalid payload length")
+)
+
+// Hash160 returns RIPEMD-160(BLAKE-256(b)), the hash committed to by
+// pay-to-pubkey-hash and pay-to-script-hash addresses.
+func Hash160(b []byte) [Hash160Size]byte {
+	h := blake256.Sum256(b)
+	return ripemd160.Sum(h[:])
+}
+
+// EncodePubKeyHash returns the pay-to-pubkey-hash address of the given public
+// key hash on the network.
+func EncodePubKeyHash(hash *[Hash160Size]byte, net *Params) string {
+	return base58.CheckEncode(hash[:], net.PubKeyHashAddrID)
+}
+
+// EncodePubKey returns the pay-to-pubkey-hash address of the public key,
+// hashed in its compressed or uncompressed serialization.
+func EncodePubKey(pub *secp256k1.PublicKey, compressed bool, net *Params) string {
+	b := pub.SerializeUncompressed()
+	if compressed {
+		b = pub.SerializeCompressed()
+	}
+	h := Hash160(b)
+	return EncodePubKeyHash(&h, net)
+}
+
+// DecodePubKeyHash returns the public key hash of a pay-to-pubkey-hash
+// address on the network.
+func DecodePubKeyHash(addr string, net *Params) ([Hash160Size]byte, error) {
+	return decode(addr, net.PubKeyHashAddrID)
+}
+
+// EncodeScriptHash returns the pay-to-script-hash address of the given
+// script hash on the network.
+func EncodeScriptHash(hash *[Hash160Size]byte, net *Params) string {
+	return base58.CheckEncode(hash[:], net.ScriptHashAddrID)
+}
+
+// DecodeScriptHash returns the script hash of a pay-to-script-hash address on
+// the network.
+func DecodeScriptHash(addr string, net *Params) ([Hash160Size]byte, error) {
+	return decode(addr, net.ScriptHashAddrID)
+}
+
+func decode(addr string, version [2]byte) ([Hash160Size]byte, error) {
+	var h [Hash160Size]byte
+	payload, v, err := base58.CheckDecode(addr)
+	if err != nil {
+		return h, fmt.Errorf("address: %w", err)
+	}
+	if v != version {
+		return h, ErrWrongNetwork
+	}
+	if len(payload) != Hash160Size {
+		return h, ErrInvalidLength
+	}
+	copy(h[:], payload)
+	return h, nil
+}
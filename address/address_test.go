@@ -0,0 +1,104 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package address
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/rickiey/blake256/internal/base58"
+	"github.com/rickiey/blake256/secp256k1"
+)
+
+func TestPubKeyHash(t *testing.T) {
+	tests := []struct {
+		net  *Params
+		hash string
+		addr string
+	}{
+		{MainNetParams, "2789d58cfa0957d206f025c2af056fc8a77cebb0", "DsUZxxoHJSty8DCfwfartwTYbuhmVct7tJu"},
+		{TestNet3Params, "f15da1cb8d1bcb162c6ab446c95757a6e791c916", "Tso2MVTUeVrjHTBFedFhiyM7yVTbieqp91h"},
+	}
+	for i, test := range tests {
+		var h [Hash160Size]byte
+		copy(h[:], hexToBytes(test.hash))
+		if got := EncodePubKeyHash(&h, test.net); got != test.addr {
+			t.Errorf("%d: expected %q, got %q", i, test.addr, got)
+		}
+		got, err := DecodePubKeyHash(test.addr, test.net)
+		if err != nil || got != h {
+			t.Errorf("%d: expected %x, got %x (%v)", i, h, got, err)
+		}
+	}
+}
+
+func TestPrefixes(t *testing.T) {
+	var h [Hash160Size]byte
+	for _, net := range []*Params{MainNetParams, TestNet3Params, SimNetParams, RegNetParams} {
+		prefix := string(net.Name[0] - 'a' + 'A')
+		for _, v := range []struct {
+			addr string
+			kind string
+		}{
+			{EncodePubKeyHash(&h, net), "s"},
+			{EncodeScriptHash(&h, net), "c"},
+		} {
+			if net == MainNetParams {
+				prefix = "D"
+			}
+			if got := v.addr[:2]; got != prefix+v.kind {
+				t.Errorf("%s: expected prefix %q, got %q", net.Name, prefix+v.kind, got)
+			}
+		}
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var h [Hash160Size]byte
+	mainnet := EncodePubKeyHash(&h, MainNetParams)
+	if _, err := DecodePubKeyHash(mainnet, TestNet3Params); !errors.Is(err, ErrWrongNetwork) {
+		t.Errorf("expected %v, got %v", ErrWrongNetwork, err)
+	}
+	if _, err := DecodeScriptHash(mainnet, MainNetParams); !errors.Is(err, ErrWrongNetwork) {
+		t.Errorf("expected %v, got %v", ErrWrongNetwork, err)
+	}
+	short := base58.CheckEncode(h[:19], MainNetParams.PubKeyHashAddrID)
+	if _, err := DecodePubKeyHash(short, MainNetParams); !errors.Is(err, ErrInvalidLength) {
+		t.Errorf("expected %v, got %v", ErrInvalidLength, err)
+	}
+	bad := mainnet[:len(mainnet)-1] + "1"
+	if _, err := DecodePubKeyHash(bad, MainNetParams); !errors.Is(err, base58.ErrChecksum) {
+		t.Errorf("expected %v, got %v", base58.ErrChecksum, err)
+	}
+}
+
+func TestEncodePubKey(t *testing.T) {
+	priv, _ := secp256k1.PrivKeyFromBytes([]byte{1})
+	pub := priv.PubKey()
+	tests := []struct {
+		compressed bool
+		hash       string
+	}{
+		{true, "e280cb6e66b96679aec288b1fbdbd4db08077a1b"},
+		{false, "76a2e145da57d32c5a40f6406287c9248e0d5040"},
+	}
+	for i, test := range tests {
+		var h [Hash160Size]byte
+		copy(h[:], hexToBytes(test.hash))
+		want := EncodePubKeyHash(&h, MainNetParams)
+		if got := EncodePubKey(pub, test.compressed, MainNetParams); got != want {
+			t.Errorf("%d: expected %q, got %q", i, want, got)
+		}
+	}
+}
+
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
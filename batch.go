@@ -0,0 +1,116 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"context"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+)
+
+// Job is an input of a BatchHasher. Reader is hashed if set, otherwise the
+// file named by Path if set, otherwise Data.
+type Job struct {
+	Data   []byte
+	Reader io.Reader
+	Path   string
+}
+
+// Result is the outcome of a Job.
+type Result struct {
+	// Sum is the BLAKE-256 checksum of the input when Err is nil.
+	Sum [Size]byte
+
+	// Err is the error reading the input, or the context error if the job
+	// was canceled.
+	Err error
+}
+
+// BatchHasher computes the BLAKE-256 checksums of many independent inputs
+// concurrently on a fixed number of goroutines.
+type BatchHasher struct {
+	workers int
+}
+
+// NewBatchHasher returns a BatchHasher that runs up to workers jobs at once.
+// A workers value below 1 means runtime.GOMAXPROCS(0).
+func NewBatchHasher(workers int) *BatchHasher {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	return &BatchHasher{workers: workers}
+}
+
+// Sum256 hashes the jobs and returns their results in the same order. When ctx
+// is done, jobs that have not completed fail with the context error.
+func (b *BatchHasher) Sum256(ctx context.Context, jobs []Job) []Result {
+	results := make([]Result, len(jobs))
+	workers := b.workers
+	if workers > len(jobs) {
+		workers = len(jobs)
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				results[i] = runJob(ctx, &jobs[i])
+			}
+		}()
+	}
+	for i := range jobs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return results
+}
+
+// runJob hashes a single job with a pooled digest.
+func runJob(ctx context.Context, job *Job) (res Result) {
+	if res.Err = ctx.Err(); res.Err != nil {
+		return res
+	}
+	d := getDigest(256)
+	defer putDigest(d)
+
+	switch {
+	case job.Reader != nil:
+		_, res.Err = d.ReadFrom(ctxReader{ctx, job.Reader})
+	case job.Path != "":
+		var f *os.File
+		f, res.Err = os.Open(job.Path)
+		if res.Err != nil {
+			return res
+		}
+		_, res.Err = d.ReadFrom(ctxReader{ctx, f})
+		f.Close()
+	default:
+		d.Write(job.Data)
+	}
+	if res.Err == nil {
+		res.Sum = d.checkSum()
+	}
+	return res
+}
+
+// ctxReader is a reader that fails with the context error once its context is
+// done.
+type ctxReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (r ctxReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.r.Read(p)
+}
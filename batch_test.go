@@ -0,0 +1,61 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+)
+
+func TestBatchHasher(t *testing.T) {
+	dir := t.TempDir()
+	var jobs []Job
+	var want [][Size]byte
+	for i := 0; i < 50; i++ {
+		data := bytes.Repeat([]byte{byte(i)}, i*97)
+		want = append(want, Sum256(data))
+		switch i % 3 {
+		case 0:
+			jobs = append(jobs, Job{Data: data})
+		case 1:
+			jobs = append(jobs, Job{Reader: iotest.HalfReader(bytes.NewReader(data))})
+		case 2:
+			path := filepath.Join(dir, string(rune('a'+i)))
+			if err := os.WriteFile(path, data, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			jobs = append(jobs, Job{Path: path})
+		}
+	}
+	jobs = append(jobs, Job{Path: filepath.Join(dir, "missing")})
+
+	results := NewBatchHasher(4).Sum256(context.Background(), jobs)
+	if len(results) != len(jobs) {
+		t.Fatalf("expected %d results, got %d", len(jobs), len(results))
+	}
+	for i, w := range want {
+		if results[i].Err != nil {
+			t.Errorf("%d: unexpected error: %v", i, results[i].Err)
+		} else if results[i].Sum != w {
+			t.Errorf("%d: expected %x, got %x", i, w, results[i].Sum)
+		}
+	}
+	if err := results[len(results)-1].Err; !os.IsNotExist(err) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+
+	// Every job fails once the context is canceled.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for i, res := range NewBatchHasher(0).Sum256(ctx, jobs) {
+		if res.Err != context.Canceled {
+			t.Errorf("%d: expected %v, got %v", i, context.Canceled, res.Err)
+		}
+	}
+}
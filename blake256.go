@@ -5,9 +5,20 @@
 
 // Package blake256 implements BLAKE-256 and BLAKE-224 hash functions (SHA-3
 // candidate).
+//
+// Besides hash.Hash, the hashes returned by the constructors implement
+// io.StringWriter, io.ReaderFrom and a WriteBuffers([][]byte) (int64, error)
+// method for writing several buffers at once, as well as
+// encoding.BinaryMarshaler and encoding.BinaryUnmarshaler to save and restore
+// their state.
 package blake256
 
-import "hash"
+import (
+	"errors"
+	"hash"
+	"reflect"
+	"unsafe"
+)
 
 // BlockSize is the block size of the hash algorithm in bytes.
 const BlockSize = 64
@@ -20,6 +31,7 @@ const Size224 = 28
 
 type digest struct {
 	hashSize int             // hash output size in bits (224 or 256)
+	rounds   int             // number of rounds if reduced, zero for 14
 	h        [8]uint32       // current chain value
 	s        [4]uint32       // salt (zero by default)
 	t        uint64          // message bits counter
@@ -53,6 +65,96 @@ func (d *digest) Reset() {
 	d.nullt = false
 }
 
+const (
+	magic224      = "blk\x02"
+	magic256      = "blk\x03"
+	magic256r8    = "blk\x08"
+	marshaledSize = len(magic256) + 8*4 + 4*4 + 8 + BlockSize + 8
+)
+
+// MarshalBinary returns the internal state of the digest, including the salt,
+// so that hashing can be resumed later with UnmarshalBinary.
+func (d *digest) MarshalBinary() ([]byte, error) {
+	b := make([]byte, 0, marshaledSize)
+	b = append(b, d.magic()...)
+	for _, v := range d.h {
+		b = appendUint32(b, v)
+	}
+	for _, v := range d.s {
+		b = appendUint32(b, v)
+	}
+	b = appendUint64(b, d.t)
+	b = append(b, d.x[:d.nx]...)
+	b = b[:len(b)+len(d.x)-d.nx] // already zero
+	b = appendUint64(b, uint64(d.nx))
+	return b, nil
+}
+
+// UnmarshalBinary restores a state returned by MarshalBinary. The state must
+// be for the same hash size and number of rounds.
+func (d *digest) UnmarshalBinary(b []byte) error {
+	magic := d.magic()
+	if len(b) < len(magic) || string(b[:len(magic)]) != magic {
+		return errors.New("blake256: invalid hash state identifier")
+	}
+	if len(b) != marshaledSize {
+		return errors.New("blake256: invalid hash state size")
+	}
+	b = b[len(magic256):]
+	for i := range d.h {
+		b, d.h[i] = consumeUint32(b)
+	}
+	for i := range d.s {
+		b, d.s[i] = consumeUint32(b)
+	}
+	b, d.t = consumeUint64(b)
+	b = b[copy(d.x[:], b):]
+	b, nx := consumeUint64(b)
+	if nx >= BlockSize || d.t&(BlockSize*8-1) != 0 {
+		return errors.New("blake256: invalid hash state")
+	}
+	d.nx = int(nx)
+	d.nullt = false
+	return nil
+}
+
+// magic returns the identifier of marshaled states of the hash.
+func (d *digest) magic() string {
+	switch {
+	case d.rounds == 8:
+		return magic256r8
+	case d.hashSize == 224:
+		return magic224
+	}
+	return magic256
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return append(b, byte(v>>56), byte(v>>48), byte(v>>40), byte(v>>32),
+		byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+}
+
+func consumeUint32(b []byte) ([]byte, uint32) {
+	v := uint32(b[0])<<24 | uint32(b[1])<<16 | uint32(b[2])<<8 | uint32(b[3])
+	return b[4:], v
+}
+
+func consumeUint64(b []byte) ([]byte, uint64) {
+	v := uint64(b[0])<<56 | uint64(b[1])<<48 | uint64(b[2])<<40 |
+		uint64(b[3])<<32 | uint64(b[4])<<24 | uint64(b[5])<<16 |
+		uint64(b[6])<<8 | uint64(b[7])
+	return b[8:], v
+}
+
+// length returns the number of bytes written to the digest.
+func (d *digest) length() uint64 {
+	return d.t>>3 + uint64(d.nx)
+}
+
 func (d *digest) Size() int { return d.hashSize >> 3 }
 
 func (d *digest) BlockSize() int { return BlockSize }
@@ -82,6 +184,51 @@ func (d *digest) Write(p []byte) (nn int, err error) {
 	return
 }
 
+// WriteString is like Write but takes a string, which avoids converting it to
+// a byte slice first.
+func (d *digest) WriteString(s string) (nn int, err error) {
+	nn = len(s)
+	if d.nx > 0 {
+		n := copy(d.x[d.nx:], s)
+		d.nx += n
+		if d.nx == BlockSize {
+			block(d, d.x[:])
+			d.nx = 0
+		}
+		s = s[n:]
+	}
+	if len(s) >= BlockSize {
+		n := len(s) &^ (BlockSize - 1)
+		block(d, stringBytes(s[:n]))
+		s = s[n:]
+	}
+	if len(s) > 0 {
+		d.nx = copy(d.x[:], s)
+	}
+	return
+}
+
+// stringBytes returns a byte slice that shares the memory of s. The slice
+// must not be modified.
+func stringBytes(s string) []byte {
+	var b []byte
+	bh := (*reflect.SliceHeader)(unsafe.Pointer(&b))
+	bh.Data = (*reflect.StringHeader)(unsafe.Pointer(&s)).Data
+	bh.Len = len(s)
+	bh.Cap = len(s)
+	return b
+}
+
+// WriteBuffers writes each buffer in bufs in order, such as the contents of a
+// net.Buffers, and returns the total number of bytes written.
+func (d *digest) WriteBuffers(bufs [][]byte) (n int64, err error) {
+	for _, b := range bufs {
+		d.Write(b)
+		n += int64(len(b))
+	}
+	return n, nil
+}
+
 // Sum returns the calculated checksum.
 func (d digest) Sum(in []byte) []byte {
 	// Note d is a copy so that the caller can keep writing and summing.
@@ -94,52 +241,47 @@ func (d digest) Sum(in []byte) []byte {
 }
 
 func (d *digest) checkSum() [Size]byte {
-	nx := uint64(d.nx)
-	l := d.t + nx<<3
-	var len [8]byte
-	len[0] = byte(l >> 56)
-	len[1] = byte(l >> 48)
-	len[2] = byte(l >> 40)
-	len[3] = byte(l >> 32)
-	len[4] = byte(l >> 24)
-	len[5] = byte(l >> 16)
-	len[6] = byte(l >> 8)
-	len[7] = byte(l)
+	nx := d.nx
+	l := d.t + uint64(nx)<<3
 
-	if nx == 55 {
-		// One padding byte.
-		d.t -= 8
-		if d.hashSize == 224 {
-			d.Write([]byte{0x80})
-		} else {
-			d.Write([]byte{0x81})
-		}
+	// Pad the buffered data in place: a 1 bit right after the message, zeros,
+	// and a final bit before the length which is 1 for BLAKE-256 and 0 for
+	// BLAKE-224.
+	x := d.x[:]
+	x[nx] = 0x80
+	copy(x[nx+1:], pad[1:])
+	if nx >= 56 {
+		// No room for the length, so it goes into an extra block that
+		// contains no message bits.
+		d.t = l - 512
+		block(d, x)
+		copy(x[:56], pad[1:])
+		d.nullt = true
+	} else if nx == 0 {
+		// The final block contains no message bits.
+		d.nullt = true
 	} else {
-		if nx < 55 {
-			// Enough space to fill the block.
-			if nx == 0 {
-				d.nullt = true
-			}
-			d.t -= 440 - nx<<3
-			d.Write(pad[0 : 55-nx])
-		} else {
-			// Need 2 compressions.
-			d.t -= 512 - nx<<3
-			d.Write(pad[0 : 64-nx])
-			d.t -= 440
-			d.Write(pad[1:56])
-			d.nullt = true
-		}
-		if d.hashSize == 224 {
-			d.Write([]byte{0x00})
-		} else {
-			d.Write([]byte{0x01})
-		}
-		d.t -= 8
+		// The counter of the final block is the full message length.
+		d.t = l - 512
+	}
+	if d.hashSize != 224 {
+		x[55] |= 0x01
 	}
-	d.t -= 64
-	d.Write(len[:])
+	x[56] = byte(l >> 56)
+	x[57] = byte(l >> 48)
+	x[58] = byte(l >> 40)
+	x[59] = byte(l >> 32)
+	x[60] = byte(l >> 24)
+	x[61] = byte(l >> 16)
+	x[62] = byte(l >> 8)
+	x[63] = byte(l)
+	block(d, x)
+	return d.chainValue()
+}
 
+// chainValue returns the current chain value in big-endian byte order. Only
+// the first 28 bytes are meaningful for BLAKE-224.
+func (d *digest) chainValue() [Size]byte {
 	var out [Size]byte
 	j := 0
 	for _, s := range d.h[:d.hashSize>>5] {
@@ -152,6 +294,19 @@ func (d *digest) checkSum() [Size]byte {
 	return out
 }
 
+// sum256 returns the chain value h in big-endian byte order, the BLAKE-256
+// checksum when h is final.
+func sum256(h *[8]uint32) [Size]byte {
+	var out [Size]byte
+	for i, v := range h {
+		out[4*i+0] = byte(v >> 24)
+		out[4*i+1] = byte(v >> 16)
+		out[4*i+2] = byte(v >> 8)
+		out[4*i+3] = byte(v)
+	}
+	return out
+}
+
 func (d *digest) setSalt(s []byte) {
 	if len(s) != 16 {
 		panic("salt length must be 16 bytes")
@@ -218,6 +373,20 @@ func Sum224(data []byte) (sum224 [Size224]byte) {
 	return
 }
 
+// AppendSum256 appends the BLAKE-256 checksum of the data to dst and returns
+// the resulting slice. It does not allocate when dst has enough capacity.
+func AppendSum256(dst, data []byte) []byte {
+	sum := Sum256(data)
+	return append(dst, sum[:]...)
+}
+
+// AppendSum224 appends the BLAKE-224 checksum of the data to dst and returns
+// the resulting slice. It does not allocate when dst has enough capacity.
+func AppendSum224(dst, data []byte) []byte {
+	sum := Sum224(data)
+	return append(dst, sum[:]...)
+}
+
 const (
 	cst0  = 0x243F6A88
 	cst1  = 0x85A308D3
@@ -238,28 +407,8 @@ const (
 )
 
 func block(d *digest, p []uint8) {
-	h0, h1, h2, h3, h4, h5, h6, h7 := d.h[0], d.h[1], d.h[2], d.h[3], d.h[4], d.h[5], d.h[6], d.h[7]
-	s0, s1, s2, s3 := d.s[0], d.s[1], d.s[2], d.s[3]
-
 	for len(p) >= BlockSize {
-		v0, v1, v2, v3, v4, v5, v6, v7 := h0, h1, h2, h3, h4, h5, h6, h7
-		v8 := cst0 ^ s0
-		v9 := cst1 ^ s1
-		v10 := cst2 ^ s2
-		v11 := cst3 ^ s3
-		v12 := uint32(cst4)
-		v13 := uint32(cst5)
-		v14 := uint32(cst6)
-		v15 := uint32(cst7)
-		d.t += 512
-		if !d.nullt {
-			v12 ^= uint32(d.t)
-			v13 ^= uint32(d.t)
-			v14 ^= uint32(d.t >> 32)
-			v15 ^= uint32(d.t >> 32)
-		}
 		var m [16]uint32
-
 		m[0] = uint32(p[0])<<24 | uint32(p[1])<<16 | uint32(p[2])<<8 | uint32(p[3])
 		m[1] = uint32(p[4])<<24 | uint32(p[5])<<16 | uint32(p[6])<<8 | uint32(p[7])
 		m[2] = uint32(p[8])<<24 | uint32(p[9])<<16 | uint32(p[10])<<8 | uint32(p[11])
@@ -277,1612 +426,1641 @@ func block(d *digest, p []uint8) {
 		m[14] = uint32(p[56])<<24 | uint32(p[57])<<16 | uint32(p[58])<<8 | uint32(p[59])
 		m[15] = uint32(p[60])<<24 | uint32(p[61])<<16 | uint32(p[62])<<8 | uint32(p[63])
 
-		// Round 1.
-		v0 += m[0] ^ cst1
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[2] ^ cst3
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[4] ^ cst5
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[6] ^ cst7
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[5] ^ cst4
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[7] ^ cst6
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[3] ^ cst2
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[1] ^ cst0
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[8] ^ cst9
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[10] ^ cst11
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[12] ^ cst13
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[14] ^ cst15
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[13] ^ cst12
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[15] ^ cst14
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[11] ^ cst10
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[9] ^ cst8
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+		d.t += 512
+		t := d.t
+		if d.nullt {
+			t = 0
+		}
+		if d.rounds != 0 {
+			compressRounds(&d.h, &d.s, t, &m, d.rounds)
+		} else {
+			compress(&d.h, &d.s, t, &m)
+		}
+		p = p[BlockSize:]
+	}
+}
 
-		// Round 2.
-		v0 += m[14] ^ cst10
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[4] ^ cst8
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[9] ^ cst15
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[13] ^ cst6
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[15] ^ cst9
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[6] ^ cst13
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[8] ^ cst4
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[10] ^ cst14
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[1] ^ cst12
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[0] ^ cst2
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[11] ^ cst7
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[5] ^ cst3
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[7] ^ cst11
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[3] ^ cst5
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[2] ^ cst0
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[12] ^ cst1
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+// compress updates the chain value h with the message block m, the salt s
+// and the counter t, the number of message bits up to and including this
+// block, or zero for a final block without message bits.
+func compress(h *[8]uint32, s *[4]uint32, t uint64, m *[16]uint32) {
+	h0, h1, h2, h3, h4, h5, h6, h7 := h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7]
+	s0, s1, s2, s3 := s[0], s[1], s[2], s[3]
 
-		// Round 3.
-		v0 += m[11] ^ cst8
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[12] ^ cst0
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[5] ^ cst2
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[15] ^ cst13
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[2] ^ cst5
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[13] ^ cst15
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[0] ^ cst12
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[8] ^ cst11
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[10] ^ cst14
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[3] ^ cst6
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[7] ^ cst1
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[9] ^ cst4
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[1] ^ cst7
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[4] ^ cst9
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[6] ^ cst3
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[14] ^ cst10
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+	v0, v1, v2, v3, v4, v5, v6, v7 := h0, h1, h2, h3, h4, h5, h6, h7
+	v8 := cst0 ^ s0
+	v9 := cst1 ^ s1
+	v10 := cst2 ^ s2
+	v11 := cst3 ^ s3
+	v12 := cst4 ^ uint32(t)
+	v13 := cst5 ^ uint32(t)
+	v14 := cst6 ^ uint32(t>>32)
+	v15 := cst7 ^ uint32(t>>32)
 
-		// Round 4.
-		v0 += m[7] ^ cst9
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[3] ^ cst1
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[13] ^ cst12
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[11] ^ cst14
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[12] ^ cst13
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[14] ^ cst11
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[1] ^ cst3
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[9] ^ cst7
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[2] ^ cst6
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[5] ^ cst10
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[4] ^ cst0
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[15] ^ cst8
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[0] ^ cst4
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[8] ^ cst15
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[10] ^ cst5
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[6] ^ cst2
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+	// Round 1.
+	v0 += m[0] ^ cst1
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[2] ^ cst3
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[4] ^ cst5
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[6] ^ cst7
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[5] ^ cst4
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[7] ^ cst6
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[3] ^ cst2
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[1] ^ cst0
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[8] ^ cst9
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[10] ^ cst11
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[12] ^ cst13
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[14] ^ cst15
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[13] ^ cst12
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[15] ^ cst14
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[11] ^ cst10
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[9] ^ cst8
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
 
-		// Round 5.
-		v0 += m[9] ^ cst0
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[5] ^ cst7
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[2] ^ cst4
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[10] ^ cst15
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[4] ^ cst2
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[15] ^ cst10
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[7] ^ cst5
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[0] ^ cst9
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[14] ^ cst1
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[11] ^ cst12
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[6] ^ cst8
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[3] ^ cst13
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[8] ^ cst6
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[13] ^ cst3
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[12] ^ cst11
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[1] ^ cst14
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+	// Round 2.
+	v0 += m[14] ^ cst10
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[4] ^ cst8
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[9] ^ cst15
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[13] ^ cst6
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[15] ^ cst9
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[6] ^ cst13
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[8] ^ cst4
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[10] ^ cst14
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[1] ^ cst12
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[0] ^ cst2
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[11] ^ cst7
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[5] ^ cst3
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[7] ^ cst11
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[3] ^ cst5
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[2] ^ cst0
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[12] ^ cst1
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
 
-		// Round 6.
-		v0 += m[2] ^ cst12
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[6] ^ cst10
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[0] ^ cst11
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[8] ^ cst3
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[11] ^ cst0
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[3] ^ cst8
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[10] ^ cst6
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[12] ^ cst2
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[4] ^ cst13
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[7] ^ cst5
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[15] ^ cst14
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[1] ^ cst9
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[14] ^ cst15
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[9] ^ cst1
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[5] ^ cst7
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[13] ^ cst4
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+	// Round 3.
+	v0 += m[11] ^ cst8
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[12] ^ cst0
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[5] ^ cst2
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[15] ^ cst13
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[2] ^ cst5
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[13] ^ cst15
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[0] ^ cst12
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[8] ^ cst11
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[10] ^ cst14
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[3] ^ cst6
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[7] ^ cst1
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[9] ^ cst4
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[1] ^ cst7
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[4] ^ cst9
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[6] ^ cst3
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[14] ^ cst10
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
 
-		// Round 7.
-		v0 += m[12] ^ cst5
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[1] ^ cst15
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[14] ^ cst13
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[4] ^ cst10
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[13] ^ cst14
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[10] ^ cst4
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[15] ^ cst1
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[5] ^ cst12
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[0] ^ cst7
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[6] ^ cst3
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[9] ^ cst2
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[8] ^ cst11
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[2] ^ cst9
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[11] ^ cst8
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[3] ^ cst6
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[7] ^ cst0
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+	// Round 4.
+	v0 += m[7] ^ cst9
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[3] ^ cst1
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[13] ^ cst12
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[11] ^ cst14
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[12] ^ cst13
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[14] ^ cst11
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[1] ^ cst3
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[9] ^ cst7
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[2] ^ cst6
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[5] ^ cst10
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[4] ^ cst0
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[15] ^ cst8
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[0] ^ cst4
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[8] ^ cst15
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[10] ^ cst5
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[6] ^ cst2
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
 
-		// Round 8.
-		v0 += m[13] ^ cst11
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[7] ^ cst14
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[12] ^ cst1
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[3] ^ cst9
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[1] ^ cst12
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[9] ^ cst3
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[14] ^ cst7
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[11] ^ cst13
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[5] ^ cst0
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[15] ^ cst4
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[8] ^ cst6
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[2] ^ cst10
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[6] ^ cst8
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[10] ^ cst2
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[4] ^ cst15
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[0] ^ cst5
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+	// Round 5.
+	v0 += m[9] ^ cst0
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[5] ^ cst7
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[2] ^ cst4
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[10] ^ cst15
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[4] ^ cst2
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[15] ^ cst10
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[7] ^ cst5
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[0] ^ cst9
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[14] ^ cst1
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[11] ^ cst12
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[6] ^ cst8
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[3] ^ cst13
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[8] ^ cst6
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[13] ^ cst3
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[12] ^ cst11
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[1] ^ cst14
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
 
-		// Round 9.
-		v0 += m[6] ^ cst15
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[14] ^ cst9
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[11] ^ cst3
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[0] ^ cst8
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[3] ^ cst11
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[8] ^ cst0
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[9] ^ cst14
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[15] ^ cst6
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[12] ^ cst2
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[13] ^ cst7
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[1] ^ cst4
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[10] ^ cst5
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[4] ^ cst1
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[5] ^ cst10
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[7] ^ cst13
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[2] ^ cst12
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+	// Round 6.
+	v0 += m[2] ^ cst12
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[6] ^ cst10
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[0] ^ cst11
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[8] ^ cst3
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[11] ^ cst0
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[3] ^ cst8
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[10] ^ cst6
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[12] ^ cst2
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[4] ^ cst13
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[7] ^ cst5
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[15] ^ cst14
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[1] ^ cst9
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[14] ^ cst15
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[9] ^ cst1
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[5] ^ cst7
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[13] ^ cst4
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
 
-		// Round 10.
-		v0 += m[10] ^ cst2
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[8] ^ cst4
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[7] ^ cst6
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[1] ^ cst5
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[6] ^ cst7
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[5] ^ cst1
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[4] ^ cst8
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[2] ^ cst10
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[15] ^ cst11
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[9] ^ cst14
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[3] ^ cst12
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[13] ^ cst0
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[12] ^ cst3
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[0] ^ cst13
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[14] ^ cst9
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[11] ^ cst15
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+	// Round 7.
+	v0 += m[12] ^ cst5
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[1] ^ cst15
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[14] ^ cst13
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[4] ^ cst10
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[13] ^ cst14
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[10] ^ cst4
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[15] ^ cst1
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[5] ^ cst12
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[0] ^ cst7
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[6] ^ cst3
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[9] ^ cst2
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[8] ^ cst11
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[2] ^ cst9
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[11] ^ cst8
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[3] ^ cst6
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[7] ^ cst0
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
 
-		// Round 11.
-		v0 += m[0] ^ cst1
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[2] ^ cst3
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[4] ^ cst5
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[6] ^ cst7
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[5] ^ cst4
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[7] ^ cst6
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[3] ^ cst2
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[1] ^ cst0
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[8] ^ cst9
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[10] ^ cst11
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[12] ^ cst13
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[14] ^ cst15
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[13] ^ cst12
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[15] ^ cst14
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[11] ^ cst10
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[9] ^ cst8
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+	// Round 8.
+	v0 += m[13] ^ cst11
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[7] ^ cst14
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[12] ^ cst1
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[3] ^ cst9
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[1] ^ cst12
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[9] ^ cst3
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[14] ^ cst7
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[11] ^ cst13
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[5] ^ cst0
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[15] ^ cst4
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[8] ^ cst6
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[2] ^ cst10
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[6] ^ cst8
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[10] ^ cst2
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[4] ^ cst15
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[0] ^ cst5
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
 
-		// Round 12.
-		v0 += m[14] ^ cst10
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[4] ^ cst8
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[9] ^ cst15
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[13] ^ cst6
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[15] ^ cst9
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[6] ^ cst13
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[8] ^ cst4
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[10] ^ cst14
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[1] ^ cst12
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[0] ^ cst2
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[11] ^ cst7
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[5] ^ cst3
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[7] ^ cst11
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[3] ^ cst5
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[2] ^ cst0
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[12] ^ cst1
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+	// Round 9.
+	v0 += m[6] ^ cst15
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[14] ^ cst9
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[11] ^ cst3
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[0] ^ cst8
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[3] ^ cst11
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[8] ^ cst0
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[9] ^ cst14
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[15] ^ cst6
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[12] ^ cst2
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[13] ^ cst7
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[1] ^ cst4
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[10] ^ cst5
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[4] ^ cst1
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[5] ^ cst10
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[7] ^ cst13
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[2] ^ cst12
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
 
-		// Round 13.
-		v0 += m[11] ^ cst8
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[12] ^ cst0
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[5] ^ cst2
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[15] ^ cst13
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[2] ^ cst5
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[13] ^ cst15
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[0] ^ cst12
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[8] ^ cst11
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[10] ^ cst14
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[3] ^ cst6
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[7] ^ cst1
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[9] ^ cst4
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[1] ^ cst7
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[4] ^ cst9
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[6] ^ cst3
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[14] ^ cst10
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+	// Round 10.
+	v0 += m[10] ^ cst2
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[8] ^ cst4
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[7] ^ cst6
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[1] ^ cst5
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[6] ^ cst7
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[5] ^ cst1
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[4] ^ cst8
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[2] ^ cst10
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[15] ^ cst11
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[9] ^ cst14
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[3] ^ cst12
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[13] ^ cst0
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[12] ^ cst3
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[0] ^ cst13
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[14] ^ cst9
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[11] ^ cst15
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
 
-		// Round 14.
-		v0 += m[7] ^ cst9
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-16) | v12>>16
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-12) | v4>>12
-		v1 += m[3] ^ cst1
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-16) | v13>>16
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-12) | v5>>12
-		v2 += m[13] ^ cst12
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-16) | v14>>16
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-12) | v6>>12
-		v3 += m[11] ^ cst14
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-16) | v15>>16
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-12) | v7>>12
-		v2 += m[12] ^ cst13
-		v2 += v6
-		v14 ^= v2
-		v14 = v14<<(32-8) | v14>>8
-		v10 += v14
-		v6 ^= v10
-		v6 = v6<<(32-7) | v6>>7
-		v3 += m[14] ^ cst11
-		v3 += v7
-		v15 ^= v3
-		v15 = v15<<(32-8) | v15>>8
-		v11 += v15
-		v7 ^= v11
-		v7 = v7<<(32-7) | v7>>7
-		v1 += m[1] ^ cst3
-		v1 += v5
-		v13 ^= v1
-		v13 = v13<<(32-8) | v13>>8
-		v9 += v13
-		v5 ^= v9
-		v5 = v5<<(32-7) | v5>>7
-		v0 += m[9] ^ cst7
-		v0 += v4
-		v12 ^= v0
-		v12 = v12<<(32-8) | v12>>8
-		v8 += v12
-		v4 ^= v8
-		v4 = v4<<(32-7) | v4>>7
-		v0 += m[2] ^ cst6
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-16) | v15>>16
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-12) | v5>>12
-		v1 += m[5] ^ cst10
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-16) | v12>>16
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-12) | v6>>12
-		v2 += m[4] ^ cst0
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-16) | v13>>16
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-12) | v7>>12
-		v3 += m[15] ^ cst8
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-16) | v14>>16
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-12) | v4>>12
-		v2 += m[0] ^ cst4
-		v2 += v7
-		v13 ^= v2
-		v13 = v13<<(32-8) | v13>>8
-		v8 += v13
-		v7 ^= v8
-		v7 = v7<<(32-7) | v7>>7
-		v3 += m[8] ^ cst15
-		v3 += v4
-		v14 ^= v3
-		v14 = v14<<(32-8) | v14>>8
-		v9 += v14
-		v4 ^= v9
-		v4 = v4<<(32-7) | v4>>7
-		v1 += m[10] ^ cst5
-		v1 += v6
-		v12 ^= v1
-		v12 = v12<<(32-8) | v12>>8
-		v11 += v12
-		v6 ^= v11
-		v6 = v6<<(32-7) | v6>>7
-		v0 += m[6] ^ cst2
-		v0 += v5
-		v15 ^= v0
-		v15 = v15<<(32-8) | v15>>8
-		v10 += v15
-		v5 ^= v10
-		v5 = v5<<(32-7) | v5>>7
+	// Round 11.
+	v0 += m[0] ^ cst1
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[2] ^ cst3
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[4] ^ cst5
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[6] ^ cst7
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[5] ^ cst4
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[7] ^ cst6
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[3] ^ cst2
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[1] ^ cst0
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[8] ^ cst9
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[10] ^ cst11
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[12] ^ cst13
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[14] ^ cst15
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[13] ^ cst12
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[15] ^ cst14
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[11] ^ cst10
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[9] ^ cst8
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
 
-		h0 ^= v0 ^ v8 ^ s0
-		h1 ^= v1 ^ v9 ^ s1
-		h2 ^= v2 ^ v10 ^ s2
-		h3 ^= v3 ^ v11 ^ s3
-		h4 ^= v4 ^ v12 ^ s0
-		h5 ^= v5 ^ v13 ^ s1
-		h6 ^= v6 ^ v14 ^ s2
-		h7 ^= v7 ^ v15 ^ s3
+	// Round 12.
+	v0 += m[14] ^ cst10
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[4] ^ cst8
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[9] ^ cst15
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[13] ^ cst6
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[15] ^ cst9
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[6] ^ cst13
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[8] ^ cst4
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[10] ^ cst14
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[1] ^ cst12
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[0] ^ cst2
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[11] ^ cst7
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[5] ^ cst3
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[7] ^ cst11
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[3] ^ cst5
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[2] ^ cst0
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[12] ^ cst1
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
 
-		p = p[BlockSize:]
-	}
-	d.h[0], d.h[1], d.h[2], d.h[3], d.h[4], d.h[5], d.h[6], d.h[7] = h0, h1, h2, h3, h4, h5, h6, h7
+	// Round 13.
+	v0 += m[11] ^ cst8
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[12] ^ cst0
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[5] ^ cst2
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[15] ^ cst13
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[2] ^ cst5
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[13] ^ cst15
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[0] ^ cst12
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[8] ^ cst11
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[10] ^ cst14
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[3] ^ cst6
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[7] ^ cst1
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[9] ^ cst4
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[1] ^ cst7
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[4] ^ cst9
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[6] ^ cst3
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[14] ^ cst10
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
+
+	// Round 14.
+	v0 += m[7] ^ cst9
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-16) | v12>>16
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-12) | v4>>12
+	v1 += m[3] ^ cst1
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-16) | v13>>16
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-12) | v5>>12
+	v2 += m[13] ^ cst12
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-16) | v14>>16
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-12) | v6>>12
+	v3 += m[11] ^ cst14
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-16) | v15>>16
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-12) | v7>>12
+	v2 += m[12] ^ cst13
+	v2 += v6
+	v14 ^= v2
+	v14 = v14<<(32-8) | v14>>8
+	v10 += v14
+	v6 ^= v10
+	v6 = v6<<(32-7) | v6>>7
+	v3 += m[14] ^ cst11
+	v3 += v7
+	v15 ^= v3
+	v15 = v15<<(32-8) | v15>>8
+	v11 += v15
+	v7 ^= v11
+	v7 = v7<<(32-7) | v7>>7
+	v1 += m[1] ^ cst3
+	v1 += v5
+	v13 ^= v1
+	v13 = v13<<(32-8) | v13>>8
+	v9 += v13
+	v5 ^= v9
+	v5 = v5<<(32-7) | v5>>7
+	v0 += m[9] ^ cst7
+	v0 += v4
+	v12 ^= v0
+	v12 = v12<<(32-8) | v12>>8
+	v8 += v12
+	v4 ^= v8
+	v4 = v4<<(32-7) | v4>>7
+	v0 += m[2] ^ cst6
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-16) | v15>>16
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-12) | v5>>12
+	v1 += m[5] ^ cst10
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-16) | v12>>16
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-12) | v6>>12
+	v2 += m[4] ^ cst0
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-16) | v13>>16
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-12) | v7>>12
+	v3 += m[15] ^ cst8
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-16) | v14>>16
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-12) | v4>>12
+	v2 += m[0] ^ cst4
+	v2 += v7
+	v13 ^= v2
+	v13 = v13<<(32-8) | v13>>8
+	v8 += v13
+	v7 ^= v8
+	v7 = v7<<(32-7) | v7>>7
+	v3 += m[8] ^ cst15
+	v3 += v4
+	v14 ^= v3
+	v14 = v14<<(32-8) | v14>>8
+	v9 += v14
+	v4 ^= v9
+	v4 = v4<<(32-7) | v4>>7
+	v1 += m[10] ^ cst5
+	v1 += v6
+	v12 ^= v1
+	v12 = v12<<(32-8) | v12>>8
+	v11 += v12
+	v6 ^= v11
+	v6 = v6<<(32-7) | v6>>7
+	v0 += m[6] ^ cst2
+	v0 += v5
+	v15 ^= v0
+	v15 = v15<<(32-8) | v15>>8
+	v10 += v15
+	v5 ^= v10
+	v5 = v5<<(32-7) | v5>>7
+
+	h0 ^= v0 ^ v8 ^ s0
+	h1 ^= v1 ^ v9 ^ s1
+	h2 ^= v2 ^ v10 ^ s2
+	h3 ^= v3 ^ v11 ^ s3
+	h4 ^= v4 ^ v12 ^ s0
+	h5 ^= v5 ^ v13 ^ s1
+	h6 ^= v6 ^ v14 ^ s2
+	h7 ^= v7 ^ v15 ^ s3
+
+	h[0], h[1], h[2], h[3], h[4], h[5], h[6], h[7] = h0, h1, h2, h3, h4, h5, h6, h7
 }
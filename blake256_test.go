@@ -7,8 +7,11 @@ package blake256
 
 import (
 	"bytes"
+	"context"
+	"encoding"
 	"fmt"
 	"hash"
+	"io"
 	"testing"
 )
 
@@ -176,6 +179,97 @@ func TestTwoWrites(t *testing.T) {
 	}
 }
 
+func TestWriteString(t *testing.T) {
+	b := make([]byte, 200)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	want := Sum256(b)
+	for split := 0; split <= len(b); split++ {
+		h := New()
+		h.Write(b[:split])
+		h.(io.StringWriter).WriteString(string(b[split:]))
+		if sum := h.Sum(nil); !bytes.Equal(sum, want[:]) {
+			t.Errorf("%d: expected %x, got %x", split, want, sum)
+		}
+	}
+}
+
+func TestWriteBuffers(t *testing.T) {
+	b := make([]byte, 200)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	want := Sum224(b)
+	bufs := [][]byte{b[:1], nil, b[1:70], b[70:128], b[128:]}
+	h := New224()
+	n, err := h.(interface {
+		WriteBuffers([][]byte) (int64, error)
+	}).WriteBuffers(bufs)
+	if err != nil || n != int64(len(b)) {
+		t.Fatalf("expected %d bytes written, got %d (err %v)", len(b), n, err)
+	}
+	if sum := h.Sum(nil); !bytes.Equal(sum, want[:]) {
+		t.Errorf("expected %x, got %x", want, sum)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	b := make([]byte, 150)
+	for i := range b {
+		b[i] = byte(i)
+	}
+	salt := []byte("SALTsaltSaltSALT")
+	ctors := []func() hash.Hash{
+		New,
+		New224,
+		func() hash.Hash { return NewSalt(salt) },
+		func() hash.Hash { return New224Salt(salt) },
+	}
+	for i, ctor := range ctors {
+		h := ctor()
+		h.Write(b)
+		want := h.Sum(nil)
+
+		for split := 0; split <= len(b); split++ {
+			h1 := ctor()
+			h1.Write(b[:split])
+			state, err := h1.(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				t.Fatalf("%d/%d: unexpected error: %v", i, split, err)
+			}
+
+			// Restore into a hash without salt to check that the salt
+			// is part of the state.
+			h2 := New()
+			if h.Size() == Size224 {
+				h2 = New224()
+			}
+			if err := h2.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+				t.Fatalf("%d/%d: unexpected error: %v", i, split, err)
+			}
+			h2.Write(b[split:])
+			if sum := h2.Sum(nil); !bytes.Equal(sum, want) {
+				t.Errorf("%d/%d: expected %x, got %x", i, split, want, sum)
+			}
+		}
+	}
+
+	// Mismatched hash sizes and corrupt states are rejected.
+	state, _ := New224().(encoding.BinaryMarshaler).MarshalBinary()
+	if err := New().(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err == nil {
+		t.Errorf("expected error restoring a BLAKE-224 state into BLAKE-256")
+	}
+	u := New224().(encoding.BinaryUnmarshaler)
+	if err := u.UnmarshalBinary(state[:len(state)-1]); err == nil {
+		t.Errorf("expected error for truncated state")
+	}
+	state[len(state)-1] = BlockSize
+	if err := u.UnmarshalBinary(state); err == nil {
+		t.Errorf("expected error for invalid buffer length")
+	}
+}
+
 var bufIn = make([]byte, 8<<10)
 var bufOut = make([]byte, 32)
 
@@ -197,6 +291,16 @@ func Benchmark8K(b *testing.B) {
 	}
 }
 
+func Benchmark8KWriteString(b *testing.B) {
+	s := string(bufIn)
+	b.SetBytes(int64(len(s)))
+	for i := 0; i < b.N; i++ {
+		var bench = New()
+		bench.(io.StringWriter).WriteString(s)
+		_ = bench.Sum(bufOut[0:0])
+	}
+}
+
 func Benchmark64(b *testing.B) {
 	b.SetBytes(64)
 	for i := 0; i < b.N; i++ {
@@ -213,6 +317,25 @@ func Benchmark1KNoAlloc(b *testing.B) {
 	}
 }
 
+func Benchmark1KPoolNoAlloc(b *testing.B) {
+	b.SetBytes(1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		h := Acquire()
+		h.Write(bufIn[:1024])
+		_ = h.Sum(bufOut[0:0])
+		Release(h)
+	}
+}
+
+func Benchmark1KAppendNoAlloc(b *testing.B) {
+	b.SetBytes(1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = AppendSum256(bufOut[0:0], bufIn[:1024])
+	}
+}
+
 func Benchmark8KNoAlloc(b *testing.B) {
 	b.SetBytes(int64(len(bufIn)))
 	for i := 0; i < b.N; i++ {
@@ -226,3 +349,72 @@ func Benchmark64NoAlloc(b *testing.B) {
 		_ = Sum256(bufIn[:64])
 	}
 }
+
+func Benchmark1KBatch(b *testing.B) {
+	jobs := make([]Job, 64)
+	for i := range jobs {
+		jobs[i].Data = bufIn[:1024]
+	}
+	h := NewBatchHasher(0)
+	ctx := context.Background()
+	b.SetBytes(int64(len(jobs)) * 1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = h.Sum256(ctx, jobs)
+	}
+}
+
+func TestPaddingLengths(t *testing.T) {
+	// Hash every message length around the one- and two-block padding
+	// boundaries and fold the results into a single digest.
+	data := make([]byte, 300)
+	for i := range data {
+		data[i] = byte(i*7 + 3)
+	}
+	salt := []byte("0123456789abcdef")
+	h256, h224, hSalt := New(), New(), New()
+	for n := 0; n <= len(data); n++ {
+		s256 := Sum256(data[:n])
+		h256.Write(s256[:])
+		s224 := Sum224(data[:n])
+		h224.Write(s224[:])
+		h := New224Salt(salt)
+		h.Write(data[:n])
+		hSalt.Write(h.Sum(nil))
+	}
+
+	tests := []struct {
+		name string
+		h    hash.Hash
+		want string
+	}{
+		{"256", h256, "668f31891c93ed105c0dd94087cb5d25c013b4885abe0a5dbb16da0a23326ad2"},
+		{"224", h224, "8d574d07b07162389086790df23147e309fe21d3fcb8e802cde3a41ff4501c44"},
+		{"224 salt", hSalt, "471808e03aeec0f5c92119c7f2fcf3ab9060f7faef2192a748a8c50e21fcd634"},
+	}
+	for _, test := range tests {
+		res := fmt.Sprintf("%x", test.h.Sum(nil))
+		if res != test.want {
+			t.Errorf("%s: expected %q, got %q", test.name, test.want, res)
+		}
+	}
+}
+
+func BenchmarkSmallNoAlloc(b *testing.B) {
+	for _, n := range []int{0, 1, 32, 55, 56, 64, 100, 119, 128} {
+		b.Run(fmt.Sprintf("%d", n), func(b *testing.B) {
+			b.SetBytes(int64(n))
+			for i := 0; i < b.N; i++ {
+				_ = Sum256(bufIn[:n])
+			}
+		})
+	}
+}
+
+func Benchmark64AppendNoAlloc(b *testing.B) {
+	b.SetBytes(64)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = AppendSum256(bufOut[0:0], bufIn[:64])
+	}
+}
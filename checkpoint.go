@@ -0,0 +1,248 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// DefaultCheckpointInterval is the number of bytes SumFileResumable hashes
+// between checkpoints when no interval is given.
+const DefaultCheckpointInterval = 1 << 30
+
+var (
+	// ErrStaleCheckpoint is returned by SumFileResumable when the checkpoint
+	// was written for a different file, for a file of a different size or
+	// modification time, or for a different hash.
+	ErrStaleCheckpoint = errors.New("blake256: checkpoint does not match the file")
+
+	// ErrInvalidCheckpoint is returned by SumFileResumable when the
+	// checkpoint file is corrupt.
+	ErrInvalidCheckpoint = errors.New("blake256: invalid checkpoint")
+)
+
+// ResumableOptions configures SumFileResumable. The zero value computes a
+// BLAKE-256 checksum without salt.
+type ResumableOptions struct {
+	// Size224 selects BLAKE-224 instead of BLAKE-256.
+	Size224 bool
+
+	// Salt is the optional 16-byte salt.
+	Salt []byte
+
+	// Interval is the number of bytes hashed between checkpoints. It
+	// defaults to DefaultCheckpointInterval.
+	Interval int64
+
+	// Checkpoint, if set, is called with the file offset after every
+	// checkpoint written.
+	Checkpoint func(offset int64)
+}
+
+const (
+	checkpointMagic = "blkc\x02"
+
+	// checkpointMinSize is the size of a checkpoint with an empty path.
+	checkpointMinSize = len(checkpointMagic) + 6*8 + marshaledSize + Size
+)
+
+// checkpoint is the progress of hashing a file as saved on disk.
+type checkpoint struct {
+	path    string // absolute path of the file
+	dev     uint64 // device number of the file, zero if unavailable
+	ino     uint64 // inode number of the file, zero if unavailable
+	size    int64  // file size
+	modTime int64  // file modification time in nanoseconds since the epoch
+	offset  int64  // number of bytes hashed
+	state   []byte
+}
+
+// sameFile reports whether c and saved were made for the same file in the
+// same state.
+func (c *checkpoint) sameFile(saved *checkpoint) bool {
+	return c.path == saved.path && c.dev == saved.dev && c.ino == saved.ino &&
+		c.size == saved.size && c.modTime == saved.modTime
+}
+
+// marshal encodes the checkpoint followed by its own BLAKE-256 checksum so
+// that torn writes are detected.
+func (c *checkpoint) marshal() []byte {
+	b := make([]byte, 0, checkpointMinSize+len(c.path))
+	b = append(b, checkpointMagic...)
+	b = appendUint64(b, c.dev)
+	b = appendUint64(b, c.ino)
+	b = appendUint64(b, uint64(c.size))
+	b = appendUint64(b, uint64(c.modTime))
+	b = appendUint64(b, uint64(c.offset))
+	b = append(b, c.state...)
+	b = appendUint64(b, uint64(len(c.path)))
+	b = append(b, c.path...)
+	sum := Sum256(b)
+	return append(b, sum[:]...)
+}
+
+func (c *checkpoint) unmarshal(b []byte) error {
+	if len(b) < checkpointMinSize || string(b[:len(checkpointMagic)]) != checkpointMagic {
+		return ErrInvalidCheckpoint
+	}
+	sum := Sum256(b[:len(b)-Size])
+	if !bytes.Equal(sum[:], b[len(b)-Size:]) {
+		return ErrInvalidCheckpoint
+	}
+	b = b[len(checkpointMagic) : len(b)-Size]
+	b, c.dev = consumeUint64(b)
+	b, c.ino = consumeUint64(b)
+	var v uint64
+	b, v = consumeUint64(b)
+	c.size = int64(v)
+	b, v = consumeUint64(b)
+	c.modTime = int64(v)
+	b, v = consumeUint64(b)
+	c.offset = int64(v)
+	c.state = append([]byte(nil), b[:marshaledSize]...)
+	b, v = consumeUint64(b[marshaledSize:])
+	if v != uint64(len(b)) {
+		return ErrInvalidCheckpoint
+	}
+	c.path = string(b)
+	return nil
+}
+
+// writeCheckpoint atomically replaces the checkpoint file at path.
+func writeCheckpoint(path string, c *checkpoint) error {
+	tmp := path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(c.marshal())
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// SumFileResumable returns the checksum of the named file, periodically saving
+// its progress to checkpointPath. If a checkpoint from an earlier run exists,
+// hashing resumes from it rather than from the start of the file. A checkpoint
+// written for a different file, for a file of a different size or
+// modification time, or with different options, is refused with
+// ErrStaleCheckpoint. Files are identified by their absolute path and, on
+// Unix, by their device and inode numbers, so a file replaced by another one
+// of the same size and modification time is not resumed. The checkpoint is
+// removed once the checksum is computed.
+//
+// When ctx is done, a checkpoint is written and the context error is returned.
+// A nil opts is the same as the zero ResumableOptions.
+//
+// It panics if the salt is not 16 bytes, like NewSalt.
+func SumFileResumable(ctx context.Context, path, checkpointPath string, opts *ResumableOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &ResumableOptions{}
+	}
+	d := digest{hashSize: 256}
+	if opts.Size224 {
+		d.hashSize = 224
+	}
+	d.Reset()
+	if opts.Salt != nil {
+		d.setSalt(opts.Salt)
+	}
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = DefaultCheckpointInterval
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	c := checkpoint{path: abs, size: fi.Size(), modTime: fi.ModTime().UnixNano()}
+	c.dev, c.ino = fileID(fi)
+
+	b, err := os.ReadFile(checkpointPath)
+	switch {
+	case err == nil:
+		var saved checkpoint
+		if err := saved.unmarshal(b); err != nil {
+			return nil, err
+		}
+		if !c.sameFile(&saved) {
+			return nil, ErrStaleCheckpoint
+		}
+		resumed := d
+		if err := resumed.UnmarshalBinary(saved.state); err != nil {
+			return nil, ErrStaleCheckpoint
+		}
+		if resumed.s != d.s || int64(resumed.length()) != saved.offset ||
+			saved.offset > c.size {
+			return nil, ErrStaleCheckpoint
+		}
+		if _, err := f.Seek(saved.offset, io.SeekStart); err != nil {
+			return nil, err
+		}
+		d = resumed
+		c.offset = saved.offset
+	case !os.IsNotExist(err):
+		return nil, err
+	}
+
+	save := func() error {
+		c.state, _ = d.MarshalBinary()
+		if err := writeCheckpoint(checkpointPath, &c); err != nil {
+			return err
+		}
+		if opts.Checkpoint != nil {
+			opts.Checkpoint(c.offset)
+		}
+		return nil
+	}
+	for {
+		if err := ctx.Err(); err != nil {
+			if serr := save(); serr != nil {
+				return nil, serr
+			}
+			return nil, err
+		}
+		n, err := d.ReadFrom(io.LimitReader(f, interval))
+		c.offset += n
+		if err != nil {
+			return nil, err
+		}
+		if n < interval {
+			break
+		}
+		if err := save(); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := os.Remove(checkpointPath); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	sum := d.checkSum()
+	return sum[:d.Size()], nil
+}
@@ -0,0 +1,170 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSumFileResumable(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data")
+	ckpt := filepath.Join(dir, "data.ckpt")
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	want := Sum256(data)
+
+	// Interrupt after the second checkpoint.
+	ctx, cancel := context.WithCancel(context.Background())
+	var offsets []int64
+	opts := &ResumableOptions{
+		Interval: 1000,
+		Checkpoint: func(offset int64) {
+			offsets = append(offsets, offset)
+			if len(offsets) == 2 {
+				cancel()
+			}
+		},
+	}
+	if _, err := SumFileResumable(ctx, path, ckpt, opts); err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	// The checkpoint is rewritten on cancellation.
+	if len(offsets) != 3 || offsets[2] != 2000 {
+		t.Fatalf("unexpected checkpoint offsets %v", offsets)
+	}
+
+	// Resume from the checkpoint.
+	offsets = nil
+	opts.Checkpoint = func(offset int64) { offsets = append(offsets, offset) }
+	sum, err := SumFileResumable(context.Background(), path, ckpt, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(sum, want[:]) {
+		t.Errorf("expected %x, got %x", want, sum)
+	}
+	if len(offsets) == 0 || offsets[0] != 3000 {
+		t.Errorf("expected to resume after offset 2000, got checkpoints %v", offsets)
+	}
+	if _, err := os.Stat(ckpt); !os.IsNotExist(err) {
+		t.Errorf("expected checkpoint to be removed, got %v", err)
+	}
+}
+
+func TestSumFileResumableStale(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "data")
+	ckpt := filepath.Join(dir, "data.ckpt")
+	if err := os.WriteFile(path, make([]byte, 5000), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// Leave a checkpoint behind.
+	ctx, cancel := context.WithCancel(context.Background())
+	opts := &ResumableOptions{
+		Interval:   1000,
+		Checkpoint: func(int64) { cancel() },
+	}
+	if _, err := SumFileResumable(ctx, path, ckpt, opts); err != context.Canceled {
+		t.Fatalf("expected %v, got %v", context.Canceled, err)
+	}
+	saved, err := os.ReadFile(ckpt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts.Checkpoint = nil
+
+	// Different hash options.
+	opts224 := &ResumableOptions{Size224: true, Interval: 1000}
+	if _, err := SumFileResumable(context.Background(), path, ckpt, opts224); err != ErrStaleCheckpoint {
+		t.Errorf("expected %v for different hash size, got %v", ErrStaleCheckpoint, err)
+	}
+	optsSalt := &ResumableOptions{Salt: make([]byte, 16), Interval: 1000}
+	optsSalt.Salt[0] = 1
+	if _, err := SumFileResumable(context.Background(), path, ckpt, optsSalt); err != ErrStaleCheckpoint {
+		t.Errorf("expected %v for different salt, got %v", ErrStaleCheckpoint, err)
+	}
+
+	// Corrupt checkpoint.
+	corrupt := append([]byte(nil), saved...)
+	corrupt[len(corrupt)/2] ^= 1
+	if err := os.WriteFile(ckpt, corrupt, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SumFileResumable(context.Background(), path, ckpt, opts); err != ErrInvalidCheckpoint {
+		t.Errorf("expected %v, got %v", ErrInvalidCheckpoint, err)
+	}
+
+	// The same file under another path.
+	if err := os.WriteFile(ckpt, saved, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	moved := filepath.Join(dir, "moved")
+	if err := os.Rename(path, moved); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SumFileResumable(context.Background(), moved, ckpt, opts); err != ErrStaleCheckpoint {
+		t.Errorf("expected %v for moved file, got %v", ErrStaleCheckpoint, err)
+	}
+	if err := os.Rename(moved, path); err != nil {
+		t.Fatal(err)
+	}
+
+	// A different file of the same size and modification time swapped in.
+	if haveFileID {
+		fi, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		other := filepath.Join(dir, "other")
+		if err := os.WriteFile(other, bytes.Repeat([]byte{1}, 5000), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(other, fi.ModTime(), fi.ModTime()); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(other, path); err != nil {
+			t.Fatal(err)
+		}
+		if fi2, err := os.Stat(path); err != nil || fi2.Size() != fi.Size() ||
+			!fi2.ModTime().Equal(fi.ModTime()) {
+			t.Fatalf("swapped file differs in size or mtime: %v", err)
+		}
+		if _, err := SumFileResumable(context.Background(), path, ckpt, opts); err != ErrStaleCheckpoint {
+			t.Errorf("expected %v for replaced file, got %v", ErrStaleCheckpoint, err)
+		}
+	}
+
+	// Changed modification time.
+	if err := os.WriteFile(ckpt, saved, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SumFileResumable(context.Background(), path, ckpt, opts); err != ErrStaleCheckpoint {
+		t.Errorf("expected %v for changed mtime, got %v", ErrStaleCheckpoint, err)
+	}
+
+	// Changed size.
+	if err := os.WriteFile(path, make([]byte, 6000), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SumFileResumable(context.Background(), path, ckpt, opts); err != ErrStaleCheckpoint {
+		t.Errorf("expected %v for changed size, got %v", ErrStaleCheckpoint, err)
+	}
+}
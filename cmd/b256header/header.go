@@ -0,0 +1,191 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/rickiey/blake256/getwork"
+	"github.com/rickiey/blake256/wire"
+)
+
+// atomsPerCoin is the number of atoms in one DCR.
+const atomsPerCoin = 1e8
+
+// errUnknownFormat is returned when the input format cannot be detected.
+var errUnknownFormat = errors.New("unrecognized header format")
+
+// headerJSON is the JSON form of a header, with the field names and units of
+// the verbose getblockheader RPC result.
+type headerJSON struct {
+	Hash          string  `json:"hash,omitempty"`
+	Version       int32   `json:"version"`
+	PreviousBlock string  `json:"previousblockhash"`
+	MerkleRoot    string  `json:"merkleroot"`
+	StakeRoot     string  `json:"stakeroot"`
+	VoteBits      uint16  `json:"votebits"`
+	FinalState    string  `json:"finalstate"`
+	Voters        uint16  `json:"voters"`
+	FreshStake    uint8   `json:"freshstake"`
+	Revocations   uint8   `json:"revocations"`
+	PoolSize      uint32  `json:"poolsize"`
+	Bits          string  `json:"bits"`
+	SBits         float64 `json:"sbits"`
+	Height        uint32  `json:"height"`
+	Size          uint32  `json:"size"`
+	Time          int64   `json:"time"`
+	Nonce         uint32  `json:"nonce"`
+	ExtraData     string  `json:"extradata"`
+	StakeVersion  uint32  `json:"stakeversion"`
+}
+
+// newHeaderJSON returns the JSON form of a header.
+func newHeaderJSON(h *wire.BlockHeader) *headerJSON {
+	return &headerJSON{
+		Hash:          h.BlockHash().String(),
+		Version:       h.Version,
+		PreviousBlock: h.PrevBlock.String(),
+		MerkleRoot:    h.MerkleRoot.String(),
+		StakeRoot:     h.StakeRoot.String(),
+		VoteBits:      h.VoteBits,
+		FinalState:    hex.EncodeToString(h.FinalState[:]),
+		Voters:        h.Voters,
+		FreshStake:    h.FreshStake,
+		Revocations:   h.Revocations,
+		PoolSize:      h.PoolSize,
+		Bits:          fmt.Sprintf("%08x", h.Bits),
+		SBits:         float64(h.SBits) / atomsPerCoin,
+		Height:        h.Height,
+		Size:          h.Size,
+		Time:          h.Timestamp.Unix(),
+		Nonce:         h.Nonce,
+		ExtraData:     hex.EncodeToString(h.ExtraData[:]),
+		StakeVersion:  h.StakeVersion,
+	}
+}
+
+// decodeFixedHex decodes s into dst, which it must fill exactly.
+func decodeFixedHex(dst []byte, s, field string) error {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return fmt.Errorf("%s: %w", field, err)
+	}
+	if len(b) != len(dst) {
+		return fmt.Errorf("%s: %d bytes, want %d", field, len(b), len(dst))
+	}
+	copy(dst, b)
+	return nil
+}
+
+// header returns the header described by j. The hash, if set, is not
+// checked.
+func (j *headerJSON) header() (*wire.BlockHeader, error) {
+	h := &wire.BlockHeader{
+		Version:      j.Version,
+		VoteBits:     j.VoteBits,
+		Voters:       j.Voters,
+		FreshStake:   j.FreshStake,
+		Revocations:  j.Revocations,
+		PoolSize:     j.PoolSize,
+		SBits:        int64(math.Round(j.SBits * atomsPerCoin)),
+		Height:       j.Height,
+		Size:         j.Size,
+		Timestamp:    time.Unix(j.Time, 0),
+		Nonce:        j.Nonce,
+		StakeVersion: j.StakeVersion,
+	}
+	hashes := []struct {
+		dst   *wire.Hash
+		s     string
+		field string
+	}{
+		{&h.PrevBlock, j.PreviousBlock, "previousblockhash"},
+		{&h.MerkleRoot, j.MerkleRoot, "merkleroot"},
+		{&h.StakeRoot, j.StakeRoot, "stakeroot"},
+	}
+	for _, f := range hashes {
+		hash, err := wire.NewHashFromStr(f.s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.field, err)
+		}
+		*f.dst = *hash
+	}
+	bits, err := strconv.ParseUint(j.Bits, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("bits: %w", err)
+	}
+	h.Bits = uint32(bits)
+	if err := decodeFixedHex(h.FinalState[:], j.FinalState, "finalstate"); err != nil {
+		return nil, err
+	}
+	if err := decodeFixedHex(h.ExtraData[:], j.ExtraData, "extradata"); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// parseHeader decodes a header in the given format: "raw" for the 180
+// serialized bytes, "hex" for their hex encoding, "json" for the fields of
+// the getblockheader RPC result, or "auto" to detect the format. Raw and hex
+// input may also be 192-byte getwork data.
+//
+// For JSON input with a hash, the returned hash is the one given so it can be
+// compared against the computed hash. Otherwise it is empty.
+func parseHeader(input []byte, format string) (*wire.BlockHeader, string, error) {
+	if format == "auto" {
+		trimmed := bytes.TrimSpace(input)
+		switch {
+		case len(input) == wire.MaxBlockHeaderPayload ||
+			len(input) == getwork.DataSize:
+			format = "raw"
+		case len(trimmed) > 0 && trimmed[0] == '{':
+			format = "json"
+		case len(trimmed) == 2*wire.MaxBlockHeaderPayload ||
+			len(trimmed) == 2*getwork.DataSize:
+			format = "hex"
+		default:
+			return nil, "", fmt.Errorf("%w: %d bytes", errUnknownFormat, len(input))
+		}
+	}
+
+	switch format {
+	case "raw":
+		return parseRaw(input)
+	case "hex":
+		raw, err := hex.DecodeString(string(bytes.TrimSpace(input)))
+		if err != nil {
+			return nil, "", err
+		}
+		return parseRaw(raw)
+	case "json":
+		var j headerJSON
+		if err := json.Unmarshal(input, &j); err != nil {
+			return nil, "", err
+		}
+		h, err := j.header()
+		return h, j.Hash, err
+	}
+	return nil, "", fmt.Errorf("%w: %q", errUnknownFormat, format)
+}
+
+// parseRaw decodes a serialized header or getwork data.
+func parseRaw(raw []byte) (*wire.BlockHeader, string, error) {
+	if len(raw) == getwork.DataSize {
+		h, err := getwork.DecodeData(raw)
+		return h, "", err
+	}
+	var h wire.BlockHeader
+	if err := h.FromBytes(raw); err != nil {
+		return nil, "", err
+	}
+	return &h, "", nil
+}
@@ -0,0 +1,135 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rickiey/blake256/getwork"
+	"github.com/rickiey/blake256/wire"
+)
+
+// genesisHash is the hash of the mainnet genesis header.
+const genesisHash = "298e5cc3d985bfe7f81dc135f360abe089edd4396b86d2de66b0cef42b21d980"
+
+// testHeader returns a header with every field set.
+func testHeader() *wire.BlockHeader {
+	h := &wire.BlockHeader{
+		Version:      9,
+		VoteBits:     0x0005,
+		FinalState:   [6]byte{1, 2, 3, 4, 5, 6},
+		Voters:       5,
+		FreshStake:   7,
+		Revocations:  1,
+		PoolSize:     41000,
+		Bits:         0x1a0c1a7e,
+		SBits:        14367214523,
+		Height:       500000,
+		Size:         12345,
+		Timestamp:    time.Unix(1600000000, 0),
+		Nonce:        0xdeadbeef,
+		StakeVersion: 9,
+	}
+	h.PrevBlock[0] = 0xaa
+	h.MerkleRoot[1] = 0xbb
+	h.StakeRoot[2] = 0xcc
+	h.ExtraData[3] = 0xdd
+	return h
+}
+
+// genesisHex returns the hex encoding of the mainnet genesis header.
+func genesisHex() string {
+	merkle, err := wire.NewHashFromStr("66aa7491b9adce110585ccab7e3fb5fe280de174530cca10eba2c6c3df01c10d")
+	if err != nil {
+		panic(err)
+	}
+	h := &wire.BlockHeader{
+		Version:    1,
+		MerkleRoot: *merkle,
+		Bits:       0x1b01ffff,
+		SBits:      2 * 1e8,
+		Timestamp:  time.Unix(1454954400, 0),
+	}
+	return hex.EncodeToString(h.Bytes())
+}
+
+func TestParseHeaderFormats(t *testing.T) {
+	want := testHeader()
+	raw := want.Bytes()
+	data := getwork.EncodeData(want)
+	js, err := json.Marshal(newHeaderJSON(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		input  []byte
+		format string
+	}{
+		{"raw", raw, "auto"},
+		{"raw explicit", raw, "raw"},
+		{"hex", []byte(hex.EncodeToString(raw) + "\n"), "auto"},
+		{"hex explicit", []byte(hex.EncodeToString(raw)), "hex"},
+		{"getwork raw", data[:], "auto"},
+		{"getwork hex", []byte(hex.EncodeToString(data[:])), "auto"},
+		{"json", js, "auto"},
+		{"json explicit", js, "json"},
+	}
+	for _, test := range tests {
+		got, _, err := parseHeader(test.input, test.format)
+		if err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		if *got != *want {
+			t.Errorf("%s: expected %+v, got %+v", test.name, *want, *got)
+		}
+	}
+}
+
+func TestParseHeaderJSONHash(t *testing.T) {
+	_, hash, err := parseHeader([]byte(`{"hash": "abcd", "bits": "1b01ffff",
+		"finalstate": "000000000000", "extradata": "`+strings.Repeat("00", 32)+`"}`), "auto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != "abcd" {
+		t.Fatalf("expected hash %q, got %q", "abcd", hash)
+	}
+}
+
+func TestParseHeaderErrors(t *testing.T) {
+	extra := strings.Repeat("00", 32)
+	tests := []struct {
+		name   string
+		input  string
+		format string
+	}{
+		{"short", "abcd", "auto"},
+		{"bad format", genesisHex(), "xml"},
+		{"bad hex", strings.Repeat("zz", wire.MaxBlockHeaderPayload), "auto"},
+		{"short raw", "abcd", "raw"},
+		{"bad json", "{", "auto"},
+		{"bad bits", `{"bits": "xyz", "finalstate": "000000000000", "extradata": "` + extra + `"}`, "json"},
+		{"bad final state", `{"bits": "1b01ffff", "finalstate": "00", "extradata": "` + extra + `"}`, "json"},
+		{"bad extra data", `{"bits": "1b01ffff", "finalstate": "000000000000", "extradata": "00"}`, "json"},
+		{"bad prev block", `{"previousblockhash": "zz", "bits": "1b01ffff", "finalstate": "000000000000", "extradata": "` + extra + `"}`, "json"},
+	}
+	for _, test := range tests {
+		if _, _, err := parseHeader([]byte(test.input), test.format); err == nil {
+			t.Errorf("%s: expected error", test.name)
+		}
+	}
+	_, _, err := parseHeader([]byte("abcd"), "auto")
+	if !errors.Is(err, errUnknownFormat) {
+		t.Errorf("expected %v, got %v", errUnknownFormat, err)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Command b256header decodes a Decred block header, prints its hash and
+// fields, and checks its proof of work.
+//
+// The header is read from the named file or standard input as 180 raw
+// bytes, their hex encoding, 192-byte getwork data, or the JSON result of
+// the getblockheader RPC. The -nonce and -timestamp flags recompute the hash
+// with those fields changed, which helps when debugging rejected blocks.
+//
+// The exit status is 1 when the proof of work is invalid or a hash given in
+// JSON input does not match, and 2 on other errors. With -json, the results
+// of these checks are printed to standard error.
+//
+// Usage:
+//
+//	b256header [flags] [file]
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/rickiey/blake256/pow"
+	"github.com/rickiey/blake256/wire"
+)
+
+// errCheckFailed is returned when a header fails its checks. The details
+// have already been printed.
+var errCheckFailed = errors.New("header check failed")
+
+// powLimits maps network names to their proof-of-work limits.
+var powLimits = map[string]*pow.Uint256{
+	"mainnet":  &pow.MainNetPowLimit,
+	"testnet3": &pow.TestNet3PowLimit,
+	"simnet":   &pow.SimNetPowLimit,
+	"regnet":   &pow.SimNetPowLimit,
+}
+
+func main() {
+	err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr)
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		os.Exit(0)
+	case errors.Is(err, errCheckFailed):
+		os.Exit(1)
+	case err != nil:
+		fmt.Fprintf(os.Stderr, "b256header: %v\n", err)
+		os.Exit(2)
+	}
+}
+
+// run executes the command with the given arguments.
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := flag.NewFlagSet("b256header", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	var (
+		format    = fs.String("format", "auto", "input format: auto, raw, hex or json")
+		network   = fs.String("net", "mainnet", "network for the proof-of-work limit: mainnet, testnet3, simnet or regnet")
+		nonce     = fs.String("nonce", "", "recompute the hash with this nonce")
+		timestamp = fs.String("timestamp", "", "recompute the hash with this timestamp, in Unix seconds or RFC 3339")
+		asJSON    = fs.Bool("json", false, "print the header as getblockheader JSON")
+	)
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: b256header [flags] [file]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	powLimit, ok := powLimits[*network]
+	if !ok {
+		return fmt.Errorf("unknown network %q", *network)
+	}
+
+	var input []byte
+	var err error
+	switch fs.NArg() {
+	case 0:
+		input, err = io.ReadAll(stdin)
+	case 1:
+		if fs.Arg(0) == "-" {
+			input, err = io.ReadAll(stdin)
+		} else {
+			input, err = os.ReadFile(fs.Arg(0))
+		}
+	default:
+		fs.Usage()
+		return errors.New("too many arguments")
+	}
+	if err != nil {
+		return err
+	}
+
+	header, givenHash, err := parseHeader(input, *format)
+	if err != nil {
+		return fmt.Errorf("decode header: %w", err)
+	}
+
+	// In JSON mode the check results go to stderr so that stdout holds only
+	// the JSON.
+	report := stdout
+	failed := false
+	if *asJSON {
+		enc := json.NewEncoder(stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(newHeaderJSON(header)); err != nil {
+			return err
+		}
+		report = stderr
+		failed = !checkPoW(report, header, powLimit)
+	} else {
+		failed = !describe(stdout, header, powLimit)
+	}
+	if givenHash != "" && givenHash != header.BlockHash().String() {
+		fmt.Fprintf(report, "hash mismatch: input hash %s\n", givenHash)
+		failed = true
+	}
+
+	if *nonce != "" || *timestamp != "" {
+		modified := *header
+		if *nonce != "" {
+			n, err := strconv.ParseUint(*nonce, 0, 32)
+			if err != nil {
+				return fmt.Errorf("invalid -nonce: %w", err)
+			}
+			modified.Nonce = uint32(n)
+		}
+		if *timestamp != "" {
+			ts, err := parseTimestamp(*timestamp)
+			if err != nil {
+				return fmt.Errorf("invalid -timestamp: %w", err)
+			}
+			modified.Timestamp = ts
+		}
+		fmt.Fprintf(report, "\nwith nonce %d and timestamp %s:\n", modified.Nonce,
+			formatTime(modified.Timestamp))
+		fmt.Fprintf(report, "%-14s %v\n", "hash", modified.BlockHash())
+		if !checkPoW(report, &modified, powLimit) {
+			failed = true
+		}
+	}
+
+	if failed {
+		return errCheckFailed
+	}
+	return nil
+}
+
+// parseTimestamp parses Unix seconds or an RFC 3339 time.
+func parseTimestamp(s string) (time.Time, error) {
+	if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return time.Unix(int64(n), 0), nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
+// formatTime formats a header timestamp as Unix seconds and UTC time.
+func formatTime(t time.Time) string {
+	return fmt.Sprintf("%d (%s)", t.Unix(), t.UTC().Format(time.RFC3339))
+}
+
+// describe prints the hash and fields of a header and checks its proof of
+// work. It reports whether the check passed.
+func describe(w io.Writer, h *wire.BlockHeader, powLimit *pow.Uint256) bool {
+	target, _, _ := pow.CompactToTarget(h.Bits)
+	work := pow.CalcWork(h.Bits)
+	field := func(name, format string, args ...interface{}) {
+		fmt.Fprintf(w, "%-14s "+format+"\n", append([]interface{}{name}, args...)...)
+	}
+	field("hash", "%v", h.BlockHash())
+	field("version", "%d", h.Version)
+	field("prev block", "%v", h.PrevBlock)
+	field("merkle root", "%v", h.MerkleRoot)
+	field("stake root", "%v", h.StakeRoot)
+	field("vote bits", "%#04x", h.VoteBits)
+	field("final state", "%x", h.FinalState)
+	field("voters", "%d", h.Voters)
+	field("fresh stake", "%d", h.FreshStake)
+	field("revocations", "%d", h.Revocations)
+	field("pool size", "%d", h.PoolSize)
+	field("bits", "%08x", h.Bits)
+	field("target", "%v", target)
+	field("work", "%v", work)
+	field("sbits", "%d (%.8f DCR)", h.SBits, float64(h.SBits)/atomsPerCoin)
+	field("height", "%d", h.Height)
+	field("size", "%d", h.Size)
+	field("timestamp", "%s", formatTime(h.Timestamp))
+	field("nonce", "%d (%#08x)", h.Nonce, h.Nonce)
+	field("extra data", "%x", h.ExtraData)
+	field("stake version", "%d", h.StakeVersion)
+	return checkPoW(w, h, powLimit)
+}
+
+// checkPoW prints the result of checking the proof of work of a header and
+// reports whether it passed.
+func checkPoW(w io.Writer, h *wire.BlockHeader, powLimit *pow.Uint256) bool {
+	if err := pow.CheckHeaderProofOfWork(h, powLimit); err != nil {
+		fmt.Fprintf(w, "%-14s FAIL: %v\n", "proof of work", err)
+		return false
+	}
+	fmt.Fprintf(w, "%-14s ok\n", "proof of work")
+	return true
+}
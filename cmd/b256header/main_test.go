@@ -0,0 +1,160 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/rickiey/blake256/pow"
+)
+
+func TestRunGenesis(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	err := run(nil, strings.NewReader(genesisHex()), &stdout, &stderr)
+
+	// The genesis block was not mined and fails its proof of work.
+	if !errors.Is(err, errCheckFailed) {
+		t.Fatalf("expected %v, got %v", errCheckFailed, err)
+	}
+	out := stdout.String()
+	for _, want := range []string{
+		"hash           " + genesisHash + "\n",
+		"merkle root    66aa7491b9adce110585ccab7e3fb5fe280de174530cca10eba2c6c3df01c10d\n",
+		"bits           1b01ffff\n",
+		"sbits          200000000 (2.00000000 DCR)\n",
+		"timestamp      1454954400 (2016-02-08T18:00:00Z)\n",
+		"proof of work  FAIL: pow: block hash above target",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestRunRecompute(t *testing.T) {
+	// Find a nonce that meets the simnet limit for a header that does not.
+	h := testHeader()
+	h.Bits = 0x207fffff
+	for pow.CheckHeaderProofOfWork(h, &pow.SimNetPowLimit) == nil {
+		h.Nonce++
+	}
+	bad := *h
+	for pow.CheckHeaderProofOfWork(h, &pow.SimNetPowLimit) != nil {
+		h.Nonce++
+	}
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "header.hex")
+	if err := os.WriteFile(path, []byte(hex.EncodeToString(bad.Bytes())), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	args := []string{"-net", "simnet", "-nonce", fmt.Sprintf("%#x", h.Nonce),
+		"-timestamp", "1600000000", path}
+	err := run(args, nil, &stdout, &stderr)
+	if !errors.Is(err, errCheckFailed) {
+		t.Fatalf("expected %v, got %v", errCheckFailed, err)
+	}
+	out := stdout.String()
+	want := "\nwith nonce " + strconv.FormatUint(uint64(h.Nonce), 10) +
+		" and timestamp 1600000000 (2020-09-13T12:26:40Z):\nhash           " +
+		h.BlockHash().String() + "\nproof of work  ok\n"
+	if !strings.HasSuffix(out, want) {
+		t.Fatalf("expected output ending in %q, got:\n%s", want, out)
+	}
+
+	// The solved header passes on its own.
+	stdout.Reset()
+	err = run([]string{"-net", "simnet", "-format", "raw"}, bytes.NewReader(h.Bytes()),
+		&stdout, &stderr)
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, stdout.String())
+	}
+}
+
+func TestRunJSON(t *testing.T) {
+	h := testHeader()
+	var js bytes.Buffer
+	err := run([]string{"-json", "-format", "raw"}, bytes.NewReader(h.Bytes()), &js,
+		&bytes.Buffer{})
+	if err != nil && !errors.Is(err, errCheckFailed) {
+		t.Fatal(err)
+	}
+	if !strings.Contains(js.String(), `"hash": "`+h.BlockHash().String()+`"`) {
+		t.Fatalf("JSON output missing hash:\n%s", js.String())
+	}
+
+	// Feeding the JSON back decodes the same header and hash.
+	var out bytes.Buffer
+	err = run([]string{"-net", "simnet"}, &js, &out, &bytes.Buffer{})
+	if err != nil && !errors.Is(err, errCheckFailed) {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "hash           "+h.BlockHash().String()) {
+		t.Fatalf("unexpected output:\n%s", out.String())
+	}
+	if strings.Contains(out.String(), "hash mismatch") {
+		t.Fatalf("unexpected hash mismatch:\n%s", out.String())
+	}
+
+	// A wrong hash in the input is reported.
+	in := `{"hash": "` + genesisHash + `", "bits": "1b01ffff", "finalstate": "000000000000",
+		"extradata": "` + strings.Repeat("00", 32) + `"}`
+	out.Reset()
+	err = run(nil, strings.NewReader(in), &out, &bytes.Buffer{})
+	if !errors.Is(err, errCheckFailed) || !strings.Contains(out.String(), "hash mismatch") {
+		t.Fatalf("expected hash mismatch, got %v:\n%s", err, out.String())
+	}
+}
+
+func TestRunJSONProofOfWork(t *testing.T) {
+	// The genesis block fails its proof of work, which is checked and
+	// reported on stderr in JSON mode too.
+	var stdout, stderr bytes.Buffer
+	err := run([]string{"-json"}, strings.NewReader(genesisHex()), &stdout, &stderr)
+	if !errors.Is(err, errCheckFailed) {
+		t.Fatalf("expected %v, got %v", errCheckFailed, err)
+	}
+	var js headerJSON
+	if err := json.Unmarshal(stdout.Bytes(), &js); err != nil {
+		t.Fatalf("invalid JSON output: %v\n%s", err, stdout.String())
+	}
+	if js.Hash != genesisHash {
+		t.Errorf("expected hash %s, got %s", genesisHash, js.Hash)
+	}
+	if want := "proof of work  FAIL: pow: block hash above target"; !strings.Contains(stderr.String(), want) {
+		t.Errorf("stderr missing %q:\n%s", want, stderr.String())
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"bad net", []string{"-net", "foo"}},
+		{"bad nonce", []string{"-nonce", "x"}},
+		{"bad timestamp", []string{"-timestamp", "yesterday"}},
+		{"missing file", []string{filepath.Join(t.TempDir(), "missing")}},
+		{"too many args", []string{"a", "b"}},
+	}
+	for _, test := range tests {
+		err := run(test.args, strings.NewReader(genesisHex()), &bytes.Buffer{},
+			&bytes.Buffer{})
+		if err == nil || errors.Is(err, errCheckFailed) {
+			t.Errorf("%s: expected usage error, got %v", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Command b256miner is a multi-threaded CPU miner for Decred's getwork
+// JSON-RPC interface.
+//
+// Each thread searches nonces with the BLAKE-256 midstate of the work unit
+// and its own range of extra nonces. New work is fetched periodically and
+// after each solution. With -mock the miner runs against an in-process node
+// at simnet difficulty, which exercises the full fetch, solve and submit
+// loop without a real node:
+//
+//	b256miner -mock -blocks 5
+//
+// Usage:
+//
+//	b256miner [flags]
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/rickiey/blake256/pow"
+)
+
+func main() {
+	var (
+		server   = flag.String("rpcserver", "http://127.0.0.1:19556", "JSON-RPC server URL")
+		user     = flag.String("rpcuser", "", "RPC user name")
+		pass     = flag.String("rpcpass", "", "RPC password")
+		cert     = flag.String("rpccert", "", "PEM certificate of the RPC server for https")
+		threads  = flag.Int("threads", runtime.NumCPU(), "number of mining threads")
+		poll     = flag.Duration("poll", 5*time.Second, "interval between checks for new work")
+		stats    = flag.Duration("stats", 10*time.Second, "interval between hash rate reports")
+		blocks   = flag.Int("blocks", 0, "exit after this many accepted blocks (0 to mine forever)")
+		mock     = flag.Bool("mock", false, "mine against an in-process mock node at simnet difficulty")
+		mockBits = flag.String("mockbits", "1f00ffff", "compact target bits of the mock node in hex")
+	)
+	flag.Parse()
+	if *threads < 1 {
+		fmt.Fprintln(os.Stderr, "b256miner: -threads must be positive")
+		os.Exit(2)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer cancel()
+
+	httpClient := http.DefaultClient
+	if *cert != "" {
+		pem, err := os.ReadFile(*cert)
+		if err != nil {
+			log.Fatal(err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			log.Fatalf("no certificates in %s", *cert)
+		}
+		httpClient = &http.Client{Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{RootCAs: pool},
+		}}
+	}
+
+	if *mock {
+		bits, err := strconv.ParseUint(*mockBits, 16, 32)
+		if err != nil {
+			log.Fatalf("invalid -mockbits: %v", err)
+		}
+		if err := pow.CheckProofOfWorkRange(uint32(bits), &pow.SimNetPowLimit); err != nil {
+			log.Fatalf("invalid -mockbits: %v", err)
+		}
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			log.Fatal(err)
+		}
+		node := newMockNode(*user, *pass, uint32(bits), &pow.SimNetPowLimit)
+		go http.Serve(ln, node)
+		*server = "http://" + ln.Addr().String()
+		log.Printf("mock node listening on %s", *server)
+	}
+
+	m := newMiner(newRPCClient(*server, *user, *pass, httpClient), *threads, *poll)
+	go m.report(ctx, *stats)
+	log.Printf("mining with %d threads against %s", *threads, *server)
+	n := m.run(ctx, *blocks)
+	log.Printf("%d blocks accepted", n)
+}
@@ -0,0 +1,193 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rickiey/blake256/getwork"
+	"github.com/rickiey/blake256/pow"
+)
+
+// batchSize is the number of nonces a thread searches between checks for
+// new work.
+const batchSize = 1 << 16
+
+// miner searches work units fetched from a getwork server on several
+// threads and submits the solutions.
+type miner struct {
+	client  *rpcClient
+	threads int
+	poll    time.Duration
+	hashes  []uint64 // per-thread hash counts, accessed atomically
+}
+
+// newMiner returns a miner using the given number of threads that checks
+// for new work every poll interval.
+func newMiner(client *rpcClient, threads int, poll time.Duration) *miner {
+	return &miner{
+		client:  client,
+		threads: threads,
+		poll:    poll,
+		hashes:  make([]uint64, threads),
+	}
+}
+
+// sameWork reports whether two work units differ only in their timestamp,
+// which servers refresh on every request.
+func sameWork(a, b *getwork.Work) bool {
+	return string(a.Data[:getwork.TimestampOffset]) ==
+		string(b.Data[:getwork.TimestampOffset]) &&
+		string(a.Data[getwork.NonceOffset:]) ==
+			string(b.Data[getwork.NonceOffset:]) &&
+		a.Target == b.Target
+}
+
+// run mines until ctx is done or, if maxBlocks is positive, maxBlocks
+// solutions have been accepted. It returns the number of accepted solutions.
+func (m *miner) run(ctx context.Context, maxBlocks int) int {
+	solutions := make(chan *getwork.Work)
+	var (
+		work   *getwork.Work
+		cancel = func() {}
+		wg     sync.WaitGroup
+	)
+	stop := func() {
+		cancel()
+		wg.Wait()
+	}
+	defer stop()
+
+	start := func(w *getwork.Work) {
+		stop()
+		var wctx context.Context
+		wctx, cancel = context.WithCancel(ctx)
+		for i := 0; i < m.threads; i++ {
+			wg.Add(1)
+			go func(id int) {
+				defer wg.Done()
+				m.solve(wctx, id, w, solutions)
+			}(i)
+		}
+		work = w
+	}
+
+	ticker := time.NewTicker(m.poll)
+	defer ticker.Stop()
+	accepted := 0
+	refresh := true
+	for {
+		if refresh {
+			refresh = false
+			w, err := m.client.getWork(ctx)
+			switch {
+			case ctx.Err() != nil:
+				return accepted
+			case err != nil:
+				log.Printf("getwork: %v", err)
+			case work == nil || !sameWork(work, w):
+				log.Printf("new work for block %d, target %x",
+					w.Header().Height, w.Target)
+				start(w)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return accepted
+		case <-ticker.C:
+			refresh = true
+		case sol := <-solutions:
+			header := sol.Header()
+			ok, err := m.client.submitWork(ctx, sol)
+			switch {
+			case ctx.Err() != nil:
+				return accepted
+			case err != nil:
+				log.Printf("submit block %d: %v", header.Height, err)
+			case !ok:
+				log.Printf("block %d rejected", header.Height)
+			default:
+				accepted++
+				log.Printf("block %d accepted: %v", header.Height, sol.Hash())
+				if maxBlocks > 0 && accepted >= maxBlocks {
+					return accepted
+				}
+			}
+			// Solved work is stale either way.
+			stop()
+			work = nil
+			refresh = true
+		}
+	}
+}
+
+// solve searches w on thread id until it finds a solution, which it sends on
+// out, or ctx is done. Each thread uses its own range of extra nonces so
+// that threads never search the same headers.
+func (m *miner) solve(ctx context.Context, id int, w *getwork.Work, out chan<- *getwork.Work) {
+	work := *w
+	for extra := uint64(id) << 32; ; extra++ {
+		work.SetExtraNonce(extra)
+		for start := uint64(0); start < 1<<32; start += batchSize {
+			if ctx.Err() != nil {
+				return
+			}
+			_, hashes, found := work.SearchNonces(uint32(start), batchSize)
+			atomic.AddUint64(&m.hashes[id], uint64(hashes))
+			if found {
+				select {
+				case out <- &work:
+				case <-ctx.Done():
+				}
+				return
+			}
+		}
+	}
+}
+
+// report logs the hash rate of each thread and the total every interval
+// until ctx is done.
+func (m *miner) report(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	last := make([]uint64, m.threads)
+	lastTime := time.Now()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			elapsed := now.Sub(lastTime)
+			lastTime = now
+			var total float64
+			for i := range last {
+				n := atomic.LoadUint64(&m.hashes[i])
+				delta := pow.NewUint256(n - last[i])
+				last[i] = n
+				rate := pow.HashesPerSecond(&delta, elapsed)
+				total += rate
+				log.Printf("thread %d: %s", i, formatHashRate(rate))
+			}
+			log.Printf("total: %s", formatHashRate(total))
+		}
+	}
+}
+
+// formatHashRate formats a hash rate with a metric prefix.
+func formatHashRate(rate float64) string {
+	units := []string{"H/s", "kH/s", "MH/s", "GH/s", "TH/s", "PH/s"}
+	i := 0
+	for rate >= 1000 && i < len(units)-1 {
+		rate /= 1000
+		i++
+	}
+	return fmt.Sprintf("%.2f %s", rate, units[i])
+}
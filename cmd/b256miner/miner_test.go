@@ -0,0 +1,90 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/rickiey/blake256/pow"
+)
+
+func TestMinerMockNode(t *testing.T) {
+	node := newMockNode("user", "pass", 0x1f00ffff, &pow.SimNetPowLimit)
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	m := newMiner(newRPCClient(srv.URL, "user", "pass", nil), 4, time.Second)
+	if n := m.run(ctx, 3); n != 3 {
+		t.Fatalf("expected 3 accepted blocks, got %d", n)
+	}
+
+	blocks := node.Blocks()
+	if len(blocks) != 3 {
+		t.Fatalf("expected 3 blocks, got %d", len(blocks))
+	}
+	var prev [32]byte
+	for i, header := range blocks {
+		if header.Height != uint32(i+1) {
+			t.Errorf("%d: expected height %d, got %d", i, i+1, header.Height)
+		}
+		if header.PrevBlock != prev {
+			t.Errorf("%d: expected prev block %x, got %v", i, prev, header.PrevBlock)
+		}
+		if err := pow.CheckHeaderProofOfWork(header, &pow.SimNetPowLimit); err != nil {
+			t.Errorf("%d: %v", i, err)
+		}
+		prev = header.BlockHash()
+	}
+
+	var total uint64
+	for _, n := range m.hashes {
+		total += n
+	}
+	if total == 0 {
+		t.Fatalf("no hashes counted")
+	}
+}
+
+func TestMinerCancel(t *testing.T) {
+	// A target this hard is never met during the test.
+	node := newMockNode("", "", 0x1a00ffff, &pow.SimNetPowLimit)
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	m := newMiner(newRPCClient(srv.URL, "", "", nil), 2, 50*time.Millisecond)
+	if n := m.run(ctx, 1); n != 0 {
+		t.Fatalf("expected no accepted blocks, got %d", n)
+	}
+	for i, n := range m.hashes {
+		if n == 0 {
+			t.Errorf("thread %d did not hash", i)
+		}
+	}
+}
+
+func TestFormatHashRate(t *testing.T) {
+	tests := []struct {
+		rate float64
+		want string
+	}{
+		{0, "0.00 H/s"},
+		{999, "999.00 H/s"},
+		{1500, "1.50 kH/s"},
+		{2.5e6, "2.50 MH/s"},
+		{3e18, "3000.00 PH/s"},
+	}
+	for _, test := range tests {
+		if got := formatHashRate(test.rate); got != test.want {
+			t.Errorf("%g: expected %q, got %q", test.rate, test.want, got)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rickiey/blake256/getwork"
+	"github.com/rickiey/blake256/pow"
+	"github.com/rickiey/blake256/wire"
+)
+
+// mockNode is an in-process stand-in for the getwork RPC of a node. It
+// extends a chain of bare headers with every valid solution and needs no
+// blocks, transactions or peers.
+type mockNode struct {
+	user, pass string
+	bits       uint32
+	powLimit   *pow.Uint256
+
+	mu     sync.Mutex
+	tip    wire.Hash
+	height uint32
+	work   *getwork.Work
+	blocks []*wire.BlockHeader
+}
+
+// newMockNode returns a mock node handing out work with the given bits,
+// which must not exceed powLimit.
+func newMockNode(user, pass string, bits uint32, powLimit *pow.Uint256) *mockNode {
+	return &mockNode{user: user, pass: pass, bits: bits, powLimit: powLimit}
+}
+
+// template returns the work extending the current tip. The caller must hold
+// n.mu.
+func (n *mockNode) template() (*getwork.Work, error) {
+	if n.work != nil {
+		return n.work, nil
+	}
+	var h [4]byte
+	binary.LittleEndian.PutUint32(h[:], n.height+1)
+	header := &wire.BlockHeader{
+		Version:      9,
+		PrevBlock:    n.tip,
+		MerkleRoot:   wire.HashH(h[:]),
+		Bits:         n.bits,
+		SBits:        2 * 1e8,
+		Height:       n.height + 1,
+		Timestamp:    time.Unix(time.Now().Unix(), 0),
+		StakeVersion: 9,
+	}
+	w, err := getwork.New(header)
+	if err != nil {
+		return nil, err
+	}
+	n.work = w
+	return w, nil
+}
+
+// submit validates solved work data and extends the chain if it solves the
+// current template.
+func (n *mockNode) submit(data []byte) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.work == nil {
+		return false
+	}
+	header, hash, err := n.work.Validate(data, n.powLimit)
+	if err != nil {
+		return false
+	}
+	n.blocks = append(n.blocks, header)
+	n.tip = hash
+	n.height = header.Height
+	n.work = nil
+	return true
+}
+
+// Blocks returns the headers accepted so far.
+func (n *mockNode) Blocks() []*wire.BlockHeader {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return append([]*wire.BlockHeader(nil), n.blocks...)
+}
+
+// ServeHTTP implements the getwork JSON-RPC method.
+func (n *mockNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	user, pass, ok := r.BasicAuth()
+	if !ok || user != n.user || pass != n.pass {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	var req struct {
+		ID     uint64            `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var result interface{}
+	var rpcErr *rpcError
+	switch {
+	case req.Method != "getwork":
+		rpcErr = &rpcError{Code: -32601, Message: "method not found"}
+	case len(req.Params) == 0:
+		n.mu.Lock()
+		work, err := n.template()
+		n.mu.Unlock()
+		if err != nil {
+			rpcErr = &rpcError{Code: -32603, Message: err.Error()}
+			break
+		}
+		result = work.Result()
+	default:
+		var dataHex string
+		if err := json.Unmarshal(req.Params[0], &dataHex); err != nil {
+			rpcErr = &rpcError{Code: -8, Message: err.Error()}
+			break
+		}
+		data, err := hex.DecodeString(dataHex)
+		if err != nil {
+			rpcErr = &rpcError{Code: -8, Message: err.Error()}
+			break
+		}
+		result = n.submit(data)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		Result interface{} `json:"result"`
+		Error  *rpcError   `json:"error"`
+		ID     uint64      `json:"id"`
+	}{result, rpcErr, req.ID})
+}
@@ -0,0 +1,88 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/rickiey/blake256/pow"
+)
+
+func TestMockNodeSubmit(t *testing.T) {
+	node := newMockNode("user", "pass", 0x207fffff, &pow.SimNetPowLimit)
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+	c := newRPCClient(srv.URL, "user", "pass", nil)
+	ctx := context.Background()
+
+	w, err := c.getWork(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An unsolved nonce is rejected.
+	var target pow.Uint256
+	target.SetBytesLE(&w.Target)
+	bad := *w
+	for {
+		hash := bad.Hash()
+		if !pow.HashMeetsTarget((*[32]byte)(&hash), &target) {
+			break
+		}
+		bad.SetNonce(bad.Nonce() + 1)
+	}
+	if ok, err := c.submitWork(ctx, &bad); err != nil || ok {
+		t.Fatalf("expected rejection, got %v, %v", ok, err)
+	}
+
+	sol := *w
+	if _, _, found := sol.SearchNonces(0, 1<<16); !found {
+		t.Fatal("no solution found")
+	}
+	if ok, err := c.submitWork(ctx, &sol); err != nil || !ok {
+		t.Fatalf("expected acceptance, got %v, %v", ok, err)
+	}
+
+	// The same solution is stale once the chain has moved on.
+	if ok, err := c.submitWork(ctx, &sol); err != nil || ok {
+		t.Fatalf("expected stale rejection, got %v, %v", ok, err)
+	}
+	w2, err := c.getWork(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h := w2.Header(); h.Height != 2 || h.PrevBlock != sol.Hash() {
+		t.Fatalf("unexpected next work at height %d on %v", h.Height, h.PrevBlock)
+	}
+}
+
+func TestMockNodeErrors(t *testing.T) {
+	node := newMockNode("user", "pass", 0x207fffff, &pow.SimNetPowLimit)
+	srv := httptest.NewServer(node)
+	defer srv.Close()
+	ctx := context.Background()
+
+	c := newRPCClient(srv.URL, "user", "wrong", nil)
+	if _, err := c.getWork(ctx); err == nil ||
+		!strings.Contains(err.Error(), "authentication") {
+		t.Errorf("expected authentication error, got %v", err)
+	}
+
+	c = newRPCClient(srv.URL, "user", "pass", nil)
+	var result interface{}
+	err := c.call(ctx, "getblockcount", &result)
+	var rpcErr *rpcError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32601 {
+		t.Errorf("expected method not found, got %v", err)
+	}
+	err = c.call(ctx, "getwork", &result, "zz")
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -8 {
+		t.Errorf("expected invalid parameter, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/rickiey/blake256/getwork"
+)
+
+// rpcRequest is a JSON-RPC 1.0 request as accepted by dcrd.
+type rpcRequest struct {
+	JSONRPC string        `json:"jsonrpc"`
+	ID      uint64        `json:"id"`
+	Method  string        `json:"method"`
+	Params  []interface{} `json:"params"`
+}
+
+// rpcResponse is a JSON-RPC response.
+type rpcResponse struct {
+	Result json.RawMessage `json:"result"`
+	Error  *rpcError       `json:"error"`
+	ID     uint64          `json:"id"`
+}
+
+// rpcError is an error returned by the server.
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *rpcError) Error() string {
+	return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+}
+
+// rpcClient is a getwork client for a JSON-RPC server.
+type rpcClient struct {
+	// id is accessed atomically and comes first to be 64-bit aligned on
+	// 32-bit platforms.
+	id uint64
+
+	url        string
+	user, pass string
+	http       *http.Client
+}
+
+// newRPCClient returns a client for the server at url. A nil httpClient
+// uses http.DefaultClient.
+func newRPCClient(url, user, pass string, httpClient *http.Client) *rpcClient {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &rpcClient{url: url, user: user, pass: pass, http: httpClient}
+}
+
+// call performs a JSON-RPC call and decodes its result into result.
+func (c *rpcClient) call(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	if params == nil {
+		params = []interface{}{}
+	}
+	body, err := json.Marshal(&rpcRequest{
+		JSONRPC: "1.0",
+		ID:      atomic.AddUint64(&c.id, 1),
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url,
+		bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.SetBasicAuth(c.user, c.pass)
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf("%s: authentication failed", method)
+	}
+
+	var r rpcResponse
+	if err := json.NewDecoder(resp.Body).Decode(&r); err != nil {
+		return fmt.Errorf("%s: %s: %w", method, resp.Status, err)
+	}
+	if r.Error != nil {
+		return fmt.Errorf("%s: %w", method, r.Error)
+	}
+	return json.Unmarshal(r.Result, result)
+}
+
+// getWork fetches a work unit.
+func (c *rpcClient) getWork(ctx context.Context) (*getwork.Work, error) {
+	var r getwork.Result
+	if err := c.call(ctx, "getwork", &r); err != nil {
+		return nil, err
+	}
+	return getwork.Parse(&r)
+}
+
+// submitWork submits solved work data and reports whether the server
+// accepted it.
+func (c *rpcClient) submitWork(ctx context.Context, w *getwork.Work) (bool, error) {
+	var accepted bool
+	err := c.call(ctx, "getwork", &accepted, w.Result().Data)
+	return accepted, err
+}
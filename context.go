@@ -0,0 +1,138 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+)
+
+// DefaultBatchSize is the number of bytes SumReaderContext hashes between
+// checks for cancellation when no batch size is given.
+const DefaultBatchSize = 16 << 20
+
+// Progress describes how far SumReaderContext has come.
+type Progress struct {
+	// Bytes is the total number of bytes hashed, including those hashed
+	// before the state that was resumed from.
+	Bytes int64
+
+	// Elapsed is the time since the call started.
+	Elapsed time.Duration
+
+	// BytesPerSecond is the throughput of the call so far.
+	BytesPerSecond float64
+}
+
+// ContextOptions configures SumReaderContext. The zero value computes a
+// BLAKE-256 checksum without salt.
+type ContextOptions struct {
+	// Size224 selects BLAKE-224 instead of BLAKE-256.
+	Size224 bool
+
+	// Salt is the optional 16-byte salt.
+	Salt []byte
+
+	// State is a state returned in a CanceledError to resume from. The
+	// reader must be positioned right after the bytes hashed so far. It
+	// overrides Size224 and Salt.
+	State []byte
+
+	// BatchSize is the number of bytes hashed between checks for
+	// cancellation. It defaults to DefaultBatchSize.
+	BatchSize int64
+
+	// Progress, if set, is called after every batch.
+	Progress func(Progress)
+}
+
+// CanceledError is returned by SumReaderContext when its context is done
+// before the end of the stream. It holds the state needed to resume.
+type CanceledError struct {
+	// State is the marshaled state of the hash to pass as
+	// ContextOptions.State.
+	State []byte
+
+	// Bytes is the total number of bytes hashed, which is the offset to
+	// resume reading from.
+	Bytes int64
+
+	// Err is the error of the context.
+	Err error
+}
+
+func (e *CanceledError) Error() string {
+	return fmt.Sprintf("blake256: hashing canceled after %d bytes: %v", e.Bytes, e.Err)
+}
+
+// Unwrap returns the error of the context.
+func (e *CanceledError) Unwrap() error { return e.Err }
+
+// SumReaderContext returns the checksum of the data read from r until EOF,
+// checking ctx between batches of reads. When ctx is done first, it returns a
+// *CanceledError that wraps the context error and carries the state of the
+// hash so that the work can be resumed later. A nil opts is the same as the
+// zero ContextOptions.
+//
+// It panics if the salt is not 16 bytes, like NewSalt.
+func SumReaderContext(ctx context.Context, r io.Reader, opts *ContextOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &ContextOptions{}
+	}
+	var d digest
+	switch {
+	case opts.State != nil:
+		if len(opts.State) >= len(magic224) && string(opts.State[:len(magic224)]) == magic224 {
+			d.hashSize = 224
+		} else {
+			d.hashSize = 256
+		}
+		if err := d.UnmarshalBinary(opts.State); err != nil {
+			return nil, err
+		}
+	case opts.Size224:
+		d.hashSize = 224
+		d.Reset()
+	default:
+		d.hashSize = 256
+		d.Reset()
+	}
+	if opts.State == nil && opts.Salt != nil {
+		d.setSalt(opts.Salt)
+	}
+	batch := opts.BatchSize
+	if batch <= 0 {
+		batch = DefaultBatchSize
+	}
+
+	start := time.Now()
+	resumed := int64(d.length())
+	total := resumed
+	for {
+		if err := ctx.Err(); err != nil {
+			state, _ := d.MarshalBinary()
+			return nil, &CanceledError{State: state, Bytes: total, Err: err}
+		}
+		n, err := d.ReadFrom(io.LimitReader(r, batch))
+		total += n
+		if err != nil {
+			return nil, err
+		}
+		if opts.Progress != nil {
+			p := Progress{Bytes: total, Elapsed: time.Since(start)}
+			if secs := p.Elapsed.Seconds(); secs > 0 {
+				p.BytesPerSecond = float64(total-resumed) / secs
+			}
+			opts.Progress(p)
+		}
+		if n < batch {
+			break
+		}
+	}
+	sum := d.checkSum()
+	return sum[:d.Size()], nil
+}
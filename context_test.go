@@ -0,0 +1,76 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSumReaderContext(t *testing.T) {
+	data := make([]byte, 10*BlockSize+7)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	salt := []byte("SALTsaltSaltSALT")
+	h := New224Salt(salt)
+	h.Write(data)
+	want224 := h.Sum(nil)
+	want256 := Sum256(data)
+
+	sum, err := SumReaderContext(context.Background(), bytes.NewReader(data), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(sum, want256[:]) {
+		t.Errorf("expected %x, got %x", want256, sum)
+	}
+
+	// Cancel after the third batch and resume from the returned state.
+	ctx, cancel := context.WithCancel(context.Background())
+	var reports []Progress
+	opts := &ContextOptions{
+		Size224:   true,
+		Salt:      salt,
+		BatchSize: 100,
+		Progress: func(p Progress) {
+			reports = append(reports, p)
+			if len(reports) == 3 {
+				cancel()
+			}
+		},
+	}
+	_, err = SumReaderContext(ctx, bytes.NewReader(data), opts)
+	var canceled *CanceledError
+	if !errors.As(err, &canceled) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+	if canceled.Bytes != 300 {
+		t.Fatalf("expected 300 bytes hashed, got %d", canceled.Bytes)
+	}
+	for i, p := range reports {
+		if p.Bytes != int64(i+1)*100 {
+			t.Errorf("report %d: expected %d bytes, got %d", i, (i+1)*100, p.Bytes)
+		}
+	}
+
+	opts = &ContextOptions{State: canceled.State, BatchSize: 100}
+	r := bytes.NewReader(data[canceled.Bytes:])
+	sum, err = SumReaderContext(context.Background(), r, opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(sum, want224) {
+		t.Errorf("expected %x, got %x", want224, sum)
+	}
+
+	// An already canceled context hashes nothing.
+	_, err = SumReaderContext(ctx, bytes.NewReader(data), nil)
+	if !errors.As(err, &canceled) || canceled.Bytes != 0 {
+		t.Errorf("expected cancellation before any data, got %v", err)
+	}
+}
@@ -0,0 +1,173 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"sync"
+)
+
+// domainTag is the tag of the tagged hash that derives domain salts.
+const domainTag = "BLAKE-256/domain"
+
+// Domain separates the hashes of different uses of BLAKE-256 by hashing with
+// a salt derived from a human-readable tag. Hashes of the same data in
+// different domains are unrelated.
+type Domain struct {
+	tag  string
+	salt [16]byte
+}
+
+// NewDomain returns the domain for tag. The salt is the first 16 bytes of the
+// tagged hash of tag under "BLAKE-256/domain", so the same tag always gives the
+// same domain.
+func NewDomain(tag string) Domain {
+	d := Domain{tag: tag}
+	sum := TaggedSum256(domainTag, []byte(tag))
+	copy(d.salt[:], sum[:])
+	return d
+}
+
+// Tag returns the tag the domain was derived from.
+func (d Domain) Tag() string { return d.tag }
+
+// Salt returns the 16-byte salt of the domain.
+func (d Domain) Salt() []byte {
+	return append([]byte(nil), d.salt[:]...)
+}
+
+// New returns a new hash.Hash computing the BLAKE-256 checksum in the domain.
+func (d Domain) New() hash.Hash { return NewSalt(d.salt[:]) }
+
+// New224 returns a new hash.Hash computing the BLAKE-224 checksum in the
+// domain.
+func (d Domain) New224() hash.Hash { return New224Salt(d.salt[:]) }
+
+// Sum256 returns the BLAKE-256 checksum of the data in the domain.
+func (d Domain) Sum256(data []byte) [Size]byte {
+	var dg digest
+	dg.hashSize = 256
+	dg.Reset()
+	dg.setSalt(d.salt[:])
+	dg.Write(data)
+	return dg.checkSum()
+}
+
+// Tag computes BIP340-style tagged hashes, BLAKE-256(BLAKE-256(tag) ||
+// BLAKE-256(tag) || msg), from a precomputed state. The two tag hashes fill
+// exactly one block, so only the message is compressed per hash.
+type Tag struct {
+	name string
+	mid  digest
+}
+
+// NewTag returns the tagged hash for name.
+func NewTag(name string) *Tag {
+	t := &Tag{name: name, mid: digest{hashSize: 256, h: iv256}}
+	tagHash := Sum256([]byte(name))
+	var prefix [BlockSize]byte
+	copy(prefix[:], tagHash[:])
+	copy(prefix[Size:], tagHash[:])
+	block(&t.mid, prefix[:])
+	return t
+}
+
+// Name returns the tag.
+func (t *Tag) Name() string { return t.name }
+
+// New returns a new hash.Hash computing the tagged hash of the data written to
+// it. Reset returns it to the state after the tag prefix.
+func (t *Tag) New() hash.Hash {
+	return &taggedDigest{digest: t.mid, tag: t}
+}
+
+// taggedDigest is a digest that resets to the state of its tag.
+type taggedDigest struct {
+	digest
+	tag *Tag
+}
+
+func (d *taggedDigest) Reset() { d.digest = d.tag.mid }
+
+// Sum256 returns the tagged hash of the concatenation of msgs.
+func (t *Tag) Sum256(msgs ...[]byte) [Size]byte {
+	d := t.mid
+	for _, m := range msgs {
+		d.Write(m)
+	}
+	return d.checkSum()
+}
+
+// maxCachedTags is the number of tags whose state TaggedSum256 caches.
+const maxCachedTags = 64
+
+var (
+	// tagsMu protects tags.
+	tagsMu sync.RWMutex
+
+	// tags caches the tagged hashes used by TaggedSum256 by name.
+	tags = make(map[string]*Tag)
+)
+
+// TaggedSum256 returns the BIP340-style tagged hash of the concatenation of
+// msgs, BLAKE-256(BLAKE-256(tag) || BLAKE-256(tag) || msgs...). The state after
+// the tag prefix is cached for the first 64 distinct tags, so tag should be
+// one of a fixed set of constants; other tags are hashed in full every time.
+// Use NewTag for tags chosen at run time.
+func TaggedSum256(tag string, msgs ...[]byte) [Size]byte {
+	tagsMu.RLock()
+	t := tags[tag]
+	tagsMu.RUnlock()
+	if t == nil {
+		t = NewTag(tag)
+		tagsMu.Lock()
+		if len(tags) < maxCachedTags {
+			tags[tag] = t
+		}
+		tagsMu.Unlock()
+	}
+	return t.Sum256(msgs...)
+}
+
+// ErrTagReused is returned by TagRegistry.Register for tags that are already
+// registered.
+var ErrTagReused = errors.New("blake256: tag reused")
+
+// TagRegistry records the tags of domains and tagged hashes to catch
+// accidental reuse of the same tag for different purposes, typically from
+// tests that register every tag used by a program. The zero value is ready to
+// use.
+type TagRegistry struct {
+	mu   sync.Mutex
+	tags map[string]string
+}
+
+// Register records tag as used by owner, a description of its purpose. It
+// returns an error wrapping ErrTagReused if tag is already registered.
+func (r *TagRegistry) Register(tag, owner string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if prev, ok := r.tags[tag]; ok {
+		return fmt.Errorf("%w: %q is used by both %s and %s", ErrTagReused,
+			tag, prev, owner)
+	}
+	if r.tags == nil {
+		r.tags = make(map[string]string)
+	}
+	r.tags[tag] = owner
+	return nil
+}
+
+// MustRegister is like Register but panics if tag is already registered. It
+// returns tag so it can wrap the argument of NewDomain or NewTag in
+// package-level variable declarations.
+func (r *TagRegistry) MustRegister(tag, owner string) string {
+	if err := r.Register(tag, owner); err != nil {
+		panic(err)
+	}
+	return tag
+}
@@ -0,0 +1,117 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestDomain(t *testing.T) {
+	d1 := NewDomain("example/v1")
+	d2 := NewDomain("example/v2")
+	if d1.Tag() != "example/v1" {
+		t.Errorf("unexpected tag %q", d1.Tag())
+	}
+	if !bytes.Equal(d1.Salt(), NewDomain("example/v1").Salt()) {
+		t.Errorf("domain salt is not deterministic")
+	}
+	if bytes.Equal(d1.Salt(), d2.Salt()) {
+		t.Errorf("different tags derived the same salt")
+	}
+	sum := TaggedSum256(domainTag, []byte("example/v1"))
+	if !bytes.Equal(d1.Salt(), sum[:16]) {
+		t.Errorf("expected salt %x, got %x", sum[:16], d1.Salt())
+	}
+
+	data := []byte("data")
+	h := NewSalt(d1.Salt())
+	h.Write(data)
+	want := h.Sum(nil)
+	h = d1.New()
+	h.Write(data)
+	if got := h.Sum(nil); !bytes.Equal(got, want) {
+		t.Errorf("New: expected %x, got %x", want, got)
+	}
+	if got := d1.Sum256(data); !bytes.Equal(got[:], want) {
+		t.Errorf("Sum256: expected %x, got %x", want, got)
+	}
+	if got := d2.Sum256(data); bytes.Equal(got[:], want) {
+		t.Errorf("different domains gave the same hash")
+	}
+	if d1.New224().Size() != Size224 {
+		t.Errorf("New224: unexpected size")
+	}
+}
+
+func TestTaggedSum256(t *testing.T) {
+	for _, tag := range []string{"", "BIP0340/challenge", "a long tag " +
+		"that does not fit into a single block of the hash function"} {
+		tagHash := Sum256([]byte(tag))
+		msg := []byte("message to hash under the tag, longer than a block " +
+			"so that it spans two of them")
+		in := append(append(append([]byte(nil), tagHash[:]...), tagHash[:]...), msg...)
+		want := Sum256(in)
+
+		if got := TaggedSum256(tag, msg); got != want {
+			t.Errorf("%q: expected %x, got %x", tag, want, got)
+		}
+		if got := TaggedSum256(tag, msg[:10], nil, msg[10:]); got != want {
+			t.Errorf("%q: expected %x for split message, got %x", tag, want, got)
+		}
+		h := NewTag(tag).New()
+		h.Write(msg)
+		if got := h.Sum(nil); !bytes.Equal(got, want[:]) {
+			t.Errorf("%q: expected %x from hash, got %x", tag, want, got)
+		}
+
+		// Reset returns to the tagged state rather than to plain BLAKE-256.
+		h.Reset()
+		h.Write([]byte("other"))
+		h.Reset()
+		h.Write(msg)
+		if got := h.Sum(nil); !bytes.Equal(got, want[:]) {
+			t.Errorf("%q: expected %x after reset, got %x", tag, want, got)
+		}
+	}
+}
+
+func TestTaggedSum256Cache(t *testing.T) {
+	msg := []byte("message")
+	for i := 0; i < 2*maxCachedTags; i++ {
+		tag := fmt.Sprintf("dynamic tag %d", i)
+		if got, want := TaggedSum256(tag, msg), NewTag(tag).Sum256(msg); got != want {
+			t.Fatalf("%d: expected %x, got %x", i, want, got)
+		}
+	}
+	tagsMu.RLock()
+	n := len(tags)
+	tagsMu.RUnlock()
+	if n > maxCachedTags {
+		t.Errorf("expected at most %d cached tags, got %d", maxCachedTags, n)
+	}
+}
+
+func TestTagRegistry(t *testing.T) {
+	var r TagRegistry
+	if err := r.Register("a", "first"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tag := r.MustRegister("b", "second"); tag != "b" {
+		t.Errorf("unexpected tag %q", tag)
+	}
+	if err := r.Register("a", "third"); !errors.Is(err, ErrTagReused) {
+		t.Errorf("expected %v, got %v", ErrTagReused, err)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Errorf("expected panic for reused tag")
+		}
+	}()
+	r.MustRegister("b", "fourth")
+}
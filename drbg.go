@@ -0,0 +1,149 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"crypto/hmac"
+	"errors"
+)
+
+const (
+	// DRBGMaxRequest is the largest number of bytes a single HMACDRBG
+	// Generate call may return, 2^19 bits per NIST SP 800-90A.
+	DRBGMaxRequest = 1 << 16
+
+	// DRBGReseedInterval is the default number of Generate calls allowed
+	// between reseeds, the maximum of 2^48 per NIST SP 800-90A.
+	DRBGReseedInterval = 1 << 48
+
+	// drbgMinEntropy is the minimum entropy input length for the 256-bit
+	// security strength of HMAC-BLAKE256.
+	drbgMinEntropy = 32
+)
+
+var (
+	// ErrReseedRequired is returned by HMACDRBG.Generate once the reseed
+	// interval is exhausted.
+	ErrReseedRequired = errors.New("blake256: DRBG reseed required")
+
+	// ErrDRBGRequestTooLarge is returned by HMACDRBG.Generate for requests
+	// larger than DRBGMaxRequest.
+	ErrDRBGRequestTooLarge = errors.New("blake256: DRBG request too large")
+
+	// ErrDRBGEntropy is returned when the entropy input is shorter than 32
+	// bytes.
+	ErrDRBGEntropy = errors.New("blake256: DRBG entropy input too short")
+)
+
+// HMACDRBG is the HMAC_DRBG deterministic random bit generator of NIST SP
+// 800-90A rev. 1, section 10.1.2, using HMAC-BLAKE256. Given the same inputs
+// it always produces the same output, which makes it usable both as a CSPRNG
+// seeded from a real entropy source and as a reproducible generator for tests
+// and simulations. It is not safe for concurrent use.
+type HMACDRBG struct {
+	k, v          [Size]byte
+	reseedCounter uint64
+
+	// ReseedInterval is the number of Generate calls allowed between
+	// reseeds. It is set to DRBGReseedInterval by NewHMACDRBG and may be
+	// lowered.
+	ReseedInterval uint64
+}
+
+// NewHMACDRBG instantiates an HMAC_DRBG from the entropy input, a nonce and an
+// optional personalization string. The entropy input must be at least 32
+// bytes.
+func NewHMACDRBG(entropy, nonce, personalization []byte) (*HMACDRBG, error) {
+	if len(entropy) < drbgMinEntropy {
+		return nil, ErrDRBGEntropy
+	}
+	d := &HMACDRBG{ReseedInterval: DRBGReseedInterval}
+	for i := range d.v {
+		d.v[i] = 0x01
+	}
+	d.update(entropy, nonce, personalization)
+	d.reseedCounter = 1
+	return d, nil
+}
+
+// update is the HMAC_DRBG_Update function on the concatenation of the
+// provided data.
+func (d *HMACDRBG) update(data ...[]byte) {
+	empty := true
+	for _, b := range data {
+		empty = empty && len(b) == 0
+	}
+	for _, sep := range []byte{0x00, 0x01} {
+		mac := hmac.New(New, d.k[:])
+		mac.Write(d.v[:])
+		mac.Write([]byte{sep})
+		for _, b := range data {
+			mac.Write(b)
+		}
+		mac.Sum(d.k[:0])
+		d.refreshV()
+		if empty {
+			return
+		}
+	}
+}
+
+// refreshV sets V to HMAC(K, V).
+func (d *HMACDRBG) refreshV() {
+	mac := hmac.New(New, d.k[:])
+	mac.Write(d.v[:])
+	mac.Sum(d.v[:0])
+}
+
+// Reseed mixes fresh entropy input, which must be at least 32 bytes, and
+// optional additional input into the state and resets the reseed counter.
+func (d *HMACDRBG) Reseed(entropy, additional []byte) error {
+	if len(entropy) < drbgMinEntropy {
+		return ErrDRBGEntropy
+	}
+	d.update(entropy, additional)
+	d.reseedCounter = 1
+	return nil
+}
+
+// Generate fills out with pseudorandom bytes, mixing in the optional
+// additional input. It returns ErrReseedRequired once ReseedInterval calls
+// have been made since the last reseed, and ErrDRBGRequestTooLarge if out is
+// larger than DRBGMaxRequest.
+func (d *HMACDRBG) Generate(out, additional []byte) error {
+	if len(out) > DRBGMaxRequest {
+		return ErrDRBGRequestTooLarge
+	}
+	if d.reseedCounter > d.ReseedInterval {
+		return ErrReseedRequired
+	}
+	if len(additional) > 0 {
+		d.update(additional)
+	}
+	for len(out) > 0 {
+		d.refreshV()
+		out = out[copy(out, d.v[:]):]
+	}
+	d.update(additional)
+	d.reseedCounter++
+	return nil
+}
+
+// Read implements io.Reader by calling Generate without additional input for
+// each DRBGMaxRequest bytes of p. It fails with ErrReseedRequired once a
+// reseed is needed.
+func (d *HMACDRBG) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		m := len(p) - n
+		if m > DRBGMaxRequest {
+			m = DRBGMaxRequest
+		}
+		if err := d.Generate(p[n:n+m], nil); err != nil {
+			return n, err
+		}
+		n += m
+	}
+	return n, nil
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"bytes"
+	"encoding/hex"
+	"io"
+	"testing"
+)
+
+// drbgVectors are HMAC_DRBG test vectors in the layout of the NIST CAVP
+// files, generated with an independent Python implementation of BLAKE-256.
+var drbgVectors = []struct {
+	entropy, nonce              string
+	personalization, add1, add2 string
+	reseedEntropy, reseedAdd    string
+	out1, out2, out3            string
+}{
+	{
+		"000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f",
+		"808182838485868788898a8b8c8d8e8f",
+		"", "", "",
+		"c0c1c2c3c4c5c6c7c8c9cacbcccdcecfd0d1d2d3d4d5d6d7d8d9dadbdcdddedfe0e1e2e3e4e5e6e7", "",
+		"cec37554ea71b0593e0d7f1ac55869f2b9148c6f41216078aa420760f2d45e587f73a2fde702594f62dbd1fa3b9f8faa9d06933a90f52120262322cac763e9cf",
+		"3f8a321518742c4cb6c7ac1b3da37ad99a129fbc9da60531a526a4e18d660ca1a6565e7fd0689ae131bf3631c20b7ce84e0b25865dc88e8a3f79a337ecda5db5",
+		"cc3392329f0648235e88c5b1b94eca77d6c81f85fa7e999f42c944f64dc589f109438403564482fdc9f4031da85d8b0f6a12724a68e2134ff4746efe11d648683ad86456e53af114ceedc1cf4e589590684b3066f97c82b30cc89a865f9762f0c461233b",
+	},
+	{
+		"1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e",
+		"808182838485868788898a8b8c8d8e8f",
+		"706572736f6e616c697a6174696f6e", "6164646974696f6e616c2031", "6164646974696f6e616c2032",
+		"c0c1c2c3c4c5c6c7c8c9cacbcccdcecfd0d1d2d3d4d5d6d7d8d9dadbdcdddedfe0e1e2e3e4e5e6e7", "726573656564206164646974696f6e616c",
+		"e75a66cb5d099a2a85320872fc7c3e271f9b6ac102185458849bedb5962b3db17b16b1fd38723404d240f49d1f8135dd93fc638b7175e3d0d825479d264abec5",
+		"3cf49561185a595a5c2013a7505d31826e48e5461f24a976b68156f5c090845e5f6c421102048029755035e887800cfebd065952e4118eaafa2e7ac15bda6544",
+		"559e8994f4dddba07b6a05a126049b0806db5998fb5aae7825ac5a196d94e60c5529baf4073f347ccdccf4493ea3b828bb7c17888abfa0ca4e2cf6812a5574df87ba99774ec8986f479226927bfcbd5bac234715132658cfcefa5f20419aaeab4bbd3568",
+	},
+}
+
+func TestHMACDRBG(t *testing.T) {
+	for i, v := range drbgVectors {
+		d, err := NewHMACDRBG(hexDecode(v.entropy), hexDecode(v.nonce),
+			hexDecode(v.personalization))
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		out := make([]byte, 64)
+		if err := d.Generate(out, hexDecode(v.add1)); err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		if res := hex.EncodeToString(out); res != v.out1 {
+			t.Errorf("%d: expected %q, got %q", i, v.out1, res)
+		}
+		if err := d.Generate(out, hexDecode(v.add2)); err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		if res := hex.EncodeToString(out); res != v.out2 {
+			t.Errorf("%d: expected %q, got %q", i, v.out2, res)
+		}
+		if err := d.Reseed(hexDecode(v.reseedEntropy), hexDecode(v.reseedAdd)); err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		out = make([]byte, 100)
+		if err := d.Generate(out, nil); err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		if res := hex.EncodeToString(out); res != v.out3 {
+			t.Errorf("%d: expected %q, got %q", i, v.out3, res)
+		}
+	}
+}
+
+func TestHMACDRBGLimits(t *testing.T) {
+	entropy := make([]byte, 32)
+	if _, err := NewHMACDRBG(entropy[:31], nil, nil); err != ErrDRBGEntropy {
+		t.Errorf("expected %v, got %v", ErrDRBGEntropy, err)
+	}
+	d, err := NewHMACDRBG(entropy, nil, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.Generate(make([]byte, DRBGMaxRequest+1), nil); err != ErrDRBGRequestTooLarge {
+		t.Errorf("expected %v, got %v", ErrDRBGRequestTooLarge, err)
+	}
+
+	d.ReseedInterval = 2
+	buf := make([]byte, 10)
+	for i := 0; i < 2; i++ {
+		if err := d.Generate(buf, nil); err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+	}
+	if err := d.Generate(buf, nil); err != ErrReseedRequired {
+		t.Errorf("expected %v, got %v", ErrReseedRequired, err)
+	}
+	if err := d.Reseed(entropy[:16], nil); err != ErrDRBGEntropy {
+		t.Errorf("expected %v, got %v", ErrDRBGEntropy, err)
+	}
+	if err := d.Reseed(entropy, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := d.Generate(buf, nil); err != nil {
+		t.Errorf("unexpected error after reseed: %v", err)
+	}
+}
+
+func TestHMACDRBGReader(t *testing.T) {
+	entropy := make([]byte, 32)
+	d1, _ := NewHMACDRBG(entropy, []byte("nonce"), nil)
+	d2, _ := NewHMACDRBG(entropy, []byte("nonce"), nil)
+
+	// Reads are split into requests of the maximum size.
+	got := make([]byte, DRBGMaxRequest+10)
+	if _, err := io.ReadFull(d1, got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := make([]byte, len(got))
+	d2.Generate(want[:DRBGMaxRequest], nil)
+	d2.Generate(want[DRBGMaxRequest:], nil)
+	if !bytes.Equal(got, want) {
+		t.Errorf("reader output differs from Generate")
+	}
+}
+
+func hexDecode(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
@@ -0,0 +1,19 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package blake256
+
+import "os"
+
+// haveFileID reports whether fileID identifies files on this platform.
+const haveFileID = false
+
+// fileID always returns zeros since device and inode numbers are only
+// available on Unix. Checkpoints then rely on the path of the file.
+func fileID(fi os.FileInfo) (dev, ino uint64) {
+	return 0, 0
+}
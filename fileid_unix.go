@@ -0,0 +1,25 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package blake256
+
+import (
+	"os"
+	"syscall"
+)
+
+// haveFileID reports whether fileID identifies files on this platform.
+const haveFileID = true
+
+// fileID returns the device and inode numbers of the file described by fi.
+func fileID(fi os.FileInfo) (dev, ino uint64) {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0
+	}
+	return uint64(st.Dev), uint64(st.Ino)
+}
@@ -0,0 +1,87 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gcs
+
+// bitWriter appends bits to a byte slice, most significant bit first.
+type bitWriter struct {
+	buf  []byte
+	free uint // unused low bits of the last byte
+}
+
+// writeBit appends a single bit.
+func (w *bitWriter) writeBit(bit bool) {
+	if w.free == 0 {
+		w.buf = append(w.buf, 0)
+		w.free = 8
+	}
+	w.free--
+	if bit {
+		w.buf[len(w.buf)-1] |= 1 << w.free
+	}
+}
+
+// writeBits appends the low count bits of v, most significant first.
+func (w *bitWriter) writeBits(v uint64, count uint) {
+	for count > 0 {
+		count--
+		w.writeBit(v>>count&1 == 1)
+	}
+}
+
+// writeUnary appends q one bits followed by a zero bit.
+func (w *bitWriter) writeUnary(q uint64) {
+	for ; q > 0; q-- {
+		w.writeBit(true)
+	}
+	w.writeBit(false)
+}
+
+// bitReader reads bits from a byte slice, most significant bit first.
+type bitReader struct {
+	data []byte
+	pos  uint // index of the next bit
+}
+
+// readBit reads a single bit. It reports false at the end of the data.
+func (r *bitReader) readBit() (bit, ok bool) {
+	if r.pos >= uint(len(r.data))*8 {
+		return false, false
+	}
+	bit = r.data[r.pos/8]>>(7-r.pos%8)&1 == 1
+	r.pos++
+	return bit, true
+}
+
+// readBits reads count bits into the low bits of the result.
+func (r *bitReader) readBits(count uint) (uint64, bool) {
+	var v uint64
+	for ; count > 0; count-- {
+		bit, ok := r.readBit()
+		if !ok {
+			return 0, false
+		}
+		v <<= 1
+		if bit {
+			v |= 1
+		}
+	}
+	return v, true
+}
+
+// readUnary reads one bits up to and including a zero bit and returns their
+// number.
+func (r *bitReader) readUnary() (uint64, bool) {
+	var q uint64
+	for {
+		bit, ok := r.readBit()
+		if !ok {
+			return 0, false
+		}
+		if !bit {
+			return q, true
+		}
+		q++
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gcs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBitRoundTrip(t *testing.T) {
+	var w bitWriter
+	w.writeBit(true)
+	w.writeBits(0x5, 3)
+	w.writeUnary(3)
+	w.writeBits(0x1ffff, 17)
+	w.writeUnary(0)
+	// 1 101 1110, seventeen ones and 0, padded with six zero bits.
+	want := []byte{0xde, 0xff, 0xff, 0x80}
+	if !bytes.Equal(w.buf, want) {
+		t.Fatalf("expected %x, got %x", want, w.buf)
+	}
+
+	r := bitReader{data: w.buf}
+	if bit, ok := r.readBit(); !ok || !bit {
+		t.Fatalf("expected 1 bit")
+	}
+	if v, ok := r.readBits(3); !ok || v != 0x5 {
+		t.Fatalf("expected 5, got %d", v)
+	}
+	if q, ok := r.readUnary(); !ok || q != 3 {
+		t.Fatalf("expected 3, got %d", q)
+	}
+	if v, ok := r.readBits(17); !ok || v != 0x1ffff {
+		t.Fatalf("expected 0x1ffff, got %x", v)
+	}
+	if q, ok := r.readUnary(); !ok || q != 0 {
+		t.Fatalf("expected 0, got %d", q)
+	}
+	if v, ok := r.readBits(6); !ok || v != 0 {
+		t.Fatalf("expected padding bits")
+	}
+	if _, ok := r.readBit(); ok {
+		t.Fatalf("read past end of data")
+	}
+	r = bitReader{data: []byte{0xff}}
+	if _, ok := r.readUnary(); ok {
+		t.Fatalf("unterminated unary value read")
+	}
+}
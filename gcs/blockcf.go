@@ -0,0 +1,61 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gcs
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rickiey/blake256/merkle"
+	"github.com/rickiey/blake256/wire"
+)
+
+// ErrCommitmentMismatch is returned when a filter is not committed to by a
+// block header.
+var ErrCommitmentMismatch = errors.New("gcs: filter does not match header commitment")
+
+// BlockKey returns the key of the filter of a block: the first KeySize bytes
+// of the merkle root in its header.
+func BlockKey(merkleRoot *wire.Hash) [KeySize]byte {
+	var key [KeySize]byte
+	copy(key[:], merkleRoot[:])
+	return key
+}
+
+// NewBlockFilter returns the version 2 filter of a block over the given
+// scripts, keyed by the merkle root of its header. The caller selects the
+// scripts as DCP-0005 specifies: the output scripts of the block and the
+// scripts of the outputs it spends, with stake opcode prefixes removed and
+// ticket commitment scripts left out. Empty scripts are skipped.
+func NewBlockFilter(merkleRoot *wire.Hash, scripts [][]byte) (*Filter, error) {
+	return NewFilter(BlockFilterB, BlockFilterM, BlockKey(merkleRoot), scripts)
+}
+
+// BlockFilterFromBytes returns the version 2 block filter serialized in d.
+func BlockFilterFromBytes(d []byte) (*Filter, error) {
+	return FromBytes(BlockFilterB, BlockFilterM, d)
+}
+
+// CalcCommitmentRootV1 returns the version 1 header commitment root, the
+// root of a Merkle tree whose only leaf is the block filter hash. Headers
+// store it in the StakeRoot field once DCP-0005 is active.
+func CalcCommitmentRootV1(filterHash *wire.Hash) wire.Hash {
+	return merkle.CalcMerkleRoot([]wire.Hash{*filterHash})
+}
+
+// VerifyHeaderCommitment checks that filter is the block filter committed to
+// by header, given the index of the filter hash in the commitment tree and
+// its inclusion proof as served with the filter. For version 1 commitments
+// the index is 0 and the proof is empty.
+func VerifyHeaderCommitment(header *wire.BlockHeader, filter *Filter, proofIndex uint32, proof []wire.Hash) error {
+	if filter.B() != BlockFilterB || filter.m != BlockFilterM {
+		return fmt.Errorf("%w: not a version 2 block filter", ErrCommitmentMismatch)
+	}
+	filterHash := filter.Hash()
+	if !merkle.VerifyInclusionProof(&header.StakeRoot, &filterHash, proofIndex, proof) {
+		return fmt.Errorf("%w: block %v", ErrCommitmentMismatch, header.BlockHash())
+	}
+	return nil
+}
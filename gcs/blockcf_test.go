@@ -0,0 +1,211 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gcs
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rickiey/blake256/merkle"
+	"github.com/rickiey/blake256/wire"
+)
+
+// testBlockFilter returns the mainnet genesis header with the version 1
+// commitment to the filter of its only output script in StakeRoot, as
+// DCP-0005 headers carry it, and that filter.
+func testBlockFilter(t *testing.T) (*wire.BlockHeader, *Filter) {
+	t.Helper()
+	merkleRoot, err := wire.NewHashFromStr(genesisMerkleRoot)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := NewBlockFilter(merkleRoot, [][]byte{genesisScript})
+	if err != nil {
+		t.Fatal(err)
+	}
+	header := &wire.BlockHeader{
+		Version:    1,
+		MerkleRoot: *merkleRoot,
+		Bits:       0x1b01ffff,
+		SBits:      2 * 1e8,
+		Timestamp:  time.Unix(1454954400, 0),
+	}
+	filterHash := f.Hash()
+	header.StakeRoot = CalcCommitmentRootV1(&filterHash)
+	return header, f
+}
+
+func TestBlockFilter(t *testing.T) {
+	header, f := testBlockFilter(t)
+	want := hexToBytes("01929fd8")
+	if !bytes.Equal(f.Bytes(), want) {
+		t.Fatalf("expected %x, got %x", want, f.Bytes())
+	}
+	wantHash := hexToBytes("47a29cecacd4456da3eafb80e7d8b018a758efcbfe2ae8d4be2aa026c44fa569")
+	if h := f.Hash(); !bytes.Equal(h[:], wantHash) {
+		t.Fatalf("expected hash %x, got %x", wantHash, h[:])
+	}
+	key := BlockKey(&header.MerkleRoot)
+	if want := hexToBytes("0dc101dfc3c6a2eb10ca0c5374e10d28"); !bytes.Equal(key[:], want) {
+		t.Fatalf("expected key %x, got %x", want, key)
+	}
+	if !f.Match(key, genesisScript) {
+		t.Fatalf("expected match")
+	}
+
+	// The version 1 commitment root is the filter hash itself.
+	if !bytes.Equal(header.StakeRoot[:], wantHash) {
+		t.Fatalf("expected commitment root %x, got %x", wantHash, header.StakeRoot[:])
+	}
+
+	// A block without scripts commits to the zero hash.
+	empty, err := NewBlockFilter(&header.MerkleRoot, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	emptyHash := empty.Hash()
+	if root := CalcCommitmentRootV1(&emptyHash); root != (wire.Hash{}) {
+		t.Fatalf("expected zero commitment root, got %v", root)
+	}
+}
+
+// block432100Header is the header of mainnet block 432100, the block used by
+// the filter tests of dcrd, and block432100Filter its version 2 filter as
+// served by dcrd.
+const (
+	block432100Header = "070000009c3c0efea268c124d46d7daeae2d9667e78daa0523a19725000000000000" +
+		"00000bc8a255edde9901ecc4cdb93e4e573cb38ae91e84495ecddc0c93c019351d5d7731" +
+		"998be0a78e955f6fb98d2f35479905c3279f6257beab42a51d556cef55b9010087ba86bb" +
+		"2e5204000100b1a00000e20f27181e4afc5b03000000e4970600de0a0000d2b46d5ef63e" +
+		"4a6dd6ab3b0000000000a200ca77000000000000000000000000000000000000000007000000"
+	block432100Filter = "11cdaad289eb092b5fd6ad60c7f7f197c2234dcbc74b14e1a477b319eae9d189cfae45f06a" +
+		"225965c7e932fc7600"
+)
+
+// block432100Scripts are the scripts committed to by the filter of block
+// 432100, with stake opcode tags removed.
+var block432100Scripts = []string{
+	// Coinbase outputs.
+	"a914f5916158e3e2c4551c1796708db8367207ed13bb87",
+	"6a0ce49706009a26e5301973f83a",
+	"76a9149c417596dea6570f8e546674555b5ce5087ce2c288ac",
+	// Outputs of the regular transaction, one of them paid twice.
+	"76a9140bb407413316b66d7648f4d938d701159b34dfd688ac",
+	"76a914065660dc3514f60a08fb32c37b2fa39863dd053788ac",
+	// Outputs spent by the regular transaction: a stake change output of
+	// 5d1e84..0683:3 and c720b8..94d5:2.
+	"76a9144ad2bfb09f0faceb9b96cdb65a9d164a3d6ae0b888ac",
+	"76a914bc3c059489f447afbf542ff33432adb9ded7f8e988ac",
+	// Payments of the four votes.
+	"76a914c82c7d53ee9c2d44e55837e1c9f0ac56c86f281d88ac",
+	"76a914d54d6bb291b98f2e9f63685c96c8a6a7ffe10c3a88ac",
+	"76a9140d68315d5757a612d232f01e39d9a89a9b78345b88ac",
+	"76a914327c2f178183b23422737032a8035b17c517a76a88ac",
+	"76a91424b5e23ae9ebeb3b7a450378bc475af35b8fd55a88ac",
+	"76a9140fbdb7f4007cd6deab32963d0ac1ebcf0ca2725988ac",
+	"76a9140c25ed1c136bf5c91e3c93bc9000f4b1eb8550fb88ac",
+	"76a914db74ca882db2c646a3b23cb757a6d61c687f8bc588ac",
+	// Ticket purchase: the commitment as a pay-to-pubkey-hash script and the
+	// output it spends, 98578f..e9bb:25, which pays the key in its signature
+	// script. The change output has no value and is left out.
+	"76a914c4f83f25f5db5005b4ca1c7fd6a3b126aa43506088ac",
+	"76a914140a9503d51b4b06675ba8e11195657376c3deb288ac",
+}
+
+// TestBlockFilter432100 builds the filter of a real block from its scripts
+// and checks it against the filter served by dcrd and the commitment in the
+// header.
+func TestBlockFilter432100(t *testing.T) {
+	var header wire.BlockHeader
+	if err := header.FromBytes(hexToBytes(block432100Header)); err != nil {
+		t.Fatal(err)
+	}
+	if h := header.BlockHash().String(); h != "000000000000000023455b4328635d8e014dbeea99c6140aa715836cc7e55981" {
+		t.Fatalf("unexpected block hash %s", h)
+	}
+
+	scripts := make([][]byte, len(block432100Scripts))
+	for i, s := range block432100Scripts {
+		scripts[i] = hexToBytes(s)
+	}
+	f, err := NewBlockFilter(&header.MerkleRoot, scripts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := hexToBytes(block432100Filter)
+	if !bytes.Equal(f.Bytes(), want) {
+		t.Fatalf("expected %x, got %x", want, f.Bytes())
+	}
+	if f.N() != uint32(len(scripts)) {
+		t.Fatalf("expected N %d, got %d", len(scripts), f.N())
+	}
+	filterHash := f.Hash()
+	if root := CalcCommitmentRootV1(&filterHash); root != header.StakeRoot {
+		t.Fatalf("expected commitment root %v, got %v", header.StakeRoot, root)
+	}
+
+	served, err := BlockFilterFromBytes(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyHeaderCommitment(&header, served, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	key := BlockKey(&header.MerkleRoot)
+	for i, script := range scripts {
+		if !served.Match(key, script) {
+			t.Errorf("%d: no match for %x", i, script)
+		}
+	}
+	if !served.MatchAny(key, scripts) {
+		t.Errorf("expected a match")
+	}
+}
+
+func TestVerifyHeaderCommitment(t *testing.T) {
+	header, f := testBlockFilter(t)
+
+	served, err := BlockFilterFromBytes(f.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyHeaderCommitment(header, served, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// A filter changed by a peer is rejected.
+	tampered := append([]byte{}, f.Bytes()...)
+	tampered[len(tampered)-1] ^= 1
+	bad, err := BlockFilterFromBytes(tampered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyHeaderCommitment(header, bad, 0, nil); !errors.Is(err, ErrCommitmentMismatch) {
+		t.Errorf("expected %v, got %v", ErrCommitmentMismatch, err)
+	}
+	if err := VerifyHeaderCommitment(header, served, 1, nil); !errors.Is(err, ErrCommitmentMismatch) {
+		t.Errorf("expected %v, got %v", ErrCommitmentMismatch, err)
+	}
+	other, err := FromBytes(BlockFilterB+1, BlockFilterM, f.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyHeaderCommitment(header, other, 0, nil); !errors.Is(err, ErrCommitmentMismatch) {
+		t.Errorf("expected %v, got %v", ErrCommitmentMismatch, err)
+	}
+
+	// Commitments with more leaves use an inclusion proof.
+	leaves := []wire.Hash{wire.HashH([]byte("a")), f.Hash(), wire.HashH([]byte("b"))}
+	header.StakeRoot = merkle.CalcMerkleRoot(leaves)
+	proof := merkle.GenerateInclusionProof(leaves, 1)
+	if err := VerifyHeaderCommitment(header, served, 1, proof); err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyHeaderCommitment(header, served, 0, proof); !errors.Is(err, ErrCommitmentMismatch) {
+		t.Errorf("expected %v, got %v", ErrCommitmentMismatch, err)
+	}
+}
@@ -0,0 +1,248 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package gcs implements the Golomb-coded set filters of Decred's version 2
+// compact block filters (DCP-0005) and the header commitment that lets a
+// light client validate filters served by untrusted peers.
+//
+// A filter of N items with parameters B and M maps each item with SipHash-2-4
+// into [0, N*M), sorts the values and Golomb-Rice codes the differences
+// between them: the quotient by 2^B in unary, then the remainder in B bits.
+// The serialized filter is N as a varint followed by the bits, most
+// significant first and padded with zero bits to a whole byte. A filter of
+// no items serializes to no bytes and its hash is all zeros.
+package gcs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/rickiey/blake256"
+	"github.com/rickiey/blake256/internal/siphash"
+	"github.com/rickiey/blake256/wire"
+)
+
+// KeySize is the size of a filter key in bytes.
+const KeySize = siphash.KeySize
+
+// Parameters of version 2 block filters.
+const (
+	// BlockFilterB is the Golomb-Rice coding parameter of block filters.
+	BlockFilterB = 19
+
+	// BlockFilterM is the inverse false positive rate of block filters.
+	BlockFilterM = 784931
+)
+
+// MaxB is the largest supported Golomb-Rice coding parameter.
+const MaxB = 32
+
+var (
+	// ErrBTooBig is returned when B exceeds MaxB.
+	ErrBTooBig = errors.New("gcs: B is too big")
+
+	// ErrNTooBig is returned when a filter has too many items for N * M to
+	// fit in 64 bits or N to fit in 32 bits.
+	ErrNTooBig = errors.New("gcs: too many items")
+
+	// ErrMisserialized is returned when a serialized filter is malformed.
+	ErrMisserialized = errors.New("gcs: misserialized filter")
+)
+
+// Filter is an immutable Golomb-coded set filter.
+type Filter struct {
+	b    uint8
+	m    uint64
+	n    uint32
+	nm   uint64 // n * m, the range of the mapped values
+	data []byte // the serialized filter
+	bits []byte // the Golomb-Rice coded values within data
+}
+
+// checkParams checks B and that N * M fits in 64 bits.
+func checkParams(b uint8, m uint64, n uint64) error {
+	if b > MaxB {
+		return fmt.Errorf("%w: %d > %d", ErrBTooBig, b, MaxB)
+	}
+	if n > 1<<32-1 {
+		return fmt.Errorf("%w: %d", ErrNTooBig, n)
+	}
+	if hi, _ := bits.Mul64(n, m); hi != 0 {
+		return fmt.Errorf("%w: %d items with M %d", ErrNTooBig, n, m)
+	}
+	return nil
+}
+
+// reduce maps a 64-bit hash uniformly into [0, nm).
+func reduce(hash, nm uint64) uint64 {
+	hi, _ := bits.Mul64(hash, nm)
+	return hi
+}
+
+// NewFilter returns a filter with parameters b and m over the distinct
+// non-empty items, keyed by key. Empty items are left out.
+func NewFilter(b uint8, m uint64, key [KeySize]byte, items [][]byte) (*Filter, error) {
+	seen := make(map[string]struct{}, len(items))
+	unique := make([][]byte, 0, len(items))
+	for _, item := range items {
+		if len(item) == 0 {
+			continue
+		}
+		if _, ok := seen[string(item)]; ok {
+			continue
+		}
+		seen[string(item)] = struct{}{}
+		unique = append(unique, item)
+	}
+	if err := checkParams(b, m, uint64(len(unique))); err != nil {
+		return nil, err
+	}
+
+	f := &Filter{b: b, m: m, n: uint32(len(unique))}
+	f.nm = uint64(f.n) * m
+	if f.n == 0 {
+		return f, nil
+	}
+
+	values := make([]uint64, len(unique))
+	for i, item := range unique {
+		values[i] = reduce(siphash.Sum64(&key, item), f.nm)
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	w := bitWriter{buf: wire.AppendVarInt(nil, uint64(f.n))}
+	prefix := len(w.buf)
+	var last uint64
+	for _, v := range values {
+		delta := v - last
+		last = v
+		w.writeUnary(delta >> b)
+		w.writeBits(delta, uint(b))
+	}
+	f.data = w.buf
+	f.bits = f.data[prefix:]
+	return f, nil
+}
+
+// FromBytes returns the filter with parameters b and m serialized in d. The
+// filter retains d.
+func FromBytes(b uint8, m uint64, d []byte) (*Filter, error) {
+	if len(d) == 0 {
+		if err := checkParams(b, m, 0); err != nil {
+			return nil, err
+		}
+		return &Filter{b: b, m: m}, nil
+	}
+	r := bytes.NewReader(d)
+	n, err := wire.ReadVarInt(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMisserialized, err)
+	}
+	if n == 0 {
+		return nil, fmt.Errorf("%w: empty filter with data", ErrMisserialized)
+	}
+	if err := checkParams(b, m, n); err != nil {
+		return nil, err
+	}
+	prefix := len(d) - r.Len()
+	return &Filter{
+		b:    b,
+		m:    m,
+		n:    uint32(n),
+		nm:   n * m,
+		data: d,
+		bits: d[prefix:],
+	}, nil
+}
+
+// B returns the Golomb-Rice coding parameter of the filter.
+func (f *Filter) B() uint8 {
+	return f.b
+}
+
+// N returns the number of items in the filter.
+func (f *Filter) N() uint32 {
+	return f.n
+}
+
+// Bytes returns the serialized filter. The caller must not modify it.
+func (f *Filter) Bytes() []byte {
+	return f.data
+}
+
+// Hash returns the BLAKE-256 hash of the serialized filter, the value
+// committed to by block headers. The hash of an empty filter is all zeros.
+func (f *Filter) Hash() wire.Hash {
+	if len(f.data) == 0 {
+		return wire.Hash{}
+	}
+	return blake256.Sum256(f.data)
+}
+
+// values calls fn with each value in the filter in ascending order until fn
+// returns false or the values are exhausted. It returns false if the data
+// ends before N values are decoded.
+func (f *Filter) values(fn func(v uint64) bool) bool {
+	r := bitReader{data: f.bits}
+	var v uint64
+	for i := uint32(0); i < f.n; i++ {
+		q, ok := r.readUnary()
+		if !ok {
+			return false
+		}
+		rem, ok := r.readBits(uint(f.b))
+		if !ok {
+			return false
+		}
+		v += q<<f.b | rem
+		if !fn(v) {
+			return true
+		}
+	}
+	return true
+}
+
+// Match reports whether item may be in the filter keyed by key. False
+// positives occur at a rate of about 1/M.
+func (f *Filter) Match(key [KeySize]byte, item []byte) bool {
+	if f.n == 0 {
+		return false
+	}
+	target := reduce(siphash.Sum64(&key, item), f.nm)
+	match := false
+	f.values(func(v uint64) bool {
+		match = v == target
+		return v < target
+	})
+	return match
+}
+
+// MatchAny reports whether any of items may be in the filter keyed by key.
+// It decodes the filter once.
+func (f *Filter) MatchAny(key [KeySize]byte, items [][]byte) bool {
+	if f.n == 0 || len(items) == 0 {
+		return false
+	}
+	targets := make([]uint64, len(items))
+	for i, item := range items {
+		targets[i] = reduce(siphash.Sum64(&key, item), f.nm)
+	}
+	sort.Slice(targets, func(i, j int) bool { return targets[i] < targets[j] })
+
+	match := false
+	f.values(func(v uint64) bool {
+		for len(targets) > 0 && targets[0] < v {
+			targets = targets[1:]
+		}
+		if len(targets) == 0 {
+			return false
+		}
+		match = targets[0] == v
+		return !match
+	})
+	return match
+}
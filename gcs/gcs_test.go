@@ -0,0 +1,338 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package gcs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/rickiey/blake256/wire"
+)
+
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
+
+// testKey returns the key 00 01 .. 0f.
+func testKey() [KeySize]byte {
+	var key [KeySize]byte
+	for i := range key {
+		key[i] = byte(i)
+	}
+	return key
+}
+
+var testItems = [][]byte{
+	[]byte("Alex"), []byte("Bob"), []byte("Charlie"), []byte("Dick"),
+	[]byte("Ed"), []byte("Frank"), []byte("George"), []byte("Harry"),
+	[]byte("Ilya"), []byte("John"), []byte("Kevin"), []byte("Larry"),
+	[]byte("Michael"), []byte("Nate"), []byte("Owen"), []byte("Paul"),
+	[]byte("Quentin"),
+}
+
+// genesisScript is the only output script of the Decred mainnet genesis
+// block, and genesisMerkleRoot its merkle root.
+var (
+	genesisScript     = hexToBytes("801679e98561ada96caec2949a5d41c4cab3851eb740d951c10ecbcf265c1fd9")
+	genesisMerkleRoot = "66aa7491b9adce110585ccab7e3fb5fe280de174530cca10eba2c6c3df01c10d"
+)
+
+// genesisKey returns the block filter key of the genesis block.
+func genesisKey() [KeySize]byte {
+	merkleRoot, err := wire.NewHashFromStr(genesisMerkleRoot)
+	if err != nil {
+		panic(err)
+	}
+	return BlockKey(merkleRoot)
+}
+
+// TestNewFilter checks the version 2 filter of the mainnet genesis block.
+// The expected filter was computed with an independent implementation.
+func TestNewFilter(t *testing.T) {
+	key := genesisKey()
+	f, err := NewFilter(BlockFilterB, BlockFilterM, key, [][]byte{genesisScript})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := hexToBytes("01929fd8")
+	if !bytes.Equal(f.Bytes(), want) {
+		t.Fatalf("expected %x, got %x", want, f.Bytes())
+	}
+	wantHash := hexToBytes("47a29cecacd4456da3eafb80e7d8b018a758efcbfe2ae8d4be2aa026c44fa569")
+	if h := f.Hash(); !bytes.Equal(h[:], wantHash) {
+		t.Fatalf("expected hash %x, got %x", wantHash, h[:])
+	}
+	if f.N() != 1 || f.B() != BlockFilterB {
+		t.Fatalf("unexpected N %d or B %d", f.N(), f.B())
+	}
+
+	// Duplicate and empty items do not change the filter.
+	items := [][]byte{nil, genesisScript, {}, genesisScript}
+	f2, err := NewFilter(BlockFilterB, BlockFilterM, key, items)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(f2.Bytes(), want) {
+		t.Fatalf("expected %x, got %x", want, f2.Bytes())
+	}
+
+	dups := append(append([][]byte{}, testItems...), testItems[0], testItems[1])
+	f3, err := NewFilter(BlockFilterB, BlockFilterM, testKey(), dups)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f3.N() != uint32(len(testItems)) {
+		t.Fatalf("expected N %d, got %d", len(testItems), f3.N())
+	}
+}
+
+// TestFilterVectors checks the version 2 filter vectors of the gcs package of
+// dcrd, which use an all-zero key. The hashes are in the byte-reversed order
+// used for display.
+func TestFilterVectors(t *testing.T) {
+	contents2 := [][]byte{
+		[]byte("Alice"), []byte("Betty"), []byte("Charmaine"), []byte("Donna"),
+		[]byte("Edith"), []byte("Faina"), []byte("Georgia"), []byte("Hannah"),
+		[]byte("Ilsbeth"), []byte("Jennifer"), []byte("Kayla"), []byte("Lena"),
+		[]byte("Michelle"), []byte("Natalie"), []byte("Ophelia"), []byte("Peggy"),
+		[]byte("Queenie"),
+	}
+	tests := []struct {
+		name   string
+		b      uint8
+		m      uint64
+		items  [][]byte
+		filter string
+		hash   string
+	}{{
+		name:   "empty data",
+		b:      19,
+		m:      784931,
+		filter: "",
+		hash:   "0000000000000000000000000000000000000000000000000000000000000000",
+	}, {
+		name:   "single nil item produces empty filter",
+		b:      19,
+		m:      784931,
+		items:  [][]byte{nil},
+		filter: "",
+		hash:   "0000000000000000000000000000000000000000000000000000000000000000",
+	}, {
+		name:   "contents1 with B=19, M=784931",
+		b:      19,
+		m:      784931,
+		items:  testItems,
+		filter: "1189af70ad5baf9da83c64e99b18e96a06cd7295a58b324e81f09c85d093f1e33dcd6f40f18cfcbe2aeb771d8390",
+		hash:   "b616838c6090d3e732e775cc2f336ce0b836895f3e0f22d6c3ee4485a6ea5018",
+	}, {
+		name:   "contents1 with nil item with B=19, M=784931",
+		b:      19,
+		m:      784931,
+		items:  append([][]byte{nil}, testItems...),
+		filter: "1189af70ad5baf9da83c64e99b18e96a06cd7295a58b324e81f09c85d093f1e33dcd6f40f18cfcbe2aeb771d8390",
+		hash:   "b616838c6090d3e732e775cc2f336ce0b836895f3e0f22d6c3ee4485a6ea5018",
+	}, {
+		name:   "contents2 with B=19, M=784931",
+		b:      19,
+		m:      784931,
+		items:  contents2,
+		filter: "118d4be5372d2f4731c7e1681aefd23028be12306b4d90701a46b472ee80ad60f9fa86c4d6430cfb495ced604362",
+		hash:   "f3028f42909209120c8bf649fbbc5a70fb907d8997a02c2c1f2eef0e6402cb15",
+	}, {
+		name:   "contents1 with B=20, M=1569862",
+		b:      20,
+		m:      1569862,
+		items:  testItems,
+		filter: "1189af7056adebe769078c9e99b1774b509b35c52b4b0b324f40f83f2174227e3c33dcd67a078c33f2f855d6ef1d8390",
+		hash:   "10d6c29ba756301e42b97a103de601f604f1cd3150e44ac7cb8cdf63222d93d3",
+	}, {
+		name:   "contents2 with B=20, M=1569862",
+		b:      20,
+		m:      1569862,
+		items:  contents2,
+		filter: "118d4be69b968bd1cc38fc2d01aefc918142f847e0d69b9070192359fcbba015ac1f9fa86a26b1fc33ed32b9da604361",
+		hash:   "cb23a266bf136103cbfedb33041d4f4324106ed1539b9ec5d1cb4e71bc3cbdec",
+	}, {
+		name:   "contents2 with B=10, M=1534",
+		b:      10,
+		m:      1534,
+		items:  contents2,
+		filter: "118d5a6fbd3e3fc1aa472f983790848fcbcd6b9fb89cc34caf6048",
+		hash:   "d7c5f91d6490ec4d66e6db825ad1a628ec7367dc9a47c31bcde31eb5a6ba194b",
+	}}
+
+	var key [KeySize]byte
+	for _, test := range tests {
+		f, err := NewFilter(test.b, test.m, key, test.items)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if got := hex.EncodeToString(f.Bytes()); got != test.filter {
+			t.Errorf("%s: expected %s, got %s", test.name, test.filter, got)
+		}
+		wantHash, err := wire.NewHashFromStr(test.hash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if h := f.Hash(); h != *wantHash {
+			t.Errorf("%s: expected hash %v, got %v", test.name, wantHash, h)
+		}
+
+		// Decoding the serialized filter must give back the same filter.
+		f2, err := FromBytes(test.b, test.m, f.Bytes())
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if f2.N() != f.N() || !bytes.Equal(f2.Bytes(), f.Bytes()) {
+			t.Errorf("%s: decoded filter differs", test.name)
+		}
+		for _, item := range test.items {
+			if len(item) != 0 && !f2.Match(key, item) {
+				t.Errorf("%s: no match for %q", test.name, item)
+			}
+		}
+	}
+}
+
+func TestEmptyFilter(t *testing.T) {
+	for i, items := range [][][]byte{nil, {{}}, {nil, {}}} {
+		f, err := NewFilter(BlockFilterB, BlockFilterM, testKey(), items)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(f.Bytes()) != 0 || f.N() != 0 {
+			t.Fatalf("%d: expected empty filter, got %x", i, f.Bytes())
+		}
+		// The hash of an empty filter is all zeros.
+		if h := f.Hash(); h != (wire.Hash{}) {
+			t.Fatalf("%d: expected zero hash, got %x", i, h[:])
+		}
+		if f.Match(testKey(), testItems[0]) || f.MatchAny(testKey(), testItems) {
+			t.Fatalf("%d: empty filter matched", i)
+		}
+	}
+	f, err := FromBytes(BlockFilterB, BlockFilterM, nil)
+	if err != nil || f.N() != 0 {
+		t.Fatalf("expected empty filter, got %v", err)
+	}
+	if h := f.Hash(); h != (wire.Hash{}) {
+		t.Fatalf("expected zero hash, got %x", h[:])
+	}
+}
+
+func TestMatch(t *testing.T) {
+	key := testKey()
+	built, err := NewFilter(BlockFilterB, BlockFilterM, key, testItems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := FromBytes(BlockFilterB, BlockFilterM, built.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.N() != built.N() {
+		t.Fatalf("expected N %d, got %d", built.N(), f.N())
+	}
+
+	for _, item := range testItems {
+		if !f.Match(key, item) {
+			t.Errorf("%s: expected match", item)
+		}
+		if !f.MatchAny(key, [][]byte{[]byte("nobody"), item}) {
+			t.Errorf("%s: expected MatchAny", item)
+		}
+	}
+
+	var absent [][]byte
+	for i := 0; i < 1000; i++ {
+		absent = append(absent, []byte(fmt.Sprintf("absent %d", i)))
+	}
+	for _, item := range absent {
+		if f.Match(key, item) {
+			t.Errorf("%s: unexpected match", item)
+		}
+	}
+	if f.MatchAny(key, absent) {
+		t.Errorf("unexpected MatchAny")
+	}
+	if f.MatchAny(key, nil) {
+		t.Errorf("unexpected MatchAny of no items")
+	}
+
+	// Another key maps the items elsewhere.
+	other := key
+	other[0] ^= 1
+	matches := 0
+	for _, item := range testItems {
+		if f.Match(other, item) {
+			matches++
+		}
+	}
+	if matches != 0 {
+		t.Errorf("%d items matched with the wrong key", matches)
+	}
+}
+
+func TestFilterErrors(t *testing.T) {
+	if _, err := NewFilter(MaxB+1, BlockFilterM, testKey(), testItems); !errors.Is(err, ErrBTooBig) {
+		t.Errorf("expected %v, got %v", ErrBTooBig, err)
+	}
+	if _, err := NewFilter(BlockFilterB, 1<<62, testKey(), testItems); !errors.Is(err, ErrNTooBig) {
+		t.Errorf("expected %v, got %v", ErrNTooBig, err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"zero count", []byte{0x00, 0x01}, ErrMisserialized},
+		{"truncated varint", []byte{0xfd, 0x01}, ErrMisserialized},
+		{"non-canonical varint", []byte{0xfd, 0x01, 0x00, 0xff}, ErrMisserialized},
+		{"huge count", hexToBytes("ff0000000001000000"), ErrNTooBig},
+	}
+	for _, test := range tests {
+		if _, err := FromBytes(BlockFilterB, BlockFilterM, test.data); !errors.Is(err, test.err) {
+			t.Errorf("%s: expected %v, got %v", test.name, test.err, err)
+		}
+	}
+
+	// A truncated filter decodes but matches nothing past its end.
+	built, err := NewFilter(BlockFilterB, BlockFilterM, testKey(), testItems)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := FromBytes(BlockFilterB, BlockFilterM, built.Bytes()[:3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, item := range testItems {
+		if f.Match(testKey(), item) && !built.Match(testKey(), item) {
+			t.Errorf("%s: truncated filter matched absent item", item)
+		}
+	}
+}
+
+func BenchmarkMatch(b *testing.B) {
+	items := make([][]byte, 1000)
+	for i := range items {
+		items[i] = []byte(fmt.Sprintf("script %d", i))
+	}
+	f, err := NewFilter(BlockFilterB, BlockFilterM, testKey(), items)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f.Match(testKey(), items[i%len(items)])
+	}
+}
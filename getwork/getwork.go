@@ -0,0 +1,277 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package getwork implements the work data format of Decred's legacy getwork
+// RPC.
+//
+// The data is the 180-byte serialized block header followed by the
+// BLAKE-256 padding of its final block, 192 bytes in all, so that a miner
+// can compress it directly: the first two blocks once per work unit into a
+// midstate and the final block once per nonce. Header fields keep their
+// little-endian serialization and no words are byte swapped. The 32-bit
+// nonce is at NonceOffset and the extra nonce fills the first ExtraNonceSize
+// bytes of the header extra data at ExtraNonceOffset.
+//
+// The target is sent as a 32-byte little-endian integer, the order in which
+// the block hash is compared against it.
+package getwork
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/rickiey/blake256"
+	"github.com/rickiey/blake256/pow"
+	"github.com/rickiey/blake256/wire"
+)
+
+const (
+	// DataSize is the size of getwork data: the block header rounded up to
+	// whole BLAKE-256 blocks including the padding.
+	DataSize = (1 + (wire.MaxBlockHeaderPayload+8)/blake256.BlockSize) *
+		blake256.BlockSize
+
+	// TargetSize is the size of a getwork target.
+	TargetSize = 32
+
+	// TimestampOffset is the offset of the header timestamp in the data.
+	TimestampOffset = 136
+
+	// NonceOffset is the offset of the header nonce in the data.
+	NonceOffset = 140
+
+	// ExtraNonceOffset is the offset of the extra nonce in the data, the
+	// start of the header extra data.
+	ExtraNonceOffset = 144
+
+	// ExtraNonceSize is the size of the extra nonce.
+	ExtraNonceSize = 8
+)
+
+var (
+	// ErrInvalidDataLen is returned when getwork data is not DataSize
+	// bytes.
+	ErrInvalidDataLen = errors.New("getwork: invalid data length")
+
+	// ErrInvalidPadding is returned when getwork data does not end with
+	// the BLAKE-256 padding of a block header.
+	ErrInvalidPadding = errors.New("getwork: invalid data padding")
+
+	// ErrInvalidTarget is returned when a getwork target is malformed or
+	// does not match the header bits.
+	ErrInvalidTarget = errors.New("getwork: invalid target")
+
+	// ErrWorkMismatch is returned when submitted data changes header fields
+	// other than those a miner is allowed to vary.
+	ErrWorkMismatch = errors.New("getwork: submitted header does not match work")
+)
+
+// padding is the BLAKE-256 padding that follows the header in the data.
+var padding = func() [DataSize - wire.MaxBlockHeaderPayload]byte {
+	var p [DataSize - wire.MaxBlockHeaderPayload]byte
+	p[0] = 0x80
+	p[len(p)-9] |= 0x01
+	binary.BigEndian.PutUint64(p[len(p)-8:], wire.MaxBlockHeaderPayload*8)
+	return p
+}()
+
+// Work is a getwork unit.
+type Work struct {
+	// Data is the padded serialized header.
+	Data [DataSize]byte
+
+	// Target is the little-endian target the block hash must not exceed.
+	Target [TargetSize]byte
+
+	// Midstate is the BLAKE-256 state after the first two blocks of Data.
+	// It is only valid while those bytes are unchanged.
+	Midstate blake256.Midstate
+}
+
+// Result is the JSON result of a getwork request.
+type Result struct {
+	Data   string `json:"data"`
+	Target string `json:"target"`
+}
+
+// EncodeData returns the getwork data of a header.
+func EncodeData(header *wire.BlockHeader) [DataSize]byte {
+	var data [DataSize]byte
+	copy(data[:], header.Bytes())
+	copy(data[wire.MaxBlockHeaderPayload:], padding[:])
+	return data
+}
+
+// DecodeData returns the header in getwork data after checking its length
+// and padding.
+func DecodeData(data []byte) (*wire.BlockHeader, error) {
+	if len(data) != DataSize {
+		return nil, fmt.Errorf("%w: %d bytes, want %d", ErrInvalidDataLen,
+			len(data), DataSize)
+	}
+	if string(data[wire.MaxBlockHeaderPayload:]) != string(padding[:]) {
+		return nil, ErrInvalidPadding
+	}
+	header := new(wire.BlockHeader)
+	if err := header.FromBytes(data[:wire.MaxBlockHeaderPayload]); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// EncodeTarget returns the getwork target encoded by compact bits.
+func EncodeTarget(bits uint32) ([TargetSize]byte, error) {
+	var le [TargetSize]byte
+	target, isNegative, overflows := pow.CompactToTarget(bits)
+	if isNegative || overflows || target.IsZero() {
+		return le, fmt.Errorf("%w: bits %08x", ErrInvalidTarget, bits)
+	}
+	be := target.Bytes()
+	for i := range be {
+		le[i] = be[len(be)-1-i]
+	}
+	return le, nil
+}
+
+// New returns the work unit for a header.
+func New(header *wire.BlockHeader) (*Work, error) {
+	target, err := EncodeTarget(header.Bits)
+	if err != nil {
+		return nil, err
+	}
+	w := &Work{Data: EncodeData(header), Target: target}
+	w.Midstate = blake256.HeaderMidstate(w.header())
+	return w, nil
+}
+
+// Parse returns the work unit of a getwork result. The target must match
+// the bits of the header.
+func Parse(r *Result) (*Work, error) {
+	data, err := hex.DecodeString(r.Data)
+	if err != nil {
+		return nil, fmt.Errorf("getwork: data: %w", err)
+	}
+	header, err := DecodeData(data)
+	if err != nil {
+		return nil, err
+	}
+	w, err := New(header)
+	if err != nil {
+		return nil, err
+	}
+	if hex.EncodeToString(w.Target[:]) != r.Target {
+		return nil, fmt.Errorf("%w: %q does not match bits %08x",
+			ErrInvalidTarget, r.Target, header.Bits)
+	}
+	return w, nil
+}
+
+// Result returns the getwork result of the work unit.
+func (w *Work) Result() *Result {
+	return &Result{
+		Data:   hex.EncodeToString(w.Data[:]),
+		Target: hex.EncodeToString(w.Target[:]),
+	}
+}
+
+// header returns the serialized header in the data.
+func (w *Work) header() *[wire.MaxBlockHeaderPayload]byte {
+	return (*[wire.MaxBlockHeaderPayload]byte)(w.Data[:wire.MaxBlockHeaderPayload])
+}
+
+// Header returns the block header in the data.
+func (w *Work) Header() *wire.BlockHeader {
+	header := new(wire.BlockHeader)
+	header.FromArray(w.header())
+	return header
+}
+
+// Nonce returns the header nonce.
+func (w *Work) Nonce() uint32 {
+	return binary.LittleEndian.Uint32(w.Data[NonceOffset:])
+}
+
+// SetNonce sets the header nonce.
+func (w *Work) SetNonce(nonce uint32) {
+	binary.LittleEndian.PutUint32(w.Data[NonceOffset:], nonce)
+}
+
+// ExtraNonce returns the extra nonce.
+func (w *Work) ExtraNonce() uint64 {
+	return binary.LittleEndian.Uint64(w.Data[ExtraNonceOffset:])
+}
+
+// SetExtraNonce sets the extra nonce.
+func (w *Work) SetExtraNonce(extraNonce uint64) {
+	binary.LittleEndian.PutUint64(w.Data[ExtraNonceOffset:], extraNonce)
+}
+
+// SetTimestamp sets the header timestamp in seconds since the Unix epoch.
+func (w *Work) SetTimestamp(unix uint32) {
+	binary.LittleEndian.PutUint32(w.Data[TimestampOffset:], unix)
+}
+
+// FinalBlock returns a pointer to the final BLAKE-256 block of the data, the
+// only block that changes with the nonce, extra nonce and timestamp.
+func (w *Work) FinalBlock() *[blake256.BlockSize]byte {
+	const off = blake256.HeaderMidstateSize
+	return (*[blake256.BlockSize]byte)(w.Data[off : off+blake256.BlockSize])
+}
+
+// Hash returns the block hash of the data from the midstate.
+func (w *Work) Hash() wire.Hash {
+	return wire.Hash(w.Midstate.SumHeader(w.FinalBlock()))
+}
+
+// SearchNonces hashes the data with nonces from start through start+count-1
+// using the midstate, stopping at the first hash that meets the target. It
+// returns that nonce, with the nonce set in the data, and the number of
+// hashes computed. The caller varies the extra nonce once the 32-bit nonce
+// space is exhausted.
+func (w *Work) SearchNonces(start, count uint32) (nonce uint32, hashes uint32, found bool) {
+	var target pow.Uint256
+	target.SetBytesLE(&w.Target)
+	final := w.FinalBlock()
+	const off = NonceOffset - blake256.HeaderMidstateSize
+	for hashes < count {
+		nonce = start + hashes
+		binary.LittleEndian.PutUint32(final[off:], nonce)
+		hashes++
+		hash := w.Midstate.SumHeader(final)
+		if pow.HashMeetsTarget(&hash, &target) {
+			return nonce, hashes, true
+		}
+	}
+	return 0, hashes, false
+}
+
+// Validate checks submitted getwork data against the work unit it was
+// derived from. Only the timestamp, nonce and extra data may differ. The
+// block hash is recomputed from the submitted bytes and checked against the
+// target and powLimit. It returns the submitted header and its hash.
+func (w *Work) Validate(data []byte, powLimit *pow.Uint256) (*wire.BlockHeader, wire.Hash, error) {
+	header, err := DecodeData(data)
+	if err != nil {
+		return nil, wire.Hash{}, err
+	}
+	expected := w.Header()
+	expected.Timestamp = header.Timestamp
+	expected.Nonce = header.Nonce
+	expected.ExtraData = header.ExtraData
+	if *expected != *header {
+		return nil, wire.Hash{}, ErrWorkMismatch
+	}
+
+	var sub Work
+	copy(sub.Data[:], data)
+	sub.Midstate = blake256.HeaderMidstate(sub.header())
+	hash := sub.Hash()
+	err = pow.CheckProofOfWork((*[32]byte)(&hash), header.Bits, powLimit)
+	if err != nil {
+		return nil, hash, err
+	}
+	return header, hash, nil
+}
@@ -0,0 +1,245 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package getwork
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rickiey/blake256/pow"
+	"github.com/rickiey/blake256/wire"
+)
+
+// testHeader returns the mainnet genesis header with the given bits.
+func testHeader(bits uint32) *wire.BlockHeader {
+	merkle, err := wire.NewHashFromStr("66aa7491b9adce110585ccab7e3fb5fe280de174530cca10eba2c6c3df01c10d")
+	if err != nil {
+		panic(err)
+	}
+	return &wire.BlockHeader{
+		Version:    1,
+		MerkleRoot: *merkle,
+		Bits:       bits,
+		SBits:      2 * 1e8,
+		Timestamp:  time.Unix(1454954400, 0),
+	}
+}
+
+func TestEncodeData(t *testing.T) {
+	if DataSize != 192 {
+		t.Fatalf("expected data size 192, got %d", DataSize)
+	}
+	header := testHeader(0x1b01ffff)
+	data := EncodeData(header)
+	if string(data[:wire.MaxBlockHeaderPayload]) != string(header.Bytes()) {
+		t.Fatalf("data does not start with the serialized header")
+	}
+	wantPad := "8000000100000000000005a0"
+	if got := hex.EncodeToString(data[wire.MaxBlockHeaderPayload:]); got != wantPad {
+		t.Fatalf("expected padding %s, got %s", wantPad, got)
+	}
+
+	got, err := DecodeData(data[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *header {
+		t.Fatalf("expected %+v, got %+v", *header, *got)
+	}
+
+	if _, err := DecodeData(data[:DataSize-1]); !errors.Is(err, ErrInvalidDataLen) {
+		t.Errorf("expected %v, got %v", ErrInvalidDataLen, err)
+	}
+	data[DataSize-1] ^= 1
+	if _, err := DecodeData(data[:]); !errors.Is(err, ErrInvalidPadding) {
+		t.Errorf("expected %v, got %v", ErrInvalidPadding, err)
+	}
+}
+
+func TestEncodeTarget(t *testing.T) {
+	target, err := EncodeTarget(0x1b01ffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "000000000000000000000000000000000000000000000000ffff010000000000"
+	if got := hex.EncodeToString(target[:]); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	for _, bits := range []uint32{0, 0x04923456, 0xff123456} {
+		if _, err := EncodeTarget(bits); !errors.Is(err, ErrInvalidTarget) {
+			t.Errorf("%08x: expected %v, got %v", bits, ErrInvalidTarget, err)
+		}
+	}
+}
+
+func TestWorkNonces(t *testing.T) {
+	header := testHeader(0x1b01ffff)
+	w, err := New(header)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := w.Hash(), header.BlockHash(); got != want {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+
+	w.SetNonce(0x01020304)
+	w.SetExtraNonce(0x0a0b0c0d0e0f1011)
+	w.SetTimestamp(1454954401)
+	header.Nonce = 0x01020304
+	copy(header.ExtraData[:], []byte{0x11, 0x10, 0x0f, 0x0e, 0x0d, 0x0c, 0x0b, 0x0a})
+	header.Timestamp = time.Unix(1454954401, 0)
+	if got := w.Header(); *got != *header {
+		t.Fatalf("expected %+v, got %+v", *header, *got)
+	}
+	if w.Nonce() != 0x01020304 || w.ExtraNonce() != 0x0a0b0c0d0e0f1011 {
+		t.Fatalf("unexpected nonce %x or extra nonce %x", w.Nonce(), w.ExtraNonce())
+	}
+	if got, want := w.Hash(), header.BlockHash(); got != want {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	w, err := New(testHeader(0x1b01ffff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := w.Result()
+	got, err := Parse(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *got != *w {
+		t.Fatalf("parsed work differs")
+	}
+
+	bad := *r
+	bad.Target = "00" + r.Target[2:62] + "ff"
+	if _, err := Parse(&bad); !errors.Is(err, ErrInvalidTarget) {
+		t.Errorf("expected %v, got %v", ErrInvalidTarget, err)
+	}
+	bad = *r
+	bad.Data = "zz"
+	if _, err := Parse(&bad); err == nil {
+		t.Errorf("expected error for invalid hex")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	w, err := New(testHeader(0x207fffff))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Mine the work with the midstate until a share meets the target.
+	sub := *w
+	sub.SetExtraNonce(7)
+	for {
+		hash := sub.Hash()
+		if pow.CheckProofOfWork((*[32]byte)(&hash), 0x207fffff, &pow.SimNetPowLimit) == nil {
+			break
+		}
+		sub.SetNonce(sub.Nonce() + 1)
+	}
+
+	header, hash, err := w.Validate(sub.Data[:], &pow.SimNetPowLimit)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hash != header.BlockHash() {
+		t.Fatalf("expected %v, got %v", header.BlockHash(), hash)
+	}
+	if header.Nonce != sub.Nonce() {
+		t.Fatalf("expected nonce %d, got %d", sub.Nonce(), header.Nonce)
+	}
+
+	// A hash above the target is rejected.
+	high := sub
+	for {
+		high.SetNonce(high.Nonce() + 1)
+		hash := high.Hash()
+		if hash[31]&0x80 != 0 {
+			break
+		}
+	}
+	_, _, err = w.Validate(high.Data[:], &pow.SimNetPowLimit)
+	if !errors.Is(err, pow.ErrHighHash) {
+		t.Errorf("expected %v, got %v", pow.ErrHighHash, err)
+	}
+
+	// Changing any other header field is rejected.
+	changed := sub
+	changed.Data[4] ^= 1
+	_, _, err = w.Validate(changed.Data[:], &pow.SimNetPowLimit)
+	if !errors.Is(err, ErrWorkMismatch) {
+		t.Errorf("expected %v, got %v", ErrWorkMismatch, err)
+	}
+
+	// Bits above the network limit are rejected.
+	w2, err := New(testHeader(0x207fffff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = w2.Validate(sub.Data[:], &pow.MainNetPowLimit)
+	if !errors.Is(err, pow.ErrTargetAboveLimit) {
+		t.Errorf("expected %v, got %v", pow.ErrTargetAboveLimit, err)
+	}
+
+	_, _, err = w.Validate(sub.Data[:10], &pow.SimNetPowLimit)
+	if !errors.Is(err, ErrInvalidDataLen) {
+		t.Errorf("expected %v, got %v", ErrInvalidDataLen, err)
+	}
+}
+
+func TestSearchNonces(t *testing.T) {
+	w, err := New(testHeader(0x1f00ffff))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var target pow.Uint256
+	target.SetBytesLE(&w.Target)
+
+	var start uint32
+	for {
+		nonce, hashes, found := w.SearchNonces(start, 1<<12)
+		if !found {
+			if hashes != 1<<12 {
+				t.Fatalf("expected %d hashes, got %d", 1<<12, hashes)
+			}
+			start += hashes
+			continue
+		}
+		if nonce != start+hashes-1 || w.Nonce() != nonce {
+			t.Fatalf("nonce %d inconsistent with start %d and hashes %d",
+				nonce, start, hashes)
+		}
+		break
+	}
+	hash := w.Header().BlockHash()
+	if !pow.HashMeetsTarget((*[32]byte)(&hash), &target) {
+		t.Fatalf("found nonce %d does not meet target", w.Nonce())
+	}
+
+	// The nonces before the solution do not meet the target.
+	check := *w
+	for n := start; n < w.Nonce(); n++ {
+		check.SetNonce(n)
+		hash := check.Hash()
+		if pow.HashMeetsTarget((*[32]byte)(&hash), &target) {
+			t.Fatalf("missed solution at nonce %d", n)
+		}
+	}
+}
+
+func BenchmarkSearchNonces(b *testing.B) {
+	w, err := New(testHeader(0x1b01ffff))
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	w.SearchNonces(0, uint32(b.N))
+}
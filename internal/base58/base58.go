@@ -0,0 +1,144 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package base58 implements the base58 encoding used by Decred addresses and
+// its checked variant, which prefixes a two-byte network identifier and
+// appends the first four bytes of BLAKE-256(BLAKE-256(payload)).
+package base58
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/rickiey/blake256"
+)
+
+const alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+var (
+	// ErrInvalidCharacter is returned when decoding a string containing a
+	// character outside of the base58 alphabet.
+	ErrInvalidCharacter = errors.New("base58: invalid character")
+
+	// ErrChecksum is returned by CheckDecode when the checksum does not
+	// match the payload.
+	ErrChecksum = errors.New("base58: checksum mismatch")
+
+	// ErrInvalidFormat is returned by CheckDecode when the decoded data is
+	// too short to hold the version and checksum.
+	ErrInvalidFormat = errors.New("base58: version and/or checksum bytes missing")
+)
+
+var decodeMap [256]byte
+
+func init() {
+	for i := range decodeMap {
+		decodeMap[i] = 0xff
+	}
+	for i := 0; i < len(alphabet); i++ {
+		decodeMap[alphabet[i]] = byte(i)
+	}
+}
+
+// Encode returns the base58 encoding of b. Each leading zero byte is encoded
+// as a leading '1'.
+func Encode(b []byte) string {
+	zeros := 0
+	for zeros < len(b) && b[zeros] == 0 {
+		zeros++
+	}
+
+	// Repeatedly divide the big-endian number by 58, collecting the
+	// remainders as little-endian digits. log(256)/log(58) < 138/100.
+	digits := make([]byte, 0, (len(b)-zeros)*138/100+1)
+	for _, c := range b[zeros:] {
+		carry := uint32(c)
+		for i := range digits {
+			carry += uint32(digits[i]) << 8
+			digits[i] = byte(carry % 58)
+			carry /= 58
+		}
+		for carry > 0 {
+			digits = append(digits, byte(carry%58))
+			carry /= 58
+		}
+	}
+
+	out := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		out[i] = alphabet[0]
+	}
+	for i, d := range digits {
+		out[len(out)-1-i] = alphabet[d]
+	}
+	return string(out)
+}
+
+// Decode returns the bytes encoded by the base58 string s.
+func Decode(s string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(s) && s[zeros] == alphabet[0] {
+		zeros++
+	}
+
+	// log(58)/log(256) < 733/1000.
+	out := make([]byte, 0, (len(s)-zeros)*733/1000+1)
+	for i := zeros; i < len(s); i++ {
+		carry := uint32(decodeMap[s[i]])
+		if carry == 0xff {
+			return nil, ErrInvalidCharacter
+		}
+		for j := range out {
+			carry += uint32(out[j]) * 58
+			out[j] = byte(carry)
+			carry >>= 8
+		}
+		for carry > 0 {
+			out = append(out, byte(carry))
+			carry >>= 8
+		}
+	}
+
+	b := make([]byte, zeros+len(out))
+	for i, c := range out {
+		b[len(b)-1-i] = c
+	}
+	return b, nil
+}
+
+// checksum returns the first four bytes of BLAKE-256(BLAKE-256(b)).
+func checksum(b []byte) [4]byte {
+	h := blake256.Sum256(b)
+	h = blake256.Sum256(h[:])
+	var c [4]byte
+	copy(c[:], h[:])
+	return c
+}
+
+// CheckEncode returns the base58 encoding of version || payload || checksum.
+func CheckEncode(payload []byte, version [2]byte) string {
+	b := make([]byte, 0, 2+len(payload)+4)
+	b = append(b, version[:]...)
+	b = append(b, payload...)
+	c := checksum(b)
+	return Encode(append(b, c[:]...))
+}
+
+// CheckDecode decodes a string produced by CheckEncode, verifies its checksum
+// and returns the payload and version.
+func CheckDecode(s string) (payload []byte, version [2]byte, err error) {
+	b, err := Decode(s)
+	if err != nil {
+		return nil, version, err
+	}
+	if len(b) < 6 {
+		return nil, version, ErrInvalidFormat
+	}
+	n := len(b) - 4
+	if c := checksum(b[:n]); !bytes.Equal(c[:], b[n:]) {
+		return nil, version, ErrChecksum
+	}
+	copy(version[:], b)
+	return b[2:n], version, nil
+}
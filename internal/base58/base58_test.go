@@ -0,0 +1,105 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package base58
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"testing"
+)
+
+// The vectors below are those of github.com/decred/base58.
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		decoded []byte
+		encoded string
+	}{
+		{[]byte(""), ""},
+		{[]byte(" "), "Z"},
+		{[]byte("-"), "n"},
+		{[]byte("0"), "q"},
+		{[]byte("1"), "r"},
+		{[]byte("-1"), "4SU"},
+		{[]byte("11"), "4k8"},
+		{[]byte("abc"), "ZiCa"},
+		{[]byte("1234598760"), "3mJr7AoUXx2Wqd"},
+		{[]byte("abcdefghijklmnopqrstuvwxyz"), "3yxU3u1igY8WkgtjK92fbJQCd4BZiiT1v25f"},
+		{[]byte("00000000000000000000000000000000000000000000000000000000000000"), "3sN2THZeE9Eh9eYrwkvZqNstbHGvrxSAM7gXUXvyFQP8XvQLUqNCS27icwUeDT7ckHm4FUHM2mTVh1vbLmk7y"},
+		{hexToBytes("61"), "2g"},
+		{hexToBytes("626262"), "a3gV"},
+		{hexToBytes("636363"), "aPEr"},
+		{hexToBytes("73696d706c792061206c6f6e6720737472696e67"), "2cFupjhnEsSn59qHXstmK2ffpLv2"},
+		{hexToBytes("00eb15231dfceb60925886b67d065299925915aeb172c06647"), "1NS17iag9jJgTHD1VXjvLCEnZuQ3rJDE9L"},
+		{hexToBytes("516b6fcd0f"), "ABnLTmg"},
+		{hexToBytes("bf4f89001e670274dd"), "3SEo3LWLoPntC"},
+		{hexToBytes("572e4794"), "3EFU7m"},
+		{hexToBytes("ecac89cad93923c02321"), "EJDM8drfXA6uyA"},
+		{hexToBytes("10c8511e"), "Rt5zm"},
+		{hexToBytes("00000000000000000000"), "1111111111"},
+	}
+	for i, test := range tests {
+		if got := Encode(test.decoded); got != test.encoded {
+			t.Errorf("%d: expected %q, got %q", i, test.encoded, got)
+			continue
+		}
+		got, err := Decode(test.encoded)
+		if err != nil || !bytes.Equal(got, test.decoded) {
+			t.Errorf("%d: expected %x, got %x (%v)", i, test.decoded, got, err)
+		}
+	}
+
+	for _, s := range []string{"0", "O", "I", "l", "3mJr0", "O3yxU", "3sNI", "4kl8", "0OIl", "!@#$%^&*()-_=+~`"} {
+		if _, err := Decode(s); !errors.Is(err, ErrInvalidCharacter) {
+			t.Errorf("%q: expected %v, got %v", s, ErrInvalidCharacter, err)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		version [2]byte
+		decoded string
+		encoded string
+	}{
+		{[2]byte{20, 0}, "", "Axk2WA6L"},
+		{[2]byte{20, 0}, " ", "kxg5DGCa1"},
+		{[2]byte{20, 0}, "-", "kxhWqwoTY"},
+		{[2]byte{20, 0}, "0", "kxhrrcZDw"},
+		{[2]byte{20, 0}, "1", "kxhzgbzwe"},
+		{[2]byte{20, 0}, "-1", "4M2qnQVfVwu"},
+		{[2]byte{20, 0}, "11", "4M2smzp65NR"},
+		{[2]byte{20, 0}, "abc", "FmT72s9HXyp6"},
+		{[2]byte{20, 0}, "1234598760", "3UFLKR4oYrL1hSX1Eu2W3F"},
+		{[2]byte{20, 0}, "abcdefghijklmnopqrstuvwxyz", "2M5VSfthNqvveeGWTcKRgY4Rm258o4ZDKBZGkAQ799jp"},
+	}
+	for i, test := range tests {
+		if got := CheckEncode([]byte(test.decoded), test.version); got != test.encoded {
+			t.Errorf("%d: expected %q, got %q", i, test.encoded, got)
+			continue
+		}
+		payload, version, err := CheckDecode(test.encoded)
+		if err != nil || version != test.version || string(payload) != test.decoded {
+			t.Errorf("%d: expected %q/%x, got %q/%x (%v)", i, test.decoded,
+				test.version, payload, version, err)
+		}
+	}
+
+	if _, _, err := CheckDecode("Axk2WA6M"); !errors.Is(err, ErrChecksum) {
+		t.Errorf("expected %v, got %v", ErrChecksum, err)
+	}
+	if _, _, err := CheckDecode("3MNQE1X"); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("expected %v, got %v", ErrInvalidFormat, err)
+	}
+}
+
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
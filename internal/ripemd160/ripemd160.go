@@ -0,0 +1,181 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package ripemd160 implements the RIPEMD-160 hash function, which Decred
+// combines with BLAKE-256 to form the 160-bit hashes used in addresses and
+// scripts.
+package ripemd160
+
+import (
+	"encoding/binary"
+	"hash"
+	"math/bits"
+)
+
+// The size of a RIPEMD-160 checksum in bytes.
+const Size = 20
+
+// The blocksize of RIPEMD-160 in bytes.
+const BlockSize = 64
+
+var iv = [5]uint32{0x67452301, 0xefcdab89, 0x98badcfe, 0x10325476, 0xc3d2e1f0}
+
+type digest struct {
+	h  [5]uint32
+	x  [BlockSize]byte
+	nx int
+	n  uint64 // bytes written
+}
+
+// New returns a new hash.Hash computing the RIPEMD-160 checksum.
+func New() hash.Hash {
+	d := new(digest)
+	d.Reset()
+	return d
+}
+
+func (d *digest) Reset() {
+	d.h = iv
+	d.nx = 0
+	d.n = 0
+}
+
+func (d *digest) Size() int { return Size }
+
+func (d *digest) BlockSize() int { return BlockSize }
+
+func (d *digest) Write(p []byte) (nn int, err error) {
+	nn = len(p)
+	d.n += uint64(nn)
+	if d.nx > 0 {
+		n := copy(d.x[d.nx:], p)
+		d.nx += n
+		if d.nx == BlockSize {
+			block(d, d.x[:])
+			d.nx = 0
+		}
+		p = p[n:]
+	}
+	if len(p) >= BlockSize {
+		n := len(p) &^ (BlockSize - 1)
+		block(d, p[:n])
+		p = p[n:]
+	}
+	if len(p) > 0 {
+		d.nx = copy(d.x[:], p)
+	}
+	return
+}
+
+func (d *digest) Sum(in []byte) []byte {
+	// Make a copy of d so that caller can keep writing and summing.
+	d0 := *d
+	sum := d0.checkSum()
+	return append(in, sum[:]...)
+}
+
+func (d *digest) checkSum() [Size]byte {
+	n := d.n
+	var tmp [BlockSize]byte
+	tmp[0] = 0x80
+	if n%BlockSize < 56 {
+		d.Write(tmp[:56-n%BlockSize])
+	} else {
+		d.Write(tmp[:BlockSize+56-n%BlockSize])
+	}
+	binary.LittleEndian.PutUint64(tmp[:], n<<3)
+	d.Write(tmp[:8])
+
+	var out [Size]byte
+	for i, v := range d.h {
+		binary.LittleEndian.PutUint32(out[i*4:], v)
+	}
+	return out
+}
+
+// Sum returns the RIPEMD-160 checksum of the data.
+func Sum(data []byte) [Size]byte {
+	var d digest
+	d.Reset()
+	d.Write(data)
+	return d.checkSum()
+}
+
+// Message word selection and rotation amounts of the left and right lines.
+var (
+	rl = [80]uint8{
+		0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15,
+		7, 4, 13, 1, 10, 6, 15, 3, 12, 0, 9, 5, 2, 14, 11, 8,
+		3, 10, 14, 4, 9, 15, 8, 1, 2, 7, 0, 6, 13, 11, 5, 12,
+		1, 9, 11, 10, 0, 8, 12, 4, 13, 3, 7, 15, 14, 5, 6, 2,
+		4, 0, 5, 9, 7, 12, 2, 10, 14, 1, 3, 8, 11, 6, 15, 13,
+	}
+	rr = [80]uint8{
+		5, 14, 7, 0, 9, 2, 11, 4, 13, 6, 15, 8, 1, 10, 3, 12,
+		6, 11, 3, 7, 0, 13, 5, 10, 14, 15, 8, 12, 4, 9, 1, 2,
+		15, 5, 1, 3, 7, 14, 6, 9, 11, 8, 12, 2, 10, 0, 4, 13,
+		8, 6, 4, 1, 3, 11, 15, 0, 5, 12, 2, 13, 9, 7, 10, 14,
+		12, 15, 10, 4, 1, 5, 8, 7, 6, 2, 13, 14, 0, 3, 9, 11,
+	}
+	sl = [80]uint8{
+		11, 14, 15, 12, 5, 8, 7, 9, 11, 13, 14, 15, 6, 7, 9, 8,
+		7, 6, 8, 13, 11, 9, 7, 15, 7, 12, 15, 9, 11, 7, 13, 12,
+		11, 13, 6, 7, 14, 9, 13, 15, 14, 8, 13, 6, 5, 12, 7, 5,
+		11, 12, 14, 15, 14, 15, 9, 8, 9, 14, 5, 6, 8, 6, 5, 12,
+		9, 15, 5, 11, 6, 8, 13, 12, 5, 12, 13, 14, 11, 8, 5, 6,
+	}
+	sr = [80]uint8{
+		8, 9, 9, 11, 13, 15, 15, 5, 7, 7, 8, 11, 14, 14, 12, 6,
+		9, 13, 15, 7, 12, 8, 9, 11, 7, 7, 12, 7, 6, 15, 13, 11,
+		9, 7, 15, 11, 8, 6, 6, 14, 12, 13, 5, 14, 13, 13, 7, 5,
+		15, 5, 8, 11, 14, 14, 6, 14, 6, 9, 12, 9, 12, 5, 15, 8,
+		8, 5, 12, 9, 12, 5, 14, 6, 8, 13, 6, 5, 15, 13, 11, 11,
+	}
+	kl = [5]uint32{0x00000000, 0x5a827999, 0x6ed9eba1, 0x8f1bbcdc, 0xa953fd4e}
+	kr = [5]uint32{0x50a28be6, 0x5c4dd124, 0x6d703ef3, 0x7a6d76e9, 0x00000000}
+)
+
+// f is the nonlinear function of round j/16.
+func f(round int, x, y, z uint32) uint32 {
+	switch round {
+	case 0:
+		return x ^ y ^ z
+	case 1:
+		return x&y | ^x&z
+	case 2:
+		return (x | ^y) ^ z
+	case 3:
+		return x&z | y&^z
+	}
+	return x ^ (y | ^z)
+}
+
+// block compresses the whole blocks in p.
+func block(d *digest, p []byte) {
+	var x [16]uint32
+	for len(p) >= BlockSize {
+		for i := range x {
+			x[i] = binary.LittleEndian.Uint32(p[i*4:])
+		}
+		al, bl, cl, dl, el := d.h[0], d.h[1], d.h[2], d.h[3], d.h[4]
+		ar, br, cr, dr, er := al, bl, cl, dl, el
+		for j := 0; j < 80; j++ {
+			round := j / 16
+			t := bits.RotateLeft32(al+f(round, bl, cl, dl)+x[rl[j]]+kl[round],
+				int(sl[j])) + el
+			al, el, dl, cl, bl = el, dl, bits.RotateLeft32(cl, 10), bl, t
+
+			t = bits.RotateLeft32(ar+f(4-round, br, cr, dr)+x[rr[j]]+kr[round],
+				int(sr[j])) + er
+			ar, er, dr, cr, br = er, dr, bits.RotateLeft32(cr, 10), br, t
+		}
+		t := d.h[1] + cl + dr
+		d.h[1] = d.h[2] + dl + er
+		d.h[2] = d.h[3] + el + ar
+		d.h[3] = d.h[4] + al + br
+		d.h[4] = d.h[0] + bl + cr
+		d.h[0] = t
+		p = p[BlockSize:]
+	}
+}
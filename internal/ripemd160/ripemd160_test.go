@@ -0,0 +1,45 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package ripemd160
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+// Test vectors from the RIPEMD-160 specification.
+var vectors = []struct {
+	out string
+	in  string
+}{
+	{"9c1185a5c5e9fc54612808977ee8f548b2258d31", ""},
+	{"0bdc9d2d256b3ee9daae347be6f4dc835a467ffe", "a"},
+	{"8eb208f7e05d987a9b044a8e98c6b087f15a0bfc", "abc"},
+	{"5d0689ef49d2fae572b881b123a85ffa21595f36", "message digest"},
+	{"f71c27109c692c1b56bbdceb5b9d2865b3708dbc", "abcdefghijklmnopqrstuvwxyz"},
+	{"12a053384a9c0c88e405a06c27dcf49ada62eb2b", "abcdbcdecdefdefgefghfghighijhijkijkljklmklmnlmnomnopnopq"},
+	{"b0e20b6e3116640286ed3a87a5713079b21f5189", "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"},
+	{"9b752e45573d4b39f4dbd3323cab82bf63326bfb", strings.Repeat("1234567890", 8)},
+	{"52783243c1697bdbe16d37f97f68f08325dc1528", strings.Repeat("a", 1000000)},
+}
+
+func TestVectors(t *testing.T) {
+	for i, v := range vectors {
+		if s := fmt.Sprintf("%x", Sum([]byte(v.in))); s != v.out {
+			t.Errorf("%d: expected %q, got %q", i, v.out, s)
+		}
+
+		// Write in two pieces, summing in between.
+		h := New()
+		io.WriteString(h, v.in[:len(v.in)/2])
+		h.Sum(nil)
+		io.WriteString(h, v.in[len(v.in)/2:])
+		if s := fmt.Sprintf("%x", h.Sum(nil)); s != v.out {
+			t.Errorf("%d: expected %q, got %q", i, v.out, s)
+		}
+	}
+}
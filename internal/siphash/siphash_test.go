@@ -0,0 +1,38 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package siphash
+
+import "testing"
+
+func TestSum64(t *testing.T) {
+	// Vectors from the SipHash reference implementation, with the key
+	// 00 01 .. 0f and the message 00 01 .. len-1.
+	tests := []struct {
+		len  int
+		want uint64
+	}{
+		{0, 0x726fdb47dd0e0e31},
+		{1, 0x74f839c593dc67fd},
+		{2, 0x0d6c8009d9a94f5a},
+		{3, 0x85676696d7fb7e2d},
+		{7, 0xab0200f58b01d137},
+		{8, 0x93f5f5799a932462},
+		{15, 0xa129ca6149be45e5},
+		{63, 0x958a324ceb064572},
+	}
+	var key [KeySize]byte
+	for i := range key {
+		key[i] = byte(i)
+	}
+	msg := make([]byte, 64)
+	for i := range msg {
+		msg[i] = byte(i)
+	}
+	for _, test := range tests {
+		if got := Sum64(&key, msg[:test.len]); got != test.want {
+			t.Errorf("%d: expected %#016x, got %#016x", test.len, test.want, got)
+		}
+	}
+}
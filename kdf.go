@@ -0,0 +1,89 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"errors"
+	"hash"
+	"io"
+)
+
+// ErrKDFExhausted is returned by the readers of the key derivation functions
+// once the 32-bit block counter is exhausted.
+var ErrKDFExhausted = errors.New("blake256: key derivation output limit reached")
+
+// counterKDF generates Hash(prefix || counter || suffix) for successive
+// 32-bit big-endian counter values, which is the structure shared by MGF1, the
+// ANSI X9.63 KDF and the SP 800-56C one-step KDF.
+type counterKDF struct {
+	h       hash.Hash
+	prefix  []byte
+	suffix  []byte
+	counter uint32
+	done    bool   // last counter value used
+	out     []byte // output block
+	unread  []byte // unread part of out
+}
+
+func newCounterKDF(newHash func() hash.Hash, prefix, suffix []byte, counter uint32) *counterKDF {
+	if newHash == nil {
+		newHash = New
+	}
+	h := newHash()
+	return &counterKDF{
+		h:       h,
+		prefix:  append([]byte(nil), prefix...),
+		suffix:  append([]byte(nil), suffix...),
+		counter: counter,
+		out:     make([]byte, 0, h.Size()),
+	}
+}
+
+func (k *counterKDF) Read(p []byte) (n int, err error) {
+	for n < len(p) {
+		if len(k.unread) == 0 {
+			if k.done {
+				return n, ErrKDFExhausted
+			}
+			c := k.counter
+			k.h.Reset()
+			k.h.Write(k.prefix)
+			k.h.Write([]byte{byte(c >> 24), byte(c >> 16), byte(c >> 8), byte(c)})
+			k.h.Write(k.suffix)
+			k.unread = k.h.Sum(k.out[:0])
+			k.counter++
+			k.done = c == 0xffffffff
+		}
+		m := copy(p[n:], k.unread)
+		k.unread = k.unread[m:]
+		n += m
+	}
+	return n, nil
+}
+
+// NewMGF1 returns a reader of the MGF1 mask generation function of PKCS #1
+// v2.2 (RFC 8017, appendix B.2.1) for seed, using the hash returned by
+// newHash, or BLAKE-256 if nil. It produces up to 2^32 hash blocks.
+func NewMGF1(newHash func() hash.Hash, seed []byte) io.Reader {
+	return newCounterKDF(newHash, seed, nil, 0)
+}
+
+// NewX963KDF returns a reader of the ANSI X9.63 key derivation function
+// (SEC 1 v2.0, section 3.6.1) for the shared secret z and optional sharedInfo,
+// using the hash returned by newHash, or BLAKE-256 if nil. It produces up to
+// 2^32-1 hash blocks.
+func NewX963KDF(newHash func() hash.Hash, z, sharedInfo []byte) io.Reader {
+	return newCounterKDF(newHash, z, sharedInfo, 1)
+}
+
+// NewOneStepKDF returns a reader of the hash-based one-step key derivation
+// function of NIST SP 800-56C rev. 2, section 4.1, option 1, for the shared
+// secret z and fixedInfo, using the hash returned by newHash, or BLAKE-256 if
+// nil. It produces up to 2^32-1 hash blocks.
+func NewOneStepKDF(newHash func() hash.Hash, z, fixedInfo []byte) io.Reader {
+	suffix := make([]byte, 0, len(z)+len(fixedInfo))
+	suffix = append(append(suffix, z...), fixedInfo...)
+	return newCounterKDF(newHash, nil, suffix, 1)
+}
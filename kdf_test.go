@@ -0,0 +1,128 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"bytes"
+	"encoding/hex"
+	"hash"
+	"io"
+	"testing"
+	"testing/iotest"
+)
+
+// kdfVector is a key derivation test vector generated with an independent
+// Python implementation of BLAKE-256.
+type kdfVector struct {
+	size    int // hash size in bits
+	z, info string
+	out     string
+}
+
+var kdfVectorsMGF1 = []kdfVector{
+	{256, "0123456789abcdef0123456789abcdef", "",
+		"3eae570e7d4f751c5599a216b72b66e93ab3f6ca7477d4ebfaa722dea40ecd85d6404cd0a95860be2517606ae4d2a86b2d15849b3689651b935824aeba071dd44dd9cf4c69a0ce0de7ccd8c6632cedbdd2c4c9327305a90a2614f0d78491f4709a17140f"},
+	{256, "", "",
+		"c53786013b3d5e3ca82a9e520b7be9128a63e95f2245ab6168d14d2e267750ec0f0014626736d9e3c1ce8a6c7bb840bd2e732343d250799d380c1c14a449325afecbc773694e50b252fe0eef5e61d2c6180deb0c9f2740b85b8876dcfc3c13b8e9830ed3"},
+	{256, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f60616263", "",
+		"cf039f7a8f2031c8016fc769fab04c12d4b082850f13604e16f5473704fea8f24e4c41f085f1a49957b34a18cfbf631882e7a6eda8eaa06f277860634e32879a93ceaf0faab7a3b0c1b00e98b2bd4dff00f7392b1fc7a089316db96f2abcdfcf62ce6b80"},
+	{224, "0123456789abcdef0123456789abcdef", "",
+		"c1535665a0245dbfb6909b4a525c706669ba91992ed9f7762d3f5eabf09cec165962da4fc1e8bdc320cd3167687288e206da9068011133661abe5bd9b34af3172a854ad6ab70219a42c8dc1016e8dd242b3e5ecc9bed123c851226b6d8eb1837aeb2cc8a"},
+	{224, "", "",
+		"2b1edd09be84816089aa69e1b3cc6d62b7b6ba5c6d1634c25c848406ba8ce6d9b6d4ee8c69f69ac751251e981cd34e2b0933a9c2f5e7aa8ecfbb98b0e1c231b0789ab6d0e474529a6b3fbf07a3bb250aa1edcc1ea656ac3f1280f0747bc2a659cc2ef2e9"},
+	{224, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f60616263", "",
+		"fcfbfd29a9b4df616cb1e9d8ba55f7c56d38d08f5616f4243e7d1b91ee6610e2dfaca524ff7e9e53b5a9bbe63163bdbb2c32cb8d64fe63a036af6a4497394af74740dc438e00e5d9fbe86a349fdfdd68604709c2af4ae48287275ee849f29b1e673ceaa5"},
+}
+
+var kdfVectorsX963 = []kdfVector{
+	{256, "0123456789abcdef0123456789abcdef", "73686172656420696e666f",
+		"f136c0b408a222b742625feb2e4141fc98df16db0109873eaf8380eabc7d00463361450dc17fe8a5958fa72c466a17db0a6af2a7026a62123ab4cee248678df04a3e5f13cba442a0006e6500699f13c8057c3e6fc21ca9a0af7bf3bd3a29791062a52229"},
+	{256, "", "",
+		"0f0014626736d9e3c1ce8a6c7bb840bd2e732343d250799d380c1c14a449325afecbc773694e50b252fe0eef5e61d2c6180deb0c9f2740b85b8876dcfc3c13b8e9830ed3b6cf460bbca3d82c91e411b0abf05e88ce843f88d3ea1868ae96b06675d94a1b"},
+	{256, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f60616263", "00010203040506",
+		"de7100b5e0f96bde2fc49a47901a6c45005a795284f168dd19edef4adb4316389b9aff9b1266cd924cb7e08b48d85ea47df01c9b93f01f9bbe1b45d5a34f6ba568e816182f3fef9979f1945ddc9904f4bf72d4c5e1f255c01818d9c7fce68d3fd830f620"},
+	{224, "0123456789abcdef0123456789abcdef", "73686172656420696e666f",
+		"e15bfe179179b8482d3119c29c4b4f9967bd4f900deb8193492492b743fcce2278a61a0e21b9dc225be3fedf1bf8ff208635e4361eba762a71651be241db5d2ea6e7c5bc209baa3704a81b66b965111f397808228bd694b62cdb7dc8bd014ec8ad7a9e0e"},
+	{224, "", "",
+		"ba8ce6d9b6d4ee8c69f69ac751251e981cd34e2b0933a9c2f5e7aa8ecfbb98b0e1c231b0789ab6d0e474529a6b3fbf07a3bb250aa1edcc1ea656ac3f1280f0747bc2a659cc2ef2e9eeaf9888707fd978b21e25e1dd3c3d63fa45b3d642966d8feb68cf13"},
+	{224, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f60616263", "00010203040506",
+		"a88a5c4d86d8417d1e5fba92f217ae3b27201aa9e505e50c9242bf030c18b15e7c2da79555d440f9df694d804928e4371dbeef6981b3d81555bdecc424e0047cfc2bd8118bafd6e0ed0d39b52d8495b46e290b42fa7962aad65c25e61324c5943264b28d"},
+}
+
+var kdfVectorsOneStep = []kdfVector{
+	{256, "0123456789abcdef0123456789abcdef", "73686172656420696e666f",
+		"6517dfe9cb0a01b3753846e7aaae86e422e7ddfb75019d9a364989b0c435c2c5616c47fa8e9651f87b0e87f0967a8320e221402e6d7b05a18f984a20a623d2803cedb9c2ba3354930e37d6e4a15a76d8c6d8aa1cfefbc5261e1a6b00b85267328ff261d2"},
+	{256, "", "",
+		"0f0014626736d9e3c1ce8a6c7bb840bd2e732343d250799d380c1c14a449325afecbc773694e50b252fe0eef5e61d2c6180deb0c9f2740b85b8876dcfc3c13b8e9830ed3b6cf460bbca3d82c91e411b0abf05e88ce843f88d3ea1868ae96b06675d94a1b"},
+	{256, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f60616263", "00010203040506",
+		"c6bcbb15aeb5addce52c61beaa0a662c94a556738a64b9b83b8a145eb84f2cbf75fcab293d1aa17193aabf8c87a8aba4500bfe17cac70e75fef28c9e1a8d6697a8c7d96ea9f0da68b186e96141f2684d4d39a4c9a054e10e3e3c47505a369e6da39d7b4a"},
+	{224, "0123456789abcdef0123456789abcdef", "73686172656420696e666f",
+		"7ee793e4bb43d5f541d37f2741fa0da27c37103eb0e81105a0dd0e08edda07e28238479e2cce61b974826782ca9cb3edf49325a839c56ab5108df79af6f10e02424dafe957acc52d8ee9b9fb9ec4467238639dce57e594561e52880557ecb4a6a881ce9e"},
+	{224, "", "",
+		"ba8ce6d9b6d4ee8c69f69ac751251e981cd34e2b0933a9c2f5e7aa8ecfbb98b0e1c231b0789ab6d0e474529a6b3fbf07a3bb250aa1edcc1ea656ac3f1280f0747bc2a659cc2ef2e9eeaf9888707fd978b21e25e1dd3c3d63fa45b3d642966d8feb68cf13"},
+	{224, "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f404142434445464748494a4b4c4d4e4f505152535455565758595a5b5c5d5e5f60616263", "00010203040506",
+		"92b269ad5ee857543f24643190ffe0a10238ad46c18d4363f4288012f4eb440a11b8e3480661f410f73496a650d1abb68fbca7fd8cf1540c22e00a566c5ab7bdc0ed21ab63cd7eb8a02d2ea8e53e4aa83e9eb03be2e80140248a0102d0800528822bbd8f"},
+}
+
+func testKDF(t *testing.T, name string, vectors []kdfVector,
+	kdf func(newHash func() hash.Hash, z, info []byte) io.Reader) {
+	for i, v := range vectors {
+		z, _ := hex.DecodeString(v.z)
+		info, _ := hex.DecodeString(v.info)
+		want, _ := hex.DecodeString(v.out)
+		newHash := New
+		if v.size == 224 {
+			newHash = New224
+		}
+
+		// Read in small pieces to cross block boundaries.
+		got, err := io.ReadAll(io.LimitReader(iotest.OneByteReader(kdf(newHash, z, info)),
+			int64(len(want))))
+		if err != nil {
+			t.Fatalf("%s %d: unexpected error: %v", name, i, err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s %d: expected %x, got %x", name, i, want, got)
+		}
+
+		// A nil hash defaults to BLAKE-256.
+		if v.size == 256 {
+			got := make([]byte, len(want))
+			if _, err := io.ReadFull(kdf(nil, z, info), got); err != nil {
+				t.Fatalf("%s %d: unexpected error: %v", name, i, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s %d: expected %x, got %x", name, i, want, got)
+			}
+		}
+	}
+}
+
+func TestMGF1(t *testing.T) {
+	testKDF(t, "MGF1", kdfVectorsMGF1, func(newHash func() hash.Hash, z, _ []byte) io.Reader {
+		return NewMGF1(newHash, z)
+	})
+}
+
+func TestX963KDF(t *testing.T) {
+	testKDF(t, "X9.63", kdfVectorsX963, NewX963KDF)
+}
+
+func TestOneStepKDF(t *testing.T) {
+	testKDF(t, "one-step", kdfVectorsOneStep, NewOneStepKDF)
+}
+
+func TestKDFExhausted(t *testing.T) {
+	k := newCounterKDF(nil, []byte("z"), nil, 0xfffffffe)
+	buf := make([]byte, 3*Size)
+	n, err := io.ReadFull(k, buf)
+	if n != 2*Size || err != ErrKDFExhausted {
+		t.Fatalf("expected %d bytes and %v, got %d and %v", 2*Size,
+			ErrKDFExhausted, n, err)
+	}
+	if _, err := k.Read(buf[:1]); err != ErrKDFExhausted {
+		t.Errorf("expected %v, got %v", ErrKDFExhausted, err)
+	}
+}
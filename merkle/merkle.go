@@ -0,0 +1,103 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package merkle implements Decred's BLAKE-256 Merkle trees and their
+// inclusion proofs.
+//
+// Each interior node is BLAKE-256(left || right). A level with an odd
+// number of nodes pairs its last node with itself. The root of a single
+// leaf is the leaf and the root of no leaves is the zero hash.
+package merkle
+
+import (
+	"github.com/rickiey/blake256"
+	"github.com/rickiey/blake256/wire"
+)
+
+// MaxProofDepth is the maximum length of an inclusion proof, for a tree of
+// 2^32 leaves.
+const MaxProofDepth = 32
+
+// hashPair returns the parent of two nodes.
+func hashPair(left, right *wire.Hash) wire.Hash {
+	var buf [2 * wire.HashSize]byte
+	copy(buf[:], left[:])
+	copy(buf[wire.HashSize:], right[:])
+	return blake256.Sum256x64(&buf)
+}
+
+// nextLevel replaces the nodes of a level with their parents and returns
+// them.
+func nextLevel(level []wire.Hash) []wire.Hash {
+	if len(level)%2 != 0 {
+		level = append(level, level[len(level)-1])
+	}
+	for i := 0; i < len(level)/2; i++ {
+		level[i] = hashPair(&level[2*i], &level[2*i+1])
+	}
+	return level[:len(level)/2]
+}
+
+// CalcMerkleRoot returns the root of the tree over leaves, such as the
+// transaction hashes of a block tree.
+func CalcMerkleRoot(leaves []wire.Hash) wire.Hash {
+	if len(leaves) == 0 {
+		return wire.Hash{}
+	}
+	level := make([]wire.Hash, len(leaves), len(leaves)+1)
+	copy(level, leaves)
+	for len(level) > 1 {
+		level = nextLevel(level)
+	}
+	return level[0]
+}
+
+// CalcCombinedTxTreeMerkleRoot returns the merkle root committed to by
+// headers after DCP-0005, the parent of the regular and stake tree roots.
+func CalcCombinedTxTreeMerkleRoot(regularRoot, stakeRoot *wire.Hash) wire.Hash {
+	return hashPair(regularRoot, stakeRoot)
+}
+
+// GenerateInclusionProof returns the proof that the leaf at leafIndex is in
+// the tree over leaves: the sibling of each node on the path from the leaf
+// to the root. It returns nil if leafIndex is out of range.
+func GenerateInclusionProof(leaves []wire.Hash, leafIndex uint32) []wire.Hash {
+	if uint64(leafIndex) >= uint64(len(leaves)) {
+		return nil
+	}
+	level := make([]wire.Hash, len(leaves), len(leaves)+1)
+	copy(level, leaves)
+	proof := make([]wire.Hash, 0, MaxProofDepth)
+	for idx := leafIndex; len(level) > 1; idx >>= 1 {
+		sibling := idx ^ 1
+		if int(sibling) >= len(level) {
+			sibling = idx
+		}
+		proof = append(proof, level[sibling])
+		level = nextLevel(level)
+	}
+	return proof
+}
+
+// VerifyInclusionProof reports whether proof shows that leaf is at leafIndex
+// in the tree with the given root.
+func VerifyInclusionProof(root, leaf *wire.Hash, leafIndex uint32, proof []wire.Hash) bool {
+	if len(proof) > MaxProofDepth {
+		return false
+	}
+	// The index must address a leaf at the depth of the proof.
+	if len(proof) < MaxProofDepth && leafIndex>>len(proof) != 0 {
+		return false
+	}
+	h := *leaf
+	for i := range proof {
+		if leafIndex&1 == 0 {
+			h = hashPair(&h, &proof[i])
+		} else {
+			h = hashPair(&proof[i], &h)
+		}
+		leafIndex >>= 1
+	}
+	return h == *root
+}
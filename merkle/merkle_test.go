@@ -0,0 +1,114 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package merkle
+
+import (
+	"testing"
+
+	"github.com/rickiey/blake256/wire"
+)
+
+// testLeaves returns n leaves, BLAKE-256 of each index byte.
+func testLeaves(n int) []wire.Hash {
+	leaves := make([]wire.Hash, n)
+	for i := range leaves {
+		leaves[i] = wire.HashH([]byte{byte(i)})
+	}
+	return leaves
+}
+
+// rootFromHex parses a hash in serialized byte order.
+func rootFromHex(t *testing.T, s string) wire.Hash {
+	t.Helper()
+	h, err := wire.NewHashFromStr(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// NewHashFromStr takes the byte-reversed display form.
+	var r wire.Hash
+	for i := range h {
+		r[i] = h[wire.HashSize-1-i]
+	}
+	return r
+}
+
+func TestCalcMerkleRoot(t *testing.T) {
+	tests := []struct {
+		n    int
+		root string
+	}{
+		{0, "0000000000000000000000000000000000000000000000000000000000000000"},
+		{1, "0ce8d4ef4dd7cd8d62dfded9d4edb0a774ae6a41929a74da23109e8f11139c87"},
+		{2, "ad204769bc6f9a916299fe1af8d54828b7403e2dbe65c8888e16e9fa1deae0c5"},
+		{3, "0135cd60725091a8795118c7ed50b8bf8a272707938a9fa7b24f0399bbe4a048"},
+		{5, "1a3f528d95b03c38767aaf2daab6ee3a97c3040adfffbb67ecb357d418b34517"},
+	}
+	for _, test := range tests {
+		leaves := testLeaves(test.n)
+		want := rootFromHex(t, test.root)
+		if got := CalcMerkleRoot(leaves); got != want {
+			t.Errorf("%d: expected %x, got %x", test.n, want[:], got[:])
+		}
+		// The leaves are not modified.
+		if test.n > 0 && leaves[0] != wire.HashH([]byte{0}) {
+			t.Errorf("%d: leaves modified", test.n)
+		}
+	}
+}
+
+func TestCombinedTxTreeMerkleRoot(t *testing.T) {
+	leaves := testLeaves(2)
+	got := CalcCombinedTxTreeMerkleRoot(&leaves[0], &leaves[1])
+	if want := CalcMerkleRoot(leaves); got != want {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+func TestInclusionProofs(t *testing.T) {
+	for n := 1; n <= 17; n++ {
+		leaves := testLeaves(n)
+		root := CalcMerkleRoot(leaves)
+		for i := range leaves {
+			idx := uint32(i)
+			proof := GenerateInclusionProof(leaves, idx)
+			if !VerifyInclusionProof(&root, &leaves[i], idx, proof) {
+				t.Fatalf("%d/%d: valid proof rejected", i, n)
+			}
+
+			// The proof does not verify another leaf or position.
+			other := wire.HashH([]byte("other"))
+			if VerifyInclusionProof(&root, &other, idx, proof) {
+				t.Fatalf("%d/%d: proof verified wrong leaf", i, n)
+			}
+			// A last node paired with itself verifies at either
+			// position, so only check leaves with a sibling.
+			if i^1 < n && VerifyInclusionProof(&root, &leaves[i], idx^1, proof) {
+				t.Fatalf("%d/%d: proof verified wrong index", i, n)
+			}
+			if VerifyInclusionProof(&root, &leaves[i], idx+1<<len(proof), proof) {
+				t.Fatalf("%d/%d: proof verified index beyond depth", i, n)
+			}
+			if len(proof) > 0 {
+				bad := append([]wire.Hash(nil), proof...)
+				bad[len(bad)-1][0] ^= 1
+				if VerifyInclusionProof(&root, &leaves[i], idx, bad) {
+					t.Fatalf("%d/%d: tampered proof verified", i, n)
+				}
+			}
+		}
+		if GenerateInclusionProof(leaves, uint32(n)) != nil {
+			t.Fatalf("%d: proof for out of range index", n)
+		}
+	}
+
+	// A single leaf is its own root with an empty proof.
+	leaves := testLeaves(1)
+	if p := GenerateInclusionProof(leaves, 0); len(p) != 0 {
+		t.Fatalf("expected empty proof, got %d hashes", len(p))
+	}
+	if VerifyInclusionProof(&leaves[0], &leaves[0], 0, make([]wire.Hash, MaxProofDepth+1)) {
+		t.Fatal("proof longer than the maximum depth verified")
+	}
+}
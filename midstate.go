@@ -0,0 +1,52 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+// HeaderMidstateSize is the number of leading block header bytes covered by
+// a header midstate. The nonce, timestamp and extra data all follow it.
+const HeaderMidstateSize = 2 * BlockSize
+
+// Midstate is a BLAKE-256 chain value after compressing whole leading blocks
+// of a message. Miners compute it once per block header since only the final
+// block varies between nonces.
+type Midstate [8]uint32
+
+// tail180 is the final block of a 180-byte message: 52 trailing message bytes
+// followed by the padding and the message length 1440. The message bytes are
+// copied over the leading zeros.
+var tail180 = [BlockSize]byte{52: 0x80, 55: 0x01, 62: 0x05, 63: 0xa0}
+
+// HeaderMidstate returns the midstate of a serialized block header after its
+// first HeaderMidstateSize bytes.
+func HeaderMidstate(header *[HeaderSize]byte) Midstate {
+	h := iv256
+	compressHeaderMidstate(&h, header)
+	return h
+}
+
+// HeaderFinalBlock returns the final block of a serialized block header: its
+// last 52 bytes followed by the BLAKE-256 padding.
+func HeaderFinalBlock(header *[HeaderSize]byte) [BlockSize]byte {
+	x := tail180
+	copy(x[:], header[HeaderMidstateSize:])
+	return x
+}
+
+// SumHeader returns the checksum of a block header given its midstate and
+// padded final block, as returned by HeaderMidstate and HeaderFinalBlock.
+// The padding in final is used as is.
+func (m *Midstate) SumHeader(final *[BlockSize]byte) [Size]byte {
+	h, s := [8]uint32(*m), [4]uint32{}
+	var w [16]uint32
+	loadWords(w[:], final[:])
+	compress(&h, &s, HeaderSize<<3, &w)
+	return sum256(&h)
+}
+
+// Bytes returns the midstate words in big-endian byte order, the order of a
+// BLAKE-256 checksum.
+func (m *Midstate) Bytes() [Size]byte {
+	return sum256((*[8]uint32)(m))
+}
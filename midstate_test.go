@@ -0,0 +1,61 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"encoding/hex"
+	"math/rand"
+	"testing"
+)
+
+func TestHeaderMidstate(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var header [HeaderSize]byte
+		rng.Read(header[:])
+
+		m := HeaderMidstate(&header)
+		final := HeaderFinalBlock(&header)
+		if got, want := m.SumHeader(&final), Sum256(header[:]); got != want {
+			t.Fatalf("SumHeader(%x): expected %x, got %x", header, want, got)
+		}
+
+		// The midstate is the state of a digest after the leading blocks.
+		d := New().(*digest)
+		d.Write(header[:HeaderMidstateSize])
+		if Midstate(d.h) != m {
+			t.Fatalf("%d: expected midstate %x, got %x", i, d.h, m)
+		}
+	}
+}
+
+func TestHeaderFinalBlockPadding(t *testing.T) {
+	var header [HeaderSize]byte
+	final := HeaderFinalBlock(&header)
+	want := "8000000100000000000005a0"
+	if got := hex.EncodeToString(final[HeaderSize-HeaderMidstateSize:]); got != want {
+		t.Fatalf("expected padding %s, got %s", want, got)
+	}
+}
+
+func TestMidstateBytes(t *testing.T) {
+	m := Midstate(iv256)
+	want := "6a09e667bb67ae853c6ef372a54ff53a510e527f9b05688c1f83d9ab5be0cd19"
+	b := m.Bytes()
+	if got := hex.EncodeToString(b[:]); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+}
+
+func BenchmarkMidstateSumHeader(b *testing.B) {
+	var header [HeaderSize]byte
+	m := HeaderMidstate(&header)
+	final := HeaderFinalBlock(&header)
+	b.SetBytes(BlockSize)
+	for i := 0; i < b.N; i++ {
+		final[140-HeaderMidstateSize] = byte(i)
+		_ = m.SumHeader(&final)
+	}
+}
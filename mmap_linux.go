@@ -0,0 +1,77 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package blake256
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"syscall"
+)
+
+const (
+	// mmapMinSize is the size below which files are read rather than
+	// memory-mapped since the mapping costs more than the copy it saves.
+	mmapMinSize = 1 << 20
+
+	// mmapStride is the amount of mapped data compressed at once. It is a
+	// multiple of BlockSize so the digest buffer is never used.
+	mmapStride = 4 << 20
+)
+
+// mapFile memory-maps f and writes its contents to the digest. It reports
+// false without consuming anything when f is not suitable for mapping, in
+// which case the caller falls back to reading it.
+func (d *digest) mapFile(f *os.File) (bool, error) {
+	fi, err := f.Stat()
+	if err != nil || !fi.Mode().IsRegular() {
+		return false, nil
+	}
+	size := fi.Size()
+	if size < mmapMinSize || int64(int(size)) != size {
+		return false, nil
+	}
+	return d.hashMapped(f, int(size))
+}
+
+// hashMapped maps the first size bytes of f and writes them to the digest.
+// If the file is truncated while it is being hashed, reading the mapping past
+// the new end of the file faults. The fault is recovered and returned as
+// io.ErrUnexpectedEOF instead of killing the process with SIGBUS, and the
+// digest is left with part of the file written to it.
+func (d *digest) hashMapped(f *os.File, size int) (ok bool, err error) {
+	data, err := syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ,
+		syscall.MAP_SHARED)
+	if err != nil {
+		return false, nil
+	}
+	defer syscall.Munmap(data)
+	defer debug.SetPanicOnFault(debug.SetPanicOnFault(true))
+	defer func() {
+		if r := recover(); r != nil {
+			if _, fault := r.(interface{ Addr() uintptr }); !fault {
+				panic(r)
+			}
+			ok, err = true, fmt.Errorf("blake256: %s changed while mapped: %w",
+				f.Name(), io.ErrUnexpectedEOF)
+		}
+	}()
+	_ = syscall.Madvise(data, syscall.MADV_SEQUENTIAL)
+
+	for len(data) > 0 {
+		n := len(data)
+		if n > mmapStride {
+			n = mmapStride
+		}
+		_ = syscall.Madvise(data[:n], syscall.MADV_WILLNEED)
+		d.Write(data[:n])
+		data = data[n:]
+	}
+	return true, nil
+}
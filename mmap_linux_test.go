@@ -0,0 +1,46 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build linux
+// +build linux
+
+package blake256
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHashMappedTruncated(t *testing.T) {
+	// Map more of the file than it holds, as when it is truncated after
+	// being mapped. Reading past the end faults, which must be returned as
+	// an error rather than crash the process.
+	path := filepath.Join(t.TempDir(), "data")
+	if err := os.WriteFile(path, make([]byte, mmapMinSize), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	d := digest{hashSize: 256, h: iv256}
+	ok, err := d.hashMapped(f, 2*mmapStride)
+	if !ok || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Fatalf("expected %v, got %v, %v", io.ErrUnexpectedEOF, ok, err)
+	}
+
+	// The whole file maps and hashes normally.
+	d = digest{hashSize: 256, h: iv256}
+	if ok, err := d.hashMapped(f, mmapMinSize); !ok || err != nil {
+		t.Fatalf("unexpected result %v, %v", ok, err)
+	}
+	if want := Sum256(make([]byte, mmapMinSize)); d.checkSum() != want {
+		t.Errorf("expected %x, got %x", want, d.checkSum())
+	}
+}
@@ -0,0 +1,16 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+//go:build !linux
+// +build !linux
+
+package blake256
+
+import "os"
+
+// mapFile always reports false since memory-mapped hashing is only supported
+// on Linux.
+func (d *digest) mapFile(f *os.File) (bool, error) {
+	return false, nil
+}
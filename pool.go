@@ -0,0 +1,79 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"hash"
+	"sync"
+)
+
+var (
+	// digestPool holds wiped digests for reuse.
+	digestPool = sync.Pool{
+		New: func() interface{} { return new(digest) },
+	}
+
+	// readBufferPool holds buffers of readBufferSize bytes for hashing from
+	// an io.Reader.
+	readBufferPool = sync.Pool{
+		New: func() interface{} {
+			buf := make([]byte, readBufferSize)
+			return &buf
+		},
+	}
+)
+
+// getDigest returns a pooled digest of the given hash size in bits, reset and
+// without salt.
+func getDigest(hashSize int) *digest {
+	d := digestPool.Get().(*digest)
+	d.hashSize = hashSize
+	d.Reset()
+	return d
+}
+
+// putDigest wipes d, including its salt and buffered data, and returns it to
+// the pool.
+func putDigest(d *digest) {
+	*d = digest{}
+	digestPool.Put(d)
+}
+
+// Acquire returns a BLAKE-256 hash.Hash from a pool of reusable hashes. Pass it
+// to Release once it is no longer needed so servers hashing per request do not
+// allocate.
+func Acquire() hash.Hash {
+	return getDigest(256)
+}
+
+// AcquireSalt is like Acquire but initializes salt with the given 16-byte
+// slice.
+func AcquireSalt(salt []byte) hash.Hash {
+	d := getDigest(256)
+	d.setSalt(salt)
+	return d
+}
+
+// Acquire224 is like Acquire but returns a BLAKE-224 hash.Hash.
+func Acquire224() hash.Hash {
+	return getDigest(224)
+}
+
+// Acquire224Salt is like Acquire224 but initializes salt with the given 16-byte
+// slice.
+func Acquire224Salt(salt []byte) hash.Hash {
+	d := getDigest(224)
+	d.setSalt(salt)
+	return d
+}
+
+// Release wipes the state of h, including its salt and any buffered data, and
+// returns it to the pool used by Acquire. h must not be used afterwards. Hashes
+// not created by this package are ignored.
+func Release(h hash.Hash) {
+	if d, ok := h.(*digest); ok {
+		putDigest(d)
+	}
+}
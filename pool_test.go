@@ -0,0 +1,74 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestAcquireRelease(t *testing.T) {
+	for i, v := range vectors256salt {
+		h := AcquireSalt([]byte(v.salt))
+		h.Write([]byte(v.in))
+		if res := fmt.Sprintf("%x", h.Sum(nil)); res != v.out {
+			t.Errorf("%d: expected %q, got %q", i, v.out, res)
+		}
+		h.Write([]byte("leftover"))
+		Release(h)
+
+		// The salt and data do not carry over to the next hash.
+		for j, v := range vectors256 {
+			h := Acquire()
+			h.Write([]byte(v.in))
+			if res := fmt.Sprintf("%x", h.Sum(nil)); res != v.out {
+				t.Errorf("%d/%d: expected %q, got %q", i, j, v.out, res)
+			}
+			Release(h)
+		}
+		for j, v := range vectors224 {
+			h := Acquire224()
+			h.Write([]byte(v.in))
+			if res := fmt.Sprintf("%x", h.Sum(nil)); res != v.out {
+				t.Errorf("%d/%d: expected %q, got %q", i, j, v.out, res)
+			}
+			Release(h)
+		}
+	}
+
+	h := Acquire224Salt(make([]byte, 16))
+	if h.Size() != Size224 {
+		t.Errorf("expected size %d, got %d", Size224, h.Size())
+	}
+	Release(h)
+}
+
+func TestAppendSum(t *testing.T) {
+	for i, v := range vectors256 {
+		sum := Sum256([]byte(v.in))
+		want := append([]byte("prefix"), sum[:]...)
+		if dst := AppendSum256([]byte("prefix"), []byte(v.in)); !bytes.Equal(dst, want) {
+			t.Errorf("%d: expected %x, got %x", i, want, dst)
+		}
+	}
+	for i, v := range vectors224 {
+		dst := AppendSum224(nil, []byte(v.in))
+		if res := fmt.Sprintf("%x", dst); res != v.out {
+			t.Errorf("%d: expected %q, got %q", i, v.out, res)
+		}
+	}
+
+	buf := make([]byte, 0, Size)
+	allocs := testing.AllocsPerRun(100, func() {
+		buf = AppendSum256(buf[:0], bufIn[:1024])
+	})
+	if allocs != 0 {
+		t.Errorf("expected no allocations, got %v", allocs)
+	}
+	if want := Sum256(bufIn[:1024]); !bytes.Equal(buf, want[:]) {
+		t.Errorf("expected %x, got %x", want, buf)
+	}
+}
@@ -0,0 +1,211 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package pow implements Decred proof-of-work difficulty utilities: the
+// conversion between compact "bits" and 256-bit targets, checking a block
+// hash against a target, and work and hash rate calculations.
+//
+// All arithmetic uses the fixed-size Uint256 type so that the hash check can
+// run inside a nonce-search loop without allocating.
+package pow
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rickiey/blake256/wire"
+)
+
+// Proof-of-work limits of the Decred networks. A block target may not exceed
+// the limit of its network.
+var (
+	// MainNetPowLimit is the highest target on the main network, 2^224 - 1.
+	MainNetPowLimit = Uint256{^uint64(0), ^uint64(0), ^uint64(0), 0xffffffff}
+
+	// TestNet3PowLimit is the highest target on the test network,
+	// 2^232 - 1.
+	TestNet3PowLimit = Uint256{^uint64(0), ^uint64(0), ^uint64(0), 0xffffffffff}
+
+	// SimNetPowLimit is the highest target on the simulation and
+	// regression test networks, 2^255 - 1.
+	SimNetPowLimit = Uint256{^uint64(0), ^uint64(0), ^uint64(0), 1<<63 - 1}
+)
+
+var (
+	// ErrInvalidBits is returned when compact bits encode a negative, zero
+	// or overflowing target.
+	ErrInvalidBits = errors.New("pow: invalid target difficulty bits")
+
+	// ErrTargetAboveLimit is returned when a target exceeds the
+	// proof-of-work limit of the network.
+	ErrTargetAboveLimit = errors.New("pow: target above proof-of-work limit")
+
+	// ErrHighHash is returned when a block hash is above its target.
+	ErrHighHash = errors.New("pow: block hash above target")
+)
+
+// CompactToTarget converts compact bits to a 256-bit target. The compact
+// form is a base-256 floating point number: the high byte is the exponent,
+// bit 23 is the sign and the low 23 bits are the mantissa, so the value is
+// mantissa * 256^(exponent-3).
+//
+// A nonzero value with the sign bit set is reported as negative, and a value
+// that does not fit in 256 bits as overflowing. The target is zero in either
+// case.
+func CompactToTarget(bits uint32) (target Uint256, isNegative, overflows bool) {
+	mantissa := bits & 0x007fffff
+	exponent := uint(bits >> 24)
+
+	if exponent <= 3 {
+		mantissa >>= 8 * (3 - exponent)
+		target[0] = uint64(mantissa)
+	} else {
+		overflows = mantissa != 0 && (exponent > 34 ||
+			(mantissa > 0xff && exponent > 33) ||
+			(mantissa > 0xffff && exponent > 32))
+		if !overflows {
+			target[0] = uint64(mantissa)
+			target.Lsh(8 * (exponent - 3))
+		}
+	}
+
+	isNegative = mantissa != 0 && bits&0x00800000 != 0
+	if isNegative || overflows {
+		target = Uint256{}
+	}
+	return target, isNegative, overflows
+}
+
+// TargetToCompact converts a target to compact bits, truncating it to the
+// three most significant bytes. The sign bit is never set: a mantissa that
+// would set it is shifted down a byte and the exponent increased.
+func TargetToCompact(target *Uint256) uint32 {
+	if target.IsZero() {
+		return 0
+	}
+
+	exponent := uint(target.BitLen()+7) / 8
+	var mantissa uint32
+	if exponent <= 3 {
+		mantissa = uint32(target.Uint64()) << (8 * (3 - exponent))
+	} else {
+		t := *target
+		mantissa = uint32(t.Rsh(8 * (exponent - 3)).Uint64())
+	}
+
+	if mantissa&0x00800000 != 0 {
+		mantissa >>= 8
+		exponent++
+	}
+	return uint32(exponent<<24) | mantissa
+}
+
+// HashToUint256 interprets a block hash as a little-endian integer, the
+// order in which it is compared against the target.
+func HashToUint256(hash *[32]byte) Uint256 {
+	var n Uint256
+	n.SetBytesLE(hash)
+	return n
+}
+
+// HashMeetsTarget reports whether hash, interpreted as a little-endian
+// integer, is not above target. This is the per-nonce check of a miner.
+func HashMeetsTarget(hash *[32]byte, target *Uint256) bool {
+	n := HashToUint256(hash)
+	return n.Cmp(target) <= 0
+}
+
+// CheckProofOfWorkRange checks that bits encode a valid positive target no
+// higher than powLimit.
+func CheckProofOfWorkRange(bits uint32, powLimit *Uint256) error {
+	_, err := targetFromBits(bits, powLimit)
+	return err
+}
+
+// targetFromBits returns the target encoded by bits after range checking it
+// against powLimit.
+func targetFromBits(bits uint32, powLimit *Uint256) (Uint256, error) {
+	target, isNegative, overflows := CompactToTarget(bits)
+	switch {
+	case isNegative:
+		return target, fmt.Errorf("%w: %08x is negative", ErrInvalidBits, bits)
+	case overflows:
+		return target, fmt.Errorf("%w: %08x overflows", ErrInvalidBits, bits)
+	case target.IsZero():
+		return target, fmt.Errorf("%w: %08x is zero", ErrInvalidBits, bits)
+	case target.Cmp(powLimit) > 0:
+		return target, fmt.Errorf("%w: %v > %v", ErrTargetAboveLimit,
+			target, powLimit)
+	}
+	return target, nil
+}
+
+// CheckProofOfWork checks that bits are in range for powLimit and that hash,
+// interpreted as a little-endian integer, is not above the target they
+// encode. A hash equal to the target is valid, as in consensus.
+func CheckProofOfWork(hash *[32]byte, bits uint32, powLimit *Uint256) error {
+	target, err := targetFromBits(bits, powLimit)
+	if err != nil {
+		return err
+	}
+	if !HashMeetsTarget(hash, &target) {
+		h := wire.Hash(*hash)
+		return fmt.Errorf("%w: block hash %v, target %v", ErrHighHash, h,
+			target)
+	}
+	return nil
+}
+
+// CheckHeaderProofOfWork checks the proof of work of a block header.
+func CheckHeaderProofOfWork(header *wire.BlockHeader, powLimit *Uint256) error {
+	hash := header.BlockHash()
+	return CheckProofOfWork((*[32]byte)(&hash), header.Bits, powLimit)
+}
+
+// CalcWork returns the work represented by a block with the given bits, the
+// expected number of hashes to find it: 2^256 / (target + 1). Bits that
+// encode a negative or zero target represent no work.
+func CalcWork(bits uint32) Uint256 {
+	target, isNegative, overflows := CompactToTarget(bits)
+	if isNegative || overflows || target.IsZero() {
+		return Uint256{}
+	}
+
+	// 2^256 does not fit in 256 bits, so compute the equivalent
+	// (2^256 - target - 1) / (target + 1) + 1.
+	one := NewUint256(1)
+	work := target
+	work.Not()
+	target.Add(&one)
+	work.Div(&target)
+	return *work.Add(&one)
+}
+
+// CalcCumulativeWork returns the total work of a chain of blocks with the
+// given bits.
+func CalcCumulativeWork(bits []uint32) Uint256 {
+	var total Uint256
+	for _, b := range bits {
+		w := CalcWork(b)
+		total.Add(&w)
+	}
+	return total
+}
+
+// HashesPerSecond returns the rate at which hashes were performed over
+// elapsed. It returns zero when elapsed is not positive.
+func HashesPerSecond(hashes *Uint256, elapsed time.Duration) float64 {
+	if elapsed <= 0 {
+		return 0
+	}
+	return hashes.Float64() / elapsed.Seconds()
+}
+
+// NetworkHashRate estimates the hash rate of a network that finds blocks
+// with the given bits every blockTime on average.
+func NetworkHashRate(bits uint32, blockTime time.Duration) float64 {
+	work := CalcWork(bits)
+	return HashesPerSecond(&work, blockTime)
+}
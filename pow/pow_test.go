@@ -0,0 +1,216 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pow
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rickiey/blake256/wire"
+)
+
+// zeros returns n zero hex digits.
+func zeros(n int) string {
+	return strings.Repeat("0", n)
+}
+
+func TestCompactToTarget(t *testing.T) {
+	tests := []struct {
+		bits       uint32
+		target     string // big-endian hex without leading zeros
+		isNegative bool
+		overflows  bool
+		compact    uint32 // TargetToCompact of the target
+	}{
+		{0x00000000, "0", false, false, 0},
+		{0x00123456, "0", false, false, 0},
+		{0x01003456, "0", false, false, 0},
+		{0x02000056, "0", false, false, 0},
+		{0x03000000, "0", false, false, 0},
+		{0x04000000, "0", false, false, 0},
+		{0x00923456, "0", false, false, 0},
+		{0x01803456, "0", false, false, 0},
+		{0x02800056, "0", false, false, 0},
+		{0x03800000, "0", false, false, 0},
+		{0x04800000, "0", false, false, 0},
+		{0x01123456, "12", false, false, 0x01120000},
+		{0x01fedcba, "0", true, false, 0},
+		{0x02123456, "1234", false, false, 0x02123400},
+		{0x03123456, "123456", false, false, 0x03123456},
+		{0x04123456, "12345600", false, false, 0x04123456},
+		{0x04923456, "0", true, false, 0},
+		{0x05009234, "92340000", false, false, 0x05009234},
+		{0x20123456, "1234560000000000000000000000000000000000000000000000000000000000",
+			false, false, 0x20123456},
+		{0x1d00ffff, "ffff0000000000000000000000000000000000000000000000000000", false,
+			false, 0x1d00ffff},
+		{0x22000001, "1" + zeros(62), false, false, 0x20010000},
+		{0x21000100, "1" + zeros(62), false, false, 0x20010000},
+		{0x20010000, "1" + zeros(62), false, false, 0x20010000},
+		{0x23000001, "0", false, true, 0},
+		{0x22000100, "0", false, true, 0},
+		{0x21010000, "0", false, true, 0},
+		{0xff123456, "0", false, true, 0},
+		{0xff000000, "0", false, false, 0},
+	}
+	for _, test := range tests {
+		target, neg, ovf := CompactToTarget(test.bits)
+		want, _ := new(big.Int).SetString(test.target, 16)
+		if toBig(&target).Cmp(want) != 0 {
+			t.Errorf("%08x: expected target %x, got %v", test.bits, want, target)
+		}
+		if neg != test.isNegative || ovf != test.overflows {
+			t.Errorf("%08x: expected negative %v overflows %v, got %v %v",
+				test.bits, test.isNegative, test.overflows, neg, ovf)
+		}
+		if got := TargetToCompact(&target); got != test.compact {
+			t.Errorf("%08x: expected compact %08x, got %08x", test.bits,
+				test.compact, got)
+		}
+	}
+}
+
+func TestPowLimitBits(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit Uint256
+		bits  uint32
+		exp   uint
+	}{
+		{"mainnet", MainNetPowLimit, 0x1d00ffff, 224},
+		{"testnet3", TestNet3PowLimit, 0x1e00ffff, 232},
+		{"simnet", SimNetPowLimit, 0x207fffff, 255},
+	}
+	for _, test := range tests {
+		want := new(big.Int).Lsh(big.NewInt(1), test.exp)
+		want.Sub(want, big.NewInt(1))
+		if toBig(&test.limit).Cmp(want) != 0 {
+			t.Errorf("%s: expected %x, got %v", test.name, want, test.limit)
+		}
+		if got := TargetToCompact(&test.limit); got != test.bits {
+			t.Errorf("%s: expected %08x, got %08x", test.name, test.bits, got)
+		}
+	}
+}
+
+func TestCheckProofOfWork(t *testing.T) {
+	// The genesis block was not mined and does not meet its own target.
+	merkle, err := wire.NewHashFromStr("66aa7491b9adce110585ccab7e3fb5fe280de174530cca10eba2c6c3df01c10d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := &wire.BlockHeader{
+		Version:    1,
+		MerkleRoot: *merkle,
+		Bits:       0x1b01ffff,
+		SBits:      2 * 1e8,
+		Timestamp:  time.Unix(1454954400, 0),
+	}
+	err = CheckHeaderProofOfWork(genesis, &MainNetPowLimit)
+	if !errors.Is(err, ErrHighHash) {
+		t.Fatalf("expected %v, got %v", ErrHighHash, err)
+	}
+
+	// Search for a nonce meeting the simnet limit.
+	h := *genesis
+	h.Bits = 0x207fffff
+	for CheckHeaderProofOfWork(&h, &SimNetPowLimit) != nil {
+		h.Nonce++
+	}
+	hash := h.BlockHash()
+	if hash[31]&0x80 != 0 {
+		t.Fatalf("accepted hash %v above simnet limit", hash)
+	}
+
+	// A hash equal to the target is valid, one above it is not.
+	target, _, _ := CompactToTarget(0x1d00ffff)
+	b := target.Bytes()
+	var le [32]byte
+	for i := range b {
+		le[i] = b[31-i]
+	}
+	if err := CheckProofOfWork(&le, 0x1d00ffff, &MainNetPowLimit); err != nil {
+		t.Fatalf("hash equal to target: %v", err)
+	}
+	le[0]++
+	err = CheckProofOfWork(&le, 0x1d00ffff, &MainNetPowLimit)
+	if !errors.Is(err, ErrHighHash) {
+		t.Fatalf("expected %v, got %v", ErrHighHash, err)
+	}
+
+	var zero [32]byte
+	errTests := []struct {
+		bits uint32
+		err  error
+	}{
+		{0x04923456, ErrInvalidBits},
+		{0xff123456, ErrInvalidBits},
+		{0x01003456, ErrInvalidBits},
+		{0x1e00ffff, ErrTargetAboveLimit},
+	}
+	for _, test := range errTests {
+		err := CheckProofOfWork(&zero, test.bits, &MainNetPowLimit)
+		if !errors.Is(err, test.err) {
+			t.Errorf("%08x: expected %v, got %v", test.bits, test.err, err)
+		}
+		if err := CheckProofOfWorkRange(test.bits, &MainNetPowLimit); !errors.Is(err, test.err) {
+			t.Errorf("%08x: expected %v, got %v", test.bits, test.err, err)
+		}
+	}
+}
+
+func TestCalcWork(t *testing.T) {
+	work := CalcWork(0x1d00ffff)
+	if work != NewUint256(0x100010001) {
+		t.Fatalf("expected %x, got %v", uint64(0x100010001), work)
+	}
+
+	for _, bits := range []uint32{0x1b01ffff, 0x207fffff, 0x03123456, 0x180eb3d8, 0x2100ffff} {
+		target, _, _ := CompactToTarget(bits)
+		want := new(big.Int).Add(toBig(&target), big.NewInt(1))
+		want.Quo(two256, want)
+		work := CalcWork(bits)
+		if toBig(&work).Cmp(want) != 0 {
+			t.Errorf("%08x: expected %x, got %v", bits, want, work)
+		}
+	}
+
+	for _, bits := range []uint32{0, 0x04923456, 0xff123456} {
+		if work := CalcWork(bits); !work.IsZero() {
+			t.Errorf("%08x: expected no work, got %v", bits, work)
+		}
+	}
+
+	total := CalcCumulativeWork([]uint32{0x1d00ffff, 0x1d00ffff, 0x04923456})
+	if total != NewUint256(2*0x100010001) {
+		t.Fatalf("expected %x, got %v", uint64(2*0x100010001), total)
+	}
+}
+
+func TestHashRate(t *testing.T) {
+	rate := NetworkHashRate(0x1d00ffff, 5*time.Minute)
+	if want := float64(0x100010001) / 300; rate != want {
+		t.Fatalf("expected %g, got %g", want, rate)
+	}
+	n := NewUint256(1000)
+	if got := HashesPerSecond(&n, 2*time.Second); got != 500 {
+		t.Fatalf("expected 500, got %g", got)
+	}
+	if got := HashesPerSecond(&n, 0); got != 0 {
+		t.Fatalf("expected 0, got %g", got)
+	}
+}
+
+func BenchmarkHashMeetsTarget(b *testing.B) {
+	target, _, _ := CompactToTarget(0x1b01ffff)
+	var hash [32]byte
+	for i := 0; i < b.N; i++ {
+		hash[0] = byte(i)
+		HashMeetsTarget(&hash, &target)
+	}
+}
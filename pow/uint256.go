@@ -0,0 +1,211 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pow
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math/bits"
+)
+
+// Uint256 is an unsigned 256-bit integer with arithmetic modulo 2^256. It is
+// stored as four 64-bit words, least significant first, and its methods do
+// not allocate.
+type Uint256 [4]uint64
+
+// NewUint256 returns v as a Uint256.
+func NewUint256(v uint64) Uint256 {
+	return Uint256{v}
+}
+
+// SetBytesLE sets n to the little-endian value of b, such as a block hash.
+func (n *Uint256) SetBytesLE(b *[32]byte) *Uint256 {
+	n[0] = binary.LittleEndian.Uint64(b[0:])
+	n[1] = binary.LittleEndian.Uint64(b[8:])
+	n[2] = binary.LittleEndian.Uint64(b[16:])
+	n[3] = binary.LittleEndian.Uint64(b[24:])
+	return n
+}
+
+// SetBytes sets n to the big-endian value of b, which must be at most 32
+// bytes. Any additional leading bytes are ignored.
+func (n *Uint256) SetBytes(b []byte) *Uint256 {
+	if len(b) > 32 {
+		b = b[len(b)-32:]
+	}
+	var buf [32]byte
+	copy(buf[32-len(b):], b)
+	n[3] = binary.BigEndian.Uint64(buf[0:])
+	n[2] = binary.BigEndian.Uint64(buf[8:])
+	n[1] = binary.BigEndian.Uint64(buf[16:])
+	n[0] = binary.BigEndian.Uint64(buf[24:])
+	return n
+}
+
+// Bytes returns the 32-byte big-endian encoding of n.
+func (n *Uint256) Bytes() [32]byte {
+	var b [32]byte
+	binary.BigEndian.PutUint64(b[0:], n[3])
+	binary.BigEndian.PutUint64(b[8:], n[2])
+	binary.BigEndian.PutUint64(b[16:], n[1])
+	binary.BigEndian.PutUint64(b[24:], n[0])
+	return b
+}
+
+// String returns n as 64 big-endian hex digits.
+func (n Uint256) String() string {
+	b := n.Bytes()
+	return hex.EncodeToString(b[:])
+}
+
+// IsZero reports whether n is zero.
+func (n *Uint256) IsZero() bool {
+	return n[0]|n[1]|n[2]|n[3] == 0
+}
+
+// IsUint64 reports whether n fits in a uint64.
+func (n *Uint256) IsUint64() bool {
+	return n[1]|n[2]|n[3] == 0
+}
+
+// Uint64 returns the low 64 bits of n.
+func (n *Uint256) Uint64() uint64 {
+	return n[0]
+}
+
+// Cmp returns -1, 0 or +1 when n is less than, equal to or greater than m.
+func (n *Uint256) Cmp(m *Uint256) int {
+	for i := 3; i >= 0; i-- {
+		switch {
+		case n[i] < m[i]:
+			return -1
+		case n[i] > m[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// BitLen returns the number of bits required to represent n.
+func (n *Uint256) BitLen() int {
+	for i := 3; i >= 0; i-- {
+		if n[i] != 0 {
+			return i*64 + bits.Len64(n[i])
+		}
+	}
+	return 0
+}
+
+// Add sets n to n + m and returns n.
+func (n *Uint256) Add(m *Uint256) *Uint256 {
+	var c uint64
+	n[0], c = bits.Add64(n[0], m[0], 0)
+	n[1], c = bits.Add64(n[1], m[1], c)
+	n[2], c = bits.Add64(n[2], m[2], c)
+	n[3], _ = bits.Add64(n[3], m[3], c)
+	return n
+}
+
+// Sub sets n to n - m and returns n.
+func (n *Uint256) Sub(m *Uint256) *Uint256 {
+	var b uint64
+	n[0], b = bits.Sub64(n[0], m[0], 0)
+	n[1], b = bits.Sub64(n[1], m[1], b)
+	n[2], b = bits.Sub64(n[2], m[2], b)
+	n[3], _ = bits.Sub64(n[3], m[3], b)
+	return n
+}
+
+// Not sets n to its bitwise complement, 2^256 - 1 - n, and returns n.
+func (n *Uint256) Not() *Uint256 {
+	n[0], n[1], n[2], n[3] = ^n[0], ^n[1], ^n[2], ^n[3]
+	return n
+}
+
+// Lsh sets n to n << s and returns n.
+func (n *Uint256) Lsh(s uint) *Uint256 {
+	if s >= 256 {
+		*n = Uint256{}
+		return n
+	}
+	words, s := int(s/64), s%64
+	for i := 3; i >= 0; i-- {
+		var v uint64
+		if j := i - words; j >= 0 {
+			v = n[j] << s
+			if s != 0 && j > 0 {
+				v |= n[j-1] >> (64 - s)
+			}
+		}
+		n[i] = v
+	}
+	return n
+}
+
+// Rsh sets n to n >> s and returns n.
+func (n *Uint256) Rsh(s uint) *Uint256 {
+	if s >= 256 {
+		*n = Uint256{}
+		return n
+	}
+	words, s := int(s/64), s%64
+	for i := 0; i < 4; i++ {
+		var v uint64
+		if j := i + words; j < 4 {
+			v = n[j] >> s
+			if s != 0 && j < 3 {
+				v |= n[j+1] << (64 - s)
+			}
+		}
+		n[i] = v
+	}
+	return n
+}
+
+// Div sets n to the quotient n / m and returns n. It panics if m is zero.
+func (n *Uint256) Div(m *Uint256) *Uint256 {
+	if m.IsZero() {
+		panic("pow: division by zero")
+	}
+	if n.Cmp(m) < 0 {
+		*n = Uint256{}
+		return n
+	}
+
+	// Divide word by word when the divisor fits in 64 bits.
+	if m.IsUint64() {
+		var r uint64
+		for i := 3; i >= 0; i-- {
+			n[i], r = bits.Div64(r, n[i], m[0])
+		}
+		return n
+	}
+
+	// Otherwise use shift-and-subtract long division, starting with the
+	// divisor aligned to the top bit of the dividend.
+	shift := uint(n.BitLen() - m.BitLen())
+	d := *m
+	d.Lsh(shift)
+	r := *n
+	var q Uint256
+	for i := int(shift); i >= 0; i-- {
+		q.Lsh(1)
+		if r.Cmp(&d) >= 0 {
+			r.Sub(&d)
+			q[0] |= 1
+		}
+		d.Rsh(1)
+	}
+	*n = q
+	return n
+}
+
+// Float64 returns n as a float64. The result may differ from the nearest
+// float64 in the last bit, which is fine for estimates.
+func (n *Uint256) Float64() float64 {
+	const two64 = 1 << 64
+	return ((float64(n[3])*two64+float64(n[2]))*two64+float64(n[1]))*two64 +
+		float64(n[0])
+}
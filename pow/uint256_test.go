@@ -0,0 +1,138 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package pow
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+var two256 = new(big.Int).Lsh(big.NewInt(1), 256)
+
+// toBig returns n as a big.Int.
+func toBig(n *Uint256) *big.Int {
+	b := n.Bytes()
+	return new(big.Int).SetBytes(b[:])
+}
+
+// randUint256 returns a random value with a random bit length so that small
+// and large operands are both covered.
+func randUint256(rng *rand.Rand) Uint256 {
+	var n Uint256
+	for i := range n {
+		n[i] = rng.Uint64()
+	}
+	n.Rsh(uint(rng.Intn(256)))
+	return n
+}
+
+func TestUint256Bytes(t *testing.T) {
+	var n Uint256
+	n.SetBytes([]byte{0x01, 0x02, 0x03})
+	if n != NewUint256(0x010203) {
+		t.Fatalf("expected %v, got %v", NewUint256(0x010203), n)
+	}
+	want := "0000000000000000000000000000000000000000000000000000000000010203"
+	if got := n.String(); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+
+	var le [32]byte
+	le[0], le[31] = 0x01, 0x80
+	n.SetBytesLE(&le)
+	if n[0] != 1 || n[3] != 0x80<<56 || n[1] != 0 || n[2] != 0 {
+		t.Fatalf("unexpected little-endian value %v", n)
+	}
+	b := n.Bytes()
+	n2 := new(Uint256).SetBytes(append([]byte{0xff}, b[:]...))
+	if *n2 != n {
+		t.Fatalf("expected %v, got %v", n, *n2)
+	}
+}
+
+func TestUint256Arithmetic(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		a, b := randUint256(rng), randUint256(rng)
+		ab, bb := toBig(&a), toBig(&b)
+
+		if got, want := a.Cmp(&b), ab.Cmp(bb); got != want {
+			t.Fatalf("%v cmp %v: expected %d, got %d", a, b, want, got)
+		}
+		if got, want := a.BitLen(), ab.BitLen(); got != want {
+			t.Fatalf("%v bitlen: expected %d, got %d", a, want, got)
+		}
+
+		sum := a
+		sum.Add(&b)
+		want := new(big.Int).Add(ab, bb)
+		want.Mod(want, two256)
+		if toBig(&sum).Cmp(want) != 0 {
+			t.Fatalf("%v + %v: expected %x, got %v", a, b, want, sum)
+		}
+
+		diff := a
+		diff.Sub(&b)
+		want.Sub(ab, bb).Mod(want, two256)
+		if toBig(&diff).Cmp(want) != 0 {
+			t.Fatalf("%v - %v: expected %x, got %v", a, b, want, diff)
+		}
+
+		s := uint(rng.Intn(300))
+		lsh := a
+		lsh.Lsh(s)
+		want.Lsh(ab, s).Mod(want, two256)
+		if toBig(&lsh).Cmp(want) != 0 {
+			t.Fatalf("%v << %d: expected %x, got %v", a, s, want, lsh)
+		}
+		rsh := a
+		rsh.Rsh(s)
+		want.Rsh(ab, s)
+		if toBig(&rsh).Cmp(want) != 0 {
+			t.Fatalf("%v >> %d: expected %x, got %v", a, s, want, rsh)
+		}
+
+		if b.IsZero() {
+			continue
+		}
+		quo := a
+		quo.Div(&b)
+		want.Quo(ab, bb)
+		if toBig(&quo).Cmp(want) != 0 {
+			t.Fatalf("%v / %v: expected %x, got %v", a, b, want, quo)
+		}
+		small := NewUint256(b[0] | 1)
+		quo = a
+		quo.Div(&small)
+		want.Quo(ab, toBig(&small))
+		if toBig(&quo).Cmp(want) != 0 {
+			t.Fatalf("%v / %v: expected %x, got %v", a, small, want, quo)
+		}
+	}
+}
+
+func TestUint256Float64(t *testing.T) {
+	n := NewUint256(1)
+	n.Lsh(200)
+	want, _ := new(big.Float).SetInt(new(big.Int).Lsh(big.NewInt(1), 200)).Float64()
+	if got := n.Float64(); got != want {
+		t.Fatalf("expected %g, got %g", want, got)
+	}
+	n = NewUint256(12345)
+	if got := n.Float64(); got != 12345 {
+		t.Fatalf("expected 12345, got %g", got)
+	}
+}
+
+func TestUint256DivByZero(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	n := NewUint256(1)
+	n.Div(&Uint256{})
+}
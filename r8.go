@@ -0,0 +1,84 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import "hash"
+
+// sigma holds the message word permutations of the rounds. Round r uses
+// sigma[r%10].
+var sigma = [10][16]uint8{
+	{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15},
+	{14, 10, 4, 8, 9, 15, 13, 6, 1, 12, 0, 2, 11, 7, 5, 3},
+	{11, 8, 12, 0, 5, 2, 15, 13, 10, 14, 3, 6, 7, 1, 9, 4},
+	{7, 9, 3, 1, 13, 12, 11, 14, 2, 6, 5, 10, 4, 0, 15, 8},
+	{9, 0, 5, 7, 2, 4, 10, 15, 14, 1, 11, 12, 6, 8, 3, 13},
+	{2, 12, 6, 10, 0, 11, 8, 3, 4, 13, 7, 5, 15, 14, 1, 9},
+	{12, 5, 1, 15, 14, 13, 4, 10, 0, 7, 6, 3, 9, 2, 8, 11},
+	{13, 11, 7, 14, 12, 1, 3, 9, 5, 0, 15, 4, 8, 6, 2, 10},
+	{6, 15, 14, 9, 11, 3, 0, 8, 12, 2, 13, 7, 1, 4, 10, 5},
+	{10, 2, 8, 4, 7, 6, 1, 5, 15, 11, 9, 14, 3, 12, 13, 0},
+}
+
+var cst = [16]uint32{
+	cst0, cst1, cst2, cst3, cst4, cst5, cst6, cst7,
+	cst8, cst9, cst10, cst11, cst12, cst13, cst14, cst15,
+}
+
+// compressRounds is like compress but runs the given number of rounds. It is
+// used by the reduced-round variants, which are not worth unrolling.
+func compressRounds(h *[8]uint32, s *[4]uint32, t uint64, m *[16]uint32, rounds int) {
+	var v [16]uint32
+	copy(v[:8], h[:])
+	v[8] = cst0 ^ s[0]
+	v[9] = cst1 ^ s[1]
+	v[10] = cst2 ^ s[2]
+	v[11] = cst3 ^ s[3]
+	v[12] = cst4 ^ uint32(t)
+	v[13] = cst5 ^ uint32(t)
+	v[14] = cst6 ^ uint32(t>>32)
+	v[15] = cst7 ^ uint32(t>>32)
+
+	g := func(sg *[16]uint8, i, a, b, c, d int) {
+		x, y := sg[2*i], sg[2*i+1]
+		v[a] += v[b] + (m[x] ^ cst[y])
+		v[d] ^= v[a]
+		v[d] = v[d]<<(32-16) | v[d]>>16
+		v[c] += v[d]
+		v[b] ^= v[c]
+		v[b] = v[b]<<(32-12) | v[b]>>12
+		v[a] += v[b] + (m[y] ^ cst[x])
+		v[d] ^= v[a]
+		v[d] = v[d]<<(32-8) | v[d]>>8
+		v[c] += v[d]
+		v[b] ^= v[c]
+		v[b] = v[b]<<(32-7) | v[b]>>7
+	}
+	for r := 0; r < rounds; r++ {
+		sg := &sigma[r%10]
+		g(sg, 0, 0, 4, 8, 12)
+		g(sg, 1, 1, 5, 9, 13)
+		g(sg, 2, 2, 6, 10, 14)
+		g(sg, 3, 3, 7, 11, 15)
+		g(sg, 4, 0, 5, 10, 15)
+		g(sg, 5, 1, 6, 11, 12)
+		g(sg, 6, 2, 7, 8, 13)
+		g(sg, 7, 3, 4, 9, 14)
+	}
+
+	for i := range h {
+		h[i] ^= v[i] ^ v[i+8] ^ s[i%4]
+	}
+}
+
+// NewR8 returns a new hash.Hash computing the checksum of BLAKE-256r8, the
+// BLAKE-256 variant reduced to 8 rounds that some cryptocurrencies use for
+// proof of work. It is not a substitute for BLAKE-256.
+func NewR8() hash.Hash {
+	return &digest{
+		hashSize: 256,
+		rounds:   8,
+		h:        iv256,
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"encoding"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// vectors256r8 were computed with an independent implementation of the BLAKE
+// specification reduced to 8 rounds.
+//
+//nolint:misspell
+var vectors256r8 = []blakeVector{
+	{"7e0cf6c8cb29e0add69c48891400219737c1632a7782161ac02f27ee78826038",
+		"The quick brown fox jumps over the lazy dog"},
+	{"f454cfde6e490eaa7188394fcd271d8c3c33308ef97965aadd1a20f48c90a95a",
+		"BLAKE"},
+	{"5aca53d736759ea025a31d76c31bc18933f480416e200a935a89fc31d3964998",
+		""},
+	{"b8d2df61ee214044a3cce6751bbfded65bab6fc53d67342382485e8a5e9c558e",
+		"Go"},
+	{"2f325031dc1d460f3cc3239ae6bda709215d6ac5d279fec3daf9727bff4e2d81", // test with one padding byte
+		"Lorem ipsum dolor sit amet, consectetur adipiscing elit. Donec a diam lectus. Sed sit amet ipsum mauris. Maecenas congu",
+	},
+	{"505b34d77a2bc945d04c7046f349bcb6085154e66b6692fa5790102b6644a5f4",
+		"xxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxxx"},
+}
+
+func TestNewR8(t *testing.T) {
+	newTestVectors(t, NewR8, vectors256r8)
+}
+
+func TestCompressRounds(t *testing.T) {
+	// The generic rounds match the unrolled ones.
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var h [8]uint32
+		var s [4]uint32
+		var m [16]uint32
+		for j := range h {
+			h[j] = rng.Uint32()
+		}
+		for j := range s {
+			s[j] = rng.Uint32()
+		}
+		for j := range m {
+			m[j] = rng.Uint32()
+		}
+		ctr := rng.Uint64()
+		want, got := h, h
+		compress(&want, &s, ctr, &m)
+		compressRounds(&got, &s, ctr, &m, 14)
+		if got != want {
+			t.Fatalf("%d: expected %x, got %x", i, want, got)
+		}
+	}
+}
+
+func TestMarshalR8(t *testing.T) {
+	v := vectors256r8[4]
+	h := NewR8()
+	h.Write([]byte(v.in[:70]))
+	state, err := h.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := NewR8()
+	if err := r.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err != nil {
+		t.Fatal(err)
+	}
+	r.Write([]byte(v.in[70:]))
+	if res := fmt.Sprintf("%x", r.Sum(nil)); res != v.out {
+		t.Errorf("expected %q, got %q", v.out, res)
+	}
+
+	// The states of BLAKE-256 and BLAKE-256r8 are not interchangeable.
+	if err := New().(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err == nil {
+		t.Errorf("expected error restoring a BLAKE-256r8 state into BLAKE-256")
+	}
+	state, _ = New().(encoding.BinaryMarshaler).MarshalBinary()
+	if err := r.(encoding.BinaryUnmarshaler).UnmarshalBinary(state); err == nil {
+		t.Errorf("expected error restoring a BLAKE-256 state into BLAKE-256r8")
+	}
+}
@@ -0,0 +1,119 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"io"
+	"os"
+)
+
+// readBufferSize is the size of the buffer used to hash from an io.Reader. It
+// is a multiple of BlockSize so full reads are compressed without going
+// through the digest buffer.
+const readBufferSize = 1024 * BlockSize
+
+// SumReader256 returns the BLAKE-256 checksum of the data read from r until
+// EOF.
+func SumReader256(r io.Reader) ([Size]byte, error) {
+	d := digest{hashSize: 256, h: iv256}
+	if err := d.readAll(r); err != nil {
+		return [Size]byte{}, err
+	}
+	return d.checkSum(), nil
+}
+
+// SumReader224 returns the BLAKE-224 checksum of the data read from r until
+// EOF.
+func SumReader224(r io.Reader) (sum224 [Size224]byte, err error) {
+	d := digest{hashSize: 224, h: iv224}
+	if err := d.readAll(r); err != nil {
+		return sum224, err
+	}
+	sum := d.checkSum()
+	copy(sum224[:], sum[:Size224])
+	return sum224, nil
+}
+
+// SumFile256 returns the BLAKE-256 checksum of the named file. Regular files
+// of 1 MiB or more are memory-mapped on Linux. If such a file is truncated
+// while it is being hashed, an error wrapping io.ErrUnexpectedEOF is
+// returned.
+func SumFile256(path string) ([Size]byte, error) {
+	d := digest{hashSize: 256, h: iv256}
+	if err := d.readFile(path); err != nil {
+		return [Size]byte{}, err
+	}
+	return d.checkSum(), nil
+}
+
+// SumFile224 returns the BLAKE-224 checksum of the named file. Regular files
+// of 1 MiB or more are memory-mapped on Linux. If such a file is truncated
+// while it is being hashed, an error wrapping io.ErrUnexpectedEOF is
+// returned.
+func SumFile224(path string) (sum224 [Size224]byte, err error) {
+	d := digest{hashSize: 224, h: iv224}
+	if err := d.readFile(path); err != nil {
+		return sum224, err
+	}
+	sum := d.checkSum()
+	copy(sum224[:], sum[:Size224])
+	return sum224, nil
+}
+
+// ReadFrom writes the data read from r until EOF to the digest. It reads
+// straight into a block-aligned buffer and compresses full blocks from there,
+// so io.Copy to the digest avoids the digest buffer for all but the tail.
+func (d *digest) ReadFrom(r io.Reader) (n int64, err error) {
+	bufp := readBufferPool.Get().(*[]byte)
+	defer readBufferPool.Put(bufp)
+	buf := *bufp
+
+	// Carry over buffered data so blocks stay aligned.
+	fill := copy(buf, d.x[:d.nx])
+	d.nx = 0
+	for {
+		m, rerr := r.Read(buf[fill:])
+		n += int64(m)
+		fill += m
+		if full := fill &^ (BlockSize - 1); full > 0 {
+			block(d, buf[:full])
+			fill = copy(buf, buf[full:fill])
+		}
+		if rerr != nil {
+			if rerr != io.EOF {
+				err = rerr
+			}
+			break
+		}
+	}
+	d.nx = copy(d.x[:], buf[:fill])
+	return n, err
+}
+
+// readAll writes the data read from r until EOF to the digest.
+func (d *digest) readAll(r io.Reader) error {
+	// Let readers that hold their data in memory hand it over without an
+	// intermediate copy.
+	if wt, ok := r.(io.WriterTo); ok {
+		_, err := wt.WriteTo(d)
+		return err
+	}
+	_, err := d.ReadFrom(r)
+	return err
+}
+
+// readFile writes the contents of the named file to the digest.
+func (d *digest) readFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if ok, err := d.mapFile(f); ok {
+		return err
+	}
+	return d.readAll(f)
+}
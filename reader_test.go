@@ -0,0 +1,165 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/iotest"
+)
+
+func TestSumReader(t *testing.T) {
+	data := make([]byte, 3*readBufferSize+17)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	for _, n := range []int{0, 1, 55, 64, 1000, readBufferSize, len(data)} {
+		readers := map[string]io.Reader{
+			"full":    bytes.NewReader(data[:n]),
+			"half":    iotest.HalfReader(bytes.NewReader(data[:n])),
+			"onebyte": iotest.OneByteReader(bytes.NewReader(data[:n])),
+		}
+		for name, r := range readers {
+			sum, err := SumReader256(r)
+			if err != nil {
+				t.Fatalf("%d/%s: unexpected error: %v", n, name, err)
+			}
+			if want := Sum256(data[:n]); sum != want {
+				t.Errorf("%d/%s: expected %x, got %x", n, name, want, sum)
+			}
+		}
+
+		sum224, err := SumReader224(iotest.HalfReader(bytes.NewReader(data[:n])))
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", n, err)
+		}
+		if want := Sum224(data[:n]); sum224 != want {
+			t.Errorf("%d: expected %x, got %x", n, want, sum224)
+		}
+	}
+
+	// Errors other than EOF are returned.
+	_, err := SumReader256(iotest.TimeoutReader(bytes.NewReader(data)))
+	if err != iotest.ErrTimeout {
+		t.Errorf("expected %v, got %v", iotest.ErrTimeout, err)
+	}
+}
+
+func TestReadFrom(t *testing.T) {
+	data := make([]byte, 2*readBufferSize+100)
+	for i := range data {
+		data[i] = byte(i * 5)
+	}
+	want := Sum256(data)
+	for _, split := range []int{0, 1, 63, 64, 65} {
+		h := New()
+		h.Write(data[:split])
+		r := iotest.HalfReader(bytes.NewReader(data[split:]))
+		n, err := h.(io.ReaderFrom).ReadFrom(r)
+		if err != nil || n != int64(len(data)-split) {
+			t.Fatalf("%d: expected %d bytes read, got %d (err %v)", split,
+				len(data)-split, n, err)
+		}
+		if sum := h.Sum(nil); !bytes.Equal(sum, want[:]) {
+			t.Errorf("%d: expected %x, got %x", split, want, sum)
+		}
+	}
+}
+
+func TestSumFile(t *testing.T) {
+	dir := t.TempDir()
+	// The larger file is memory-mapped where supported.
+	for _, n := range []int{0, 100, 2<<20 + 3} {
+		data := make([]byte, n)
+		for i := range data {
+			data[i] = byte(i * 3)
+		}
+		path := filepath.Join(dir, "data")
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		sum, err := SumFile256(path)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", n, err)
+		}
+		if want := Sum256(data); sum != want {
+			t.Errorf("%d: expected %x, got %x", n, want, sum)
+		}
+		sum224, err := SumFile224(path)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", n, err)
+		}
+		if want := Sum224(data); sum224 != want {
+			t.Errorf("%d: expected %x, got %x", n, want, sum224)
+		}
+	}
+
+	if _, err := SumFile256(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("expected not exist error, got %v", err)
+	}
+}
+
+var benchFileSize = 64 << 20
+
+func benchFile(b *testing.B) string {
+	path := filepath.Join(b.TempDir(), "data")
+	if err := os.WriteFile(path, make([]byte, benchFileSize), 0o600); err != nil {
+		b.Fatal(err)
+	}
+	return path
+}
+
+// benchReader returns a reader over 1MiB of data that hides any io.WriterTo
+// implementation, like a network connection or a pipe.
+func benchReader() io.Reader {
+	return struct{ io.Reader }{bytes.NewReader(benchReaderData)}
+}
+
+var benchReaderData = make([]byte, 1<<20)
+
+func BenchmarkReaderCopy(b *testing.B) {
+	b.SetBytes(int64(len(benchReaderData)))
+	for i := 0; i < b.N; i++ {
+		h := New()
+		io.Copy(h, benchReader())
+		_ = h.Sum(bufOut[0:0])
+	}
+}
+
+func BenchmarkReaderSum(b *testing.B) {
+	b.SetBytes(int64(len(benchReaderData)))
+	for i := 0; i < b.N; i++ {
+		_, _ = SumReader256(benchReader())
+	}
+}
+
+func BenchmarkFileCopy(b *testing.B) {
+	path := benchFile(b)
+	b.SetBytes(int64(benchFileSize))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		f, err := os.Open(path)
+		if err != nil {
+			b.Fatal(err)
+		}
+		h := New()
+		io.Copy(h, f)
+		_ = h.Sum(bufOut[0:0])
+		f.Close()
+	}
+}
+
+func BenchmarkFileSum(b *testing.B) {
+	path := benchFile(b)
+	b.SetBytes(int64(benchFileSize))
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _ = SumFile256(path)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Algorithm describes a hash algorithm of the BLAKE family.
+type Algorithm struct {
+	// Name is the canonical name of the algorithm, such as "blake256".
+	Name string
+
+	// Size is the size of the checksum in bytes.
+	Size int
+
+	// BlockSize is the block size of the algorithm in bytes.
+	BlockSize int
+
+	// New returns a new hash.Hash computing the checksum.
+	New func() hash.Hash
+
+	// NewSalt returns a new hash.Hash computing the checksum with the given
+	// salt. It is nil if the algorithm does not support salt.
+	NewSalt func(salt []byte) hash.Hash
+}
+
+// SupportsSalt reports whether the algorithm takes a salt.
+func (a Algorithm) SupportsSalt() bool {
+	return a.NewSalt != nil
+}
+
+// ErrUnknownAlgorithm is returned by Lookup for names that are not registered.
+var ErrUnknownAlgorithm = errors.New("blake256: unknown algorithm")
+
+// saltSuffix names the salted form of an algorithm that supports salt, as in
+// "blake256-salt".
+const saltSuffix = "-salt"
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]Algorithm)
+)
+
+func init() {
+	for _, a := range []Algorithm{
+		{Name: "blake256", Size: Size, BlockSize: BlockSize, New: New,
+			NewSalt: NewSalt},
+		{Name: "blake224", Size: Size224, BlockSize: BlockSize, New: New224,
+			NewSalt: New224Salt},
+		{Name: "blake256r8", Size: Size, BlockSize: BlockSize, New: NewR8},
+	} {
+		if err := Register(a); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// canonicalName lowers the case of name and drops a dash right after a
+// leading "blake", so "BLAKE-256" and "blake256" refer to the same algorithm.
+// Other dashes are kept, as in "blake256-salt".
+func canonicalName(name string) string {
+	name = strings.ToLower(name)
+	if strings.HasPrefix(name, "blake-") {
+		name = "blake" + name[len("blake-"):]
+	}
+	return name
+}
+
+// Register adds a custom algorithm, such as a reduced-round variant, under
+// a.Name. The name is matched case-insensitively, stored in lower case and
+// must not already be registered.
+func Register(a Algorithm) error {
+	if a.Name == "" || a.New == nil || a.Size <= 0 || a.BlockSize <= 0 {
+		return fmt.Errorf("blake256: incomplete algorithm %q", a.Name)
+	}
+	name := canonicalName(a.Name)
+	a.Name = name
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if _, ok := registry[name]; ok {
+		return fmt.Errorf("blake256: algorithm %q already registered", a.Name)
+	}
+	registry[name] = a
+	return nil
+}
+
+// Lookup returns the algorithm registered under name, such as "blake256",
+// "blake224" or "blake256r8". The name is matched case-insensitively and a
+// dash may follow "blake", so "BLAKE-256" is accepted for "blake256". A
+// "-salt" suffix, as in "blake256-salt", names an algorithm that supports
+// salt and returns the same Algorithm as its name without the suffix.
+func Lookup(name string) (Algorithm, error) {
+	canon := canonicalName(name)
+	registryMu.RLock()
+	a, ok := registry[canon]
+	if !ok && strings.HasSuffix(canon, saltSuffix) {
+		a, ok = registry[strings.TrimSuffix(canon, saltSuffix)]
+		ok = ok && a.SupportsSalt()
+	}
+	registryMu.RUnlock()
+	if !ok {
+		return Algorithm{}, fmt.Errorf("%w: %q", ErrUnknownAlgorithm, name)
+	}
+	return a, nil
+}
+
+// Algorithms returns the canonical names of all registered algorithms in
+// sorted order.
+func Algorithms() []string {
+	registryMu.RLock()
+	names := make([]string, 0, len(registry))
+	for _, a := range registry {
+		names = append(names, a.Name)
+	}
+	registryMu.RUnlock()
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		name   string
+		canon  string
+		size   int
+		salt   bool
+		vector blakeVector
+	}{
+		{"blake256", "blake256", Size, true, vectors256[0]},
+		{"BLAKE256", "blake256", Size, true, vectors256[0]},
+		{"blake-256", "blake256", Size, true, vectors256[0]},
+		{"BLAKE-256", "blake256", Size, true, vectors256[0]},
+		{"blake224", "blake224", Size224, true, vectors224[0]},
+		{"Blake224", "blake224", Size224, true, vectors224[0]},
+		{"blake-224", "blake224", Size224, true, vectors224[0]},
+		{"BLAKE-224", "blake224", Size224, true, vectors224[0]},
+		{"blake256r8", "blake256r8", Size, false, vectors256r8[0]},
+		{"BLAKE256R8", "blake256r8", Size, false, vectors256r8[0]},
+		{"blake-256r8", "blake256r8", Size, false, vectors256r8[0]},
+		{"BLAKE-256r8", "blake256r8", Size, false, vectors256r8[0]},
+		{"blake256-salt", "blake256", Size, true, vectors256[0]},
+		{"BLAKE256-SALT", "blake256", Size, true, vectors256[0]},
+		{"blake-256-salt", "blake256", Size, true, vectors256[0]},
+		{"BLAKE-256-Salt", "blake256", Size, true, vectors256[0]},
+		{"blake224-salt", "blake224", Size224, true, vectors224[0]},
+		{"BLAKE224-SALT", "blake224", Size224, true, vectors224[0]},
+		{"blake-224-salt", "blake224", Size224, true, vectors224[0]},
+		{"Blake-224-Salt", "blake224", Size224, true, vectors224[0]},
+	}
+	for _, test := range tests {
+		a, err := Lookup(test.name)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", test.name, err)
+		}
+		if a.Name != test.canon || a.Size != test.size || a.BlockSize != BlockSize ||
+			a.SupportsSalt() != test.salt {
+			t.Errorf("%s: unexpected algorithm %+v", test.name, a)
+		}
+		h := a.New()
+		h.Write([]byte(test.vector.in))
+		if res := fmt.Sprintf("%x", h.Sum(nil)); res != test.vector.out {
+			t.Errorf("%s: expected %q, got %q", test.name, test.vector.out, res)
+		}
+	}
+
+	// Dashes are only dropped right after "blake", and "-salt" only names
+	// algorithms that support salt.
+	for _, name := range []string{"blake512", "blake_256", "blake--256",
+		"blake2-56", "blake256salt", "blake256-r8", " blake256", "256",
+		"blake256r8-salt", "blake256-salt-salt", "-salt"} {
+		if _, err := Lookup(name); !errors.Is(err, ErrUnknownAlgorithm) {
+			t.Errorf("%q: expected %v, got %v", name, ErrUnknownAlgorithm, err)
+		}
+	}
+
+	for _, name := range []string{"blake256", "blake256-salt"} {
+		a, _ := Lookup(name)
+		v := vectors256salt[1]
+		h := a.NewSalt([]byte(v.salt))
+		h.Write([]byte(v.in))
+		if res := fmt.Sprintf("%x", h.Sum(nil)); res != v.out {
+			t.Errorf("%s: expected %q, got %q", name, v.out, res)
+		}
+	}
+
+	// The salted forms are not listed separately.
+	if got, want := fmt.Sprint(Algorithms()[:3]), "[blake224 blake256 blake256r8]"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+// registerRuns makes the names registered by TestRegister unique when the test
+// is run several times.
+var registerRuns int
+
+func TestRegister(t *testing.T) {
+	registerRuns++
+	name := fmt.Sprintf("test-blake256-custom%d", registerRuns)
+	custom := Algorithm{
+		Name:      strings.ToUpper(name),
+		Size:      Size,
+		BlockSize: BlockSize,
+		New:       func() hash.Hash { return NewSalt([]byte("custom-variant!!")) },
+	}
+	if err := Register(custom); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	a, err := Lookup(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a.Name != name || a.SupportsSalt() {
+		t.Errorf("unexpected algorithm %+v", a)
+	}
+
+	found := false
+	for _, name := range Algorithms() {
+		found = found || name == a.Name
+	}
+	if !found {
+		t.Errorf("%q missing from %v", a.Name, Algorithms())
+	}
+
+	if err := Register(custom); err == nil {
+		t.Errorf("expected error registering a duplicate name")
+	}
+	if err := Register(Algorithm{Name: "blake256"}); err == nil {
+		t.Errorf("expected error registering an incomplete algorithm")
+	}
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"crypto/hmac"
+	"hash"
+	"math/big"
+)
+
+// NonceRFC6979 returns the deterministic nonce of RFC 6979, section 3.2, for
+// the private key privKey and the message hash msgHash over a group of order
+// q. HMAC is computed with the hash returned by newHash, or BLAKE-256 if nil.
+// The private key should lie in [1, q-1]; like dcrd, a longer key is
+// truncated to its leftmost bytes rather than causing a panic.
+//
+// The optional extra data is appended to the HMAC input after the private key
+// and the hash as described in section 3.6 of the RFC. The extraIterations
+// parameter skips that many valid candidates, which lets signing code draw a
+// new nonce in the unlikely event the first one yields an invalid signature.
+// It should start at zero.
+//
+// The secp256k1 package of dcrd computes its nonces with HMAC-SHA256 and a
+// 32-byte extra data optionally followed by a 16-byte version, so passing
+// sha256.New, the curve order and extra || version reproduces its nonces.
+func NonceRFC6979(q *big.Int, newHash func() hash.Hash, privKey, msgHash, extra []byte, extraIterations uint32) *big.Int {
+	if newHash == nil {
+		newHash = New
+	}
+	qlen := q.BitLen()
+	rlen := (qlen + 7) / 8
+
+	// bits2int converts the leftmost qlen bits of b to an integer.
+	bits2int := func(b []byte) *big.Int {
+		v := new(big.Int).SetBytes(b)
+		if blen := len(b) * 8; blen > qlen {
+			v.Rsh(v, uint(blen-qlen))
+		}
+		return v
+	}
+	// int2octets encodes v as rlen big-endian bytes.
+	int2octets := func(v *big.Int) []byte {
+		out := make([]byte, rlen)
+		return v.FillBytes(out)
+	}
+
+	// Step D and F input: int2octets(x) || bits2octets(h1) || extra.
+	if len(privKey) > rlen {
+		privKey = privKey[:rlen]
+	}
+	x := new(big.Int).SetBytes(privKey)
+	h1 := bits2int(msgHash)
+	h1.Mod(h1, q)
+	key := append(append(int2octets(x), int2octets(h1)...), extra...)
+
+	mac := func(k []byte, data ...[]byte) []byte {
+		m := hmac.New(newHash, k)
+		for _, d := range data {
+			m.Write(d)
+		}
+		return m.Sum(nil)
+	}
+
+	// Steps B and C.
+	hlen := newHash().Size()
+	v := make([]byte, hlen)
+	for i := range v {
+		v[i] = 0x01
+	}
+	k := make([]byte, hlen)
+
+	// Steps D to G.
+	k = mac(k, v, []byte{0x00}, key)
+	v = mac(k, v)
+	k = mac(k, v, []byte{0x01}, key)
+	v = mac(k, v)
+
+	// Step H.
+	var generated uint32
+	for {
+		var t []byte
+		for len(t) < rlen {
+			v = mac(k, v)
+			t = append(t, v...)
+		}
+		nonce := bits2int(t)
+		if nonce.Sign() > 0 && nonce.Cmp(q) < 0 {
+			generated++
+			if generated > extraIterations {
+				return nonce
+			}
+		}
+		k = mac(k, v, []byte{0x00})
+		v = mac(k, v)
+	}
+}
@@ -0,0 +1,139 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"crypto/elliptic"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"testing"
+)
+
+// secp256k1N is the order of the secp256k1 group.
+var secp256k1N, _ = new(big.Int).SetString("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141", 16)
+
+func TestNonceRFC6979(t *testing.T) {
+	// Vectors generated with an independent Python implementation of
+	// BLAKE-256 over secp256k1.
+	tests := []struct {
+		key, hash, extra string
+		iterations       uint32
+		nonce            string
+	}{
+		{"0000000000000000000000000000000000000000000000000000000000000001", "4e5167e4ae82869c31f1979186cea1ffd9e20bb031215f4733aa5e7227f8deb4", "", 0,
+			"8dce43359bc26f0724b222ffdba36f30205dd2e8c63ede4dc72c47b339890969"},
+		{"cca9fbcc1b41e5a95d369eaa6ddcff73b61a4efaa279cfc6567e8daa39cbaf50", "c3ef6ca0e55eaf8fef581455ba3c270167ca18c00512cba46fe21e087ebd2478", "", 0,
+			"a5ea2091de845856f0a21484ad1a09cb1b3b99cfe3be3efe71fa4984de90ac24"},
+		{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140", "c3ef6ca0e55eaf8fef581455ba3c270167ca18c00512cba46fe21e087ebd2478", "0000000000000000000000000000000000000000000000000000000000000002", 0,
+			"2dda93f4b7a3bd79c75201165cc686a6215e887da1e34e78ae34987b9858b1b9"},
+		{"0011111111111111111111111111111111111111111111111111111111111111", "0632e6e644ec3fac7f586da38476684b59a3e800aa64a7714e66e7e2848408c3", "", 2,
+			"06092bd3f6113a630c447b5acaaf91c9458a599ea6e6653306de0ac313b46f60"},
+	}
+	for i, test := range tests {
+		nonce := NonceRFC6979(secp256k1N, nil, hexDecode(test.key),
+			hexDecode(test.hash), hexDecode(test.extra), test.iterations)
+		if res := fmt.Sprintf("%064x", nonce); res != test.nonce {
+			t.Errorf("%d: expected %s, got %s", i, test.nonce, res)
+		}
+	}
+}
+
+func TestNonceRFC6979SHA256(t *testing.T) {
+	// Vectors from the secp256k1 package of dcrd, which uses HMAC-SHA256.
+	tests := []struct {
+		name           string
+		key, hash      string
+		extra, version string
+		iterations     uint32
+		nonce          string
+	}{{
+		name:  "key 32 bytes, hash 32 bytes, no extra data, no version",
+		key:   "0011111111111111111111111111111111111111111111111111111111111111",
+		hash:  "0000000000000000000000000000000000000000000000000000000000000001",
+		nonce: "154e92760f77ad9af6b547edd6f14ad0fae023eb2221bc8be2911675d8a686a3",
+	}, {
+		name:  "key <32 bytes, hash 32 bytes, no extra data, no version",
+		key:   "11111111111111111111111111111111111111111111111111111111111111",
+		hash:  "0000000000000000000000000000000000000000000000000000000000000001",
+		nonce: "154e92760f77ad9af6b547edd6f14ad0fae023eb2221bc8be2911675d8a686a3",
+	}, {
+		name:  "key >32 bytes, hash 32 bytes, no extra data, no version",
+		key:   "001111111111111111111111111111111111111111111111111111111111111111",
+		hash:  "0000000000000000000000000000000000000000000000000000000000000001",
+		nonce: "154e92760f77ad9af6b547edd6f14ad0fae023eb2221bc8be2911675d8a686a3",
+	}, {
+		name:  "hash <32 bytes (padded), no extra data, no version",
+		key:   "0011111111111111111111111111111111111111111111111111111111111111",
+		hash:  "00000000000000000000000000000000000000000000000000000000000001",
+		nonce: "154e92760f77ad9af6b547edd6f14ad0fae023eb2221bc8be2911675d8a686a3",
+	}, {
+		name:  "hash >32 bytes (truncated), no extra data, no version",
+		key:   "0011111111111111111111111111111111111111111111111111111111111111",
+		hash:  "000000000000000000000000000000000000000000000000000000000000000100",
+		nonce: "154e92760f77ad9af6b547edd6f14ad0fae023eb2221bc8be2911675d8a686a3",
+	}, {
+		name:  "hash 32 bytes, extra data 32 bytes, no version",
+		key:   "0011111111111111111111111111111111111111111111111111111111111111",
+		hash:  "0000000000000000000000000000000000000000000000000000000000000001",
+		extra: "0000000000000000000000000000000000000000000000000000000000000002",
+		nonce: "67893461ade51cde61824b20bc293b585d058e6b9f40fb68453d5143f15116ae",
+	}, {
+		name:    "hash 32 bytes, extra data 32 bytes all zero, version 16 bytes",
+		key:     "0011111111111111111111111111111111111111111111111111111111111111",
+		hash:    "0000000000000000000000000000000000000000000000000000000000000001",
+		extra:   "0000000000000000000000000000000000000000000000000000000000000000",
+		version: "00000000000000000000000000000003",
+		nonce:   "7b27d6ceff87e1ded1860ca4e271a530e48514b9d3996db0af2bb8bda189007d",
+	}, {
+		name:    "hash 32 bytes, extra data 32 bytes, version 16 bytes",
+		key:     "0011111111111111111111111111111111111111111111111111111111111111",
+		hash:    "0000000000000000000000000000000000000000000000000000000000000001",
+		extra:   "0000000000000000000000000000000000000000000000000000000000000002",
+		version: "00000000000000000000000000000003",
+		nonce:   "9b5657643dfd4b77d99dfa505ed8a17e1b9616354fc890669b4aabece2170686",
+	}, {
+		name:       "hash 32 bytes, no extra data, no version, extra iteration",
+		key:        "0011111111111111111111111111111111111111111111111111111111111111",
+		hash:       "0000000000000000000000000000000000000000000000000000000000000001",
+		iterations: 1,
+		nonce:      "66fca3fe494a6216e4a3f15cfbc1d969c60d9cdefda1a1c193edabd34aa8cd5e",
+	}, {
+		name:       "hash 32 bytes, no extra data, no version, 2 extra iterations",
+		key:        "0011111111111111111111111111111111111111111111111111111111111111",
+		hash:       "0000000000000000000000000000000000000000000000000000000000000001",
+		iterations: 2,
+		nonce:      "70da248c92b5d28a52eafca1848b1a37d4cb36526c02553c9c48bb0b895fc77d",
+	}}
+	for _, test := range tests {
+		extra := append(hexDecode(test.extra), hexDecode(test.version)...)
+		nonce := NonceRFC6979(secp256k1N, sha256.New, hexDecode(test.key),
+			hexDecode(test.hash), extra, test.iterations)
+		if res := fmt.Sprintf("%064x", nonce); res != test.nonce {
+			t.Errorf("%s: expected %s, got %s", test.name, test.nonce, res)
+		}
+	}
+
+	// RFC 6979, appendix A.2.5, P-256 with SHA-256 and the message "sample".
+	x := hexDecode("c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721")
+	h := sha256.Sum256([]byte("sample"))
+	nonce := NonceRFC6979(elliptic.P256().Params().N, sha256.New, x, h[:], nil, 0)
+	want := "a6e3c57dd01abe90086538398355dd4c3b17aa873382b0f24d6129493d8aad60"
+	if res := fmt.Sprintf("%064x", nonce); res != want {
+		t.Errorf("P-256: expected %s, got %s", want, res)
+	}
+}
+
+func TestNonceRFC6979LargeKey(t *testing.T) {
+	// A key wider than the group order used to overflow int2octets. It is
+	// truncated to the leftmost 32 bytes like dcrd does.
+	key := hexDecode("01001111111111111111111111111111111111111111111111111111111111111111")
+	hash := hexDecode("0000000000000000000000000000000000000000000000000000000000000001")
+	nonce := NonceRFC6979(secp256k1N, sha256.New, key, hash, nil, 0)
+	want := NonceRFC6979(secp256k1N, sha256.New, key[:32], hash, nil, 0)
+	if nonce.Cmp(want) != 0 {
+		t.Errorf("expected %064x, got %064x", want, nonce)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package secp256k1 implements the secp256k1 elliptic curve arithmetic and
+// keys needed by the Decred signature schemes built on BLAKE-256.
+//
+// ScalarMult and ScalarBaseMult, which multiply points by private keys and
+// nonces, run in constant time on fixed-size field elements once the scalar
+// is converted from math/big. The remaining operations, such as Add and
+// DecompressY, are implemented with math/big and are not constant time; the
+// signature packages only use them on public values. Their arithmetic modulo
+// N on private keys and nonces also uses math/big, as documented by their
+// Sign functions.
+package secp256k1
+
+import "math/big"
+
+var (
+	// fieldPrime is the prime of the underlying field.
+	fieldPrime = fromHex("fffffffffffffffffffffffffffffffffffffffffffffffffffffffefffffc2f")
+
+	// order is the order of the group generated by G.
+	order = fromHex("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+
+	// gx and gy are the coordinates of the base point G.
+	gx = fromHex("79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	gy = fromHex("483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8")
+
+	// curveB is the constant of the curve equation y^2 = x^3 + 7.
+	curveB = big.NewInt(7)
+
+	// sqrtExp is (P+1)/4, used to compute square roots since P = 3 mod 4.
+	sqrtExp = new(big.Int).Rsh(new(big.Int).Add(fieldPrime, big.NewInt(1)), 2)
+)
+
+func fromHex(s string) *big.Int {
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		panic("invalid hex in source file: " + s)
+	}
+	return v
+}
+
+// FieldPrime returns the prime P of the field the curve is defined over.
+func FieldPrime() *big.Int { return new(big.Int).Set(fieldPrime) }
+
+// Order returns the order N of the group generated by the base point.
+func Order() *big.Int { return new(big.Int).Set(order) }
+
+// Generator returns the coordinates of the base point G.
+func Generator() (x, y *big.Int) {
+	return new(big.Int).Set(gx), new(big.Int).Set(gy)
+}
+
+// IsOnCurve reports whether (x, y) is a point on the curve with coordinates in
+// the range [0, P).
+func IsOnCurve(x, y *big.Int) bool {
+	if x.Sign() < 0 || x.Cmp(fieldPrime) >= 0 || y.Sign() < 0 ||
+		y.Cmp(fieldPrime) >= 0 {
+		return false
+	}
+	y2 := new(big.Int).Mul(y, y)
+	y2.Mod(y2, fieldPrime)
+	return y2.Cmp(curveRHS(x)) == 0
+}
+
+// curveRHS returns x^3 + 7 mod P.
+func curveRHS(x *big.Int) *big.Int {
+	x3 := new(big.Int).Mul(x, x)
+	x3.Mul(x3, x)
+	x3.Add(x3, curveB)
+	return x3.Mod(x3, fieldPrime)
+}
+
+// DecompressY returns the y coordinate of the point with the given x
+// coordinate whose oddness matches odd. It reports false if there is no such
+// point.
+func DecompressY(x *big.Int, odd bool) (*big.Int, bool) {
+	if x.Sign() < 0 || x.Cmp(fieldPrime) >= 0 {
+		return nil, false
+	}
+	rhs := curveRHS(x)
+	y := new(big.Int).Exp(rhs, sqrtExp, fieldPrime)
+	if check := new(big.Int).Mul(y, y); check.Mod(check, fieldPrime).Cmp(rhs) != 0 {
+		return nil, false
+	}
+	if (y.Bit(0) == 1) != odd {
+		y.Sub(fieldPrime, y)
+	}
+	return y, true
+}
+
+// jacobianPoint is a point in Jacobian coordinates, where the affine point is
+// (X/Z^2, Y/Z^3). The point at infinity has Z = 0.
+type jacobianPoint struct {
+	x, y, z *big.Int
+}
+
+func newJacobian(x, y *big.Int) *jacobianPoint {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return &jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	return &jacobianPoint{new(big.Int).Set(x), new(big.Int).Set(y), big.NewInt(1)}
+}
+
+// affine converts the point to affine coordinates. The point at infinity is
+// returned as (0, 0).
+func (p *jacobianPoint) affine() (x, y *big.Int) {
+	if p.z.Sign() == 0 {
+		return new(big.Int), new(big.Int)
+	}
+	zInv := new(big.Int).ModInverse(p.z, fieldPrime)
+	zInv2 := new(big.Int).Mul(zInv, zInv)
+	x = new(big.Int).Mul(p.x, zInv2)
+	x.Mod(x, fieldPrime)
+	zInv2.Mul(zInv2, zInv)
+	y = new(big.Int).Mul(p.y, zInv2)
+	y.Mod(y, fieldPrime)
+	return x, y
+}
+
+// double returns 2p using the dbl-2009-l formulas for a = 0.
+func (p *jacobianPoint) double() *jacobianPoint {
+	if p.z.Sign() == 0 || p.y.Sign() == 0 {
+		return &jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	mod := func(v *big.Int) *big.Int { return v.Mod(v, fieldPrime) }
+	a := mod(new(big.Int).Mul(p.x, p.x))
+	b := mod(new(big.Int).Mul(p.y, p.y))
+	c := mod(new(big.Int).Mul(b, b))
+	d := new(big.Int).Add(p.x, b)
+	d.Mul(d, d)
+	d.Sub(d, a)
+	d.Sub(d, c)
+	d = mod(d.Lsh(d, 1))
+	e := new(big.Int).Mul(a, big.NewInt(3))
+	f := mod(new(big.Int).Mul(e, e))
+
+	x3 := new(big.Int).Sub(f, new(big.Int).Lsh(d, 1))
+	mod(x3)
+	y3 := new(big.Int).Sub(d, x3)
+	y3.Mul(y3, e)
+	y3.Sub(y3, new(big.Int).Lsh(c, 3))
+	mod(y3)
+	z3 := new(big.Int).Mul(p.y, p.z)
+	mod(z3.Lsh(z3, 1))
+	return &jacobianPoint{x3, y3, z3}
+}
+
+// add returns p + q using the add-2007-bl formulas.
+func (p *jacobianPoint) add(q *jacobianPoint) *jacobianPoint {
+	if p.z.Sign() == 0 {
+		return q
+	}
+	if q.z.Sign() == 0 {
+		return p
+	}
+	mod := func(v *big.Int) *big.Int { return v.Mod(v, fieldPrime) }
+	z1z1 := mod(new(big.Int).Mul(p.z, p.z))
+	z2z2 := mod(new(big.Int).Mul(q.z, q.z))
+	u1 := mod(new(big.Int).Mul(p.x, z2z2))
+	u2 := mod(new(big.Int).Mul(q.x, z1z1))
+	s1 := new(big.Int).Mul(p.y, q.z)
+	s1 = mod(s1.Mul(s1, z2z2))
+	s2 := new(big.Int).Mul(q.y, p.z)
+	s2 = mod(s2.Mul(s2, z1z1))
+	h := mod(new(big.Int).Sub(u2, u1))
+	r := mod(new(big.Int).Sub(s2, s1))
+	if h.Sign() == 0 {
+		if r.Sign() == 0 {
+			return p.double()
+		}
+		return &jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	}
+	r.Lsh(r, 1)
+	i := new(big.Int).Lsh(h, 1)
+	i = mod(i.Mul(i, i))
+	j := mod(new(big.Int).Mul(h, i))
+	v := mod(new(big.Int).Mul(u1, i))
+
+	x3 := new(big.Int).Mul(r, r)
+	x3.Sub(x3, j)
+	x3.Sub(x3, new(big.Int).Lsh(v, 1))
+	mod(x3)
+	y3 := new(big.Int).Sub(v, x3)
+	y3.Mul(y3, r)
+	s1j := new(big.Int).Mul(s1, j)
+	y3.Sub(y3, s1j.Lsh(s1j, 1))
+	mod(y3)
+	z3 := new(big.Int).Add(p.z, q.z)
+	z3.Mul(z3, z3)
+	z3.Sub(z3, z1z1)
+	z3.Sub(z3, z2z2)
+	z3.Mul(z3, h)
+	mod(z3)
+	return &jacobianPoint{x3, y3, z3}
+}
+
+// Add returns the sum of the points (x1, y1) and (x2, y2). The point at
+// infinity is represented as (0, 0).
+func Add(x1, y1, x2, y2 *big.Int) (x, y *big.Int) {
+	return newJacobian(x1, y1).add(newJacobian(x2, y2)).affine()
+}
+
+// ScalarMult returns k*(x, y). The point at infinity is represented as (0, 0).
+// It runs in constant time with respect to k.
+func ScalarMult(x, y, k *big.Int) (rx, ry *big.Int) {
+	return newMultTable(newProjective(x, y)).scalarMult(k).affine()
+}
+
+// ScalarBaseMult returns k*G where G is the base point. It runs in constant
+// time with respect to k.
+func ScalarBaseMult(k *big.Int) (x, y *big.Int) {
+	return baseTable.scalarMult(k).affine()
+}
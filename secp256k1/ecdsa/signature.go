@@ -0,0 +1,263 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package ecdsa implements ECDSA over secp256k1 with RFC 6979 nonces, low-S
+// normalization and the 65-byte compact signatures that allow recovering the
+// public key, as used by Decred message signing.
+package ecdsa
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/rickiey/blake256"
+	"github.com/rickiey/blake256/secp256k1"
+)
+
+const (
+	// CompactSignatureSize is the size of a compact signature: a recovery
+	// code byte followed by r and s as 32-byte big-endian integers.
+	CompactSignatureSize = 65
+
+	// compactSigMagicOffset is added to the recovery code for compatibility
+	// with the Bitcoin format it was inherited from.
+	compactSigMagicOffset = 27
+
+	// compactSigCompPubKey is added to the recovery code when the signer's
+	// public key is serialized compressed.
+	compactSigCompPubKey = 4
+
+	// Bits of the public key recovery code identifying the random point R:
+	// the oddness of its y coordinate and whether its x coordinate was not
+	// less than the group order.
+	recoveryCodeOddBit      = 1 << 0
+	recoveryCodeOverflowBit = 1 << 1
+)
+
+var (
+	// ErrSigInvalidLen is returned when a compact signature is not
+	// CompactSignatureSize bytes.
+	ErrSigInvalidLen = errors.New("ecdsa: invalid compact signature length")
+
+	// ErrSigInvalidRecoveryCode is returned when the recovery code of a
+	// compact signature is out of range.
+	ErrSigInvalidRecoveryCode = errors.New("ecdsa: invalid recovery code")
+
+	// ErrSigRTooBig is returned when r is not less than the group order.
+	ErrSigRTooBig = errors.New("ecdsa: signature r is not less than the group order")
+
+	// ErrSigRIsZero is returned when r is zero.
+	ErrSigRIsZero = errors.New("ecdsa: signature r is zero")
+
+	// ErrSigSTooBig is returned when s is not less than the group order.
+	ErrSigSTooBig = errors.New("ecdsa: signature s is not less than the group order")
+
+	// ErrSigSIsZero is returned when s is zero.
+	ErrSigSIsZero = errors.New("ecdsa: signature s is zero")
+
+	// ErrSigOverflowsPrime is returned when the recovery code claims r
+	// overflowed the group order but r + N is not less than the field prime.
+	ErrSigOverflowsPrime = errors.New("ecdsa: signature r + N is not less than the field prime")
+
+	// ErrPointNotOnCurve is returned when a compact signature does not
+	// identify a point on the curve, or the recovered key is the point at
+	// infinity.
+	ErrPointNotOnCurve = errors.New("ecdsa: signature is not for a valid curve point")
+)
+
+// Signature is an ECDSA signature.
+type Signature struct {
+	r, s *big.Int
+}
+
+// NewSignature returns the signature with the given r and s values.
+func NewSignature(r, s *big.Int) *Signature {
+	return &Signature{r: new(big.Int).Set(r), s: new(big.Int).Set(s)}
+}
+
+// R returns the r value of the signature.
+func (sig *Signature) R() *big.Int { return new(big.Int).Set(sig.r) }
+
+// S returns the s value of the signature.
+func (sig *Signature) S() *big.Int { return new(big.Int).Set(sig.s) }
+
+// IsEqual reports whether the two signatures are identical.
+func (sig *Signature) IsEqual(other *Signature) bool {
+	return sig.r.Cmp(other.r) == 0 && sig.s.Cmp(other.s) == 0
+}
+
+// hashToInt returns the leftmost 256 bits of hash as an integer reduced
+// modulo the group order.
+func hashToInt(hash []byte) *big.Int {
+	if len(hash) > 32 {
+		hash = hash[:32]
+	}
+	e := new(big.Int).SetBytes(hash)
+	return e.Mod(e, secp256k1.Order())
+}
+
+// Verify reports whether sig is a valid signature of hash by pubKey.
+func (sig *Signature) Verify(hash []byte, pubKey *secp256k1.PublicKey) bool {
+	n := secp256k1.Order()
+	if sig.r.Sign() <= 0 || sig.s.Sign() <= 0 ||
+		sig.r.Cmp(n) >= 0 || sig.s.Cmp(n) >= 0 || !pubKey.IsOnCurve() {
+		return false
+	}
+
+	// X = u1G + u2Q with w = s^-1, u1 = ew and u2 = rw.
+	w := new(big.Int).ModInverse(sig.s, n)
+	u1 := new(big.Int).Mul(hashToInt(hash), w)
+	u1.Mod(u1, n)
+	u2 := new(big.Int).Mul(sig.r, w)
+	u2.Mod(u2, n)
+	x1, y1 := secp256k1.ScalarBaseMult(u1)
+	x2, y2 := secp256k1.ScalarMult(pubKey.X, pubKey.Y, u2)
+	x, y := secp256k1.Add(x1, y1, x2, y2)
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return false
+	}
+	return x.Mod(x, n).Cmp(sig.r) == 0
+}
+
+// sign signs hash with the private key d and nonce k and returns the
+// signature with its public key recovery code. It reports false if k yields
+// r = 0 or s = 0, in which case the caller should retry with a new nonce.
+func sign(d, k *big.Int, hash []byte) (*Signature, byte, bool) {
+	n := secp256k1.Order()
+
+	// r = kG.x mod N
+	rx, ry := secp256k1.ScalarBaseMult(k)
+	var code byte
+	if ry.Bit(0) == 1 {
+		code |= recoveryCodeOddBit
+	}
+	if rx.Cmp(n) >= 0 {
+		code |= recoveryCodeOverflowBit
+	}
+	r := new(big.Int).Mod(rx, n)
+	if r.Sign() == 0 {
+		return nil, 0, false
+	}
+
+	// s = k^-1(e + dr) mod N, negated if greater than N/2. The negation
+	// corresponds to the point -kG, so the oddness bit is flipped too.
+	s := new(big.Int).Mul(d, r)
+	s.Add(s, hashToInt(hash))
+	s.Mul(s, new(big.Int).ModInverse(k, n))
+	s.Mod(s, n)
+	if s.Sign() == 0 {
+		return nil, 0, false
+	}
+	if s.Cmp(new(big.Int).Rsh(n, 1)) > 0 {
+		s.Sub(n, s)
+		code ^= recoveryCodeOddBit
+	}
+	return &Signature{r: r, s: s}, code, true
+}
+
+// signRFC6979 signs hash with an RFC 6979 nonce derived with HMAC-SHA256 and
+// returns the signature with its public key recovery code.
+func signRFC6979(privKey *secp256k1.PrivateKey, hash []byte) (*Signature, byte) {
+	n := secp256k1.Order()
+	privKeyBytes := privKey.Serialize()
+	for iteration := uint32(0); ; iteration++ {
+		k := blake256.NonceRFC6979(n, sha256.New, privKeyBytes, hash, nil,
+			iteration)
+		if sig, code, ok := sign(privKey.D, k, hash); ok {
+			return sig, code
+		}
+	}
+}
+
+// Sign returns the deterministic, low-S ECDSA signature of hash by privKey.
+//
+// The multiplication of the nonce by the base point runs in constant time,
+// but the arithmetic modulo N that combines the nonce, the private key and
+// the hash uses math/big and is not constant time, so signing should not be
+// exposed to attackers who can measure its timing precisely.
+func Sign(privKey *secp256k1.PrivateKey, hash []byte) *Signature {
+	sig, _ := signRFC6979(privKey, hash)
+	return sig
+}
+
+// SignCompact returns the compact signature of hash by privKey. The
+// compressed parameter records whether the signer's public key is serialized
+// compressed, which RecoverCompact reports back. Like Sign, only its point
+// multiplication runs in constant time.
+func SignCompact(privKey *secp256k1.PrivateKey, hash []byte, compressed bool) []byte {
+	sig, code := signRFC6979(privKey, hash)
+	b := make([]byte, CompactSignatureSize)
+	b[0] = compactSigMagicOffset + code
+	if compressed {
+		b[0] += compactSigCompPubKey
+	}
+	sig.r.FillBytes(b[1:33])
+	sig.s.FillBytes(b[33:])
+	return b
+}
+
+// RecoverCompact returns the public key that produced the compact signature
+// of hash, and whether that key was serialized compressed. Since the
+// recovered key is the only one for which the signature is valid, a
+// successful recovery also verifies the signature.
+func RecoverCompact(signature, hash []byte) (*secp256k1.PublicKey, bool, error) {
+	if len(signature) != CompactSignatureSize {
+		return nil, false, fmt.Errorf("%w: got %d bytes, want %d",
+			ErrSigInvalidLen, len(signature), CompactSignatureSize)
+	}
+	const (
+		minCode = compactSigMagicOffset
+		maxCode = compactSigMagicOffset + compactSigCompPubKey + 3
+	)
+	code := signature[0]
+	if code < minCode || code > maxCode {
+		return nil, false, fmt.Errorf("%w: %d is not in [%d, %d]",
+			ErrSigInvalidRecoveryCode, code, minCode, maxCode)
+	}
+	code -= compactSigMagicOffset
+	compressed := code&compactSigCompPubKey != 0
+
+	n := secp256k1.Order()
+	r := new(big.Int).SetBytes(signature[1:33])
+	s := new(big.Int).SetBytes(signature[33:])
+	switch {
+	case r.Cmp(n) >= 0:
+		return nil, false, ErrSigRTooBig
+	case r.Sign() == 0:
+		return nil, false, ErrSigRIsZero
+	case s.Cmp(n) >= 0:
+		return nil, false, ErrSigSTooBig
+	case s.Sign() == 0:
+		return nil, false, ErrSigSIsZero
+	}
+
+	// Reconstruct the random point X from r and the recovery code.
+	x := new(big.Int).Set(r)
+	if code&recoveryCodeOverflowBit != 0 {
+		x.Add(x, n)
+		if x.Cmp(secp256k1.FieldPrime()) >= 0 {
+			return nil, false, ErrSigOverflowsPrime
+		}
+	}
+	y, ok := secp256k1.DecompressY(x, code&recoveryCodeOddBit != 0)
+	if !ok {
+		return nil, false, ErrPointNotOnCurve
+	}
+
+	// Q = r^-1(sX - eG) = u1G + u2X with u1 = -ew and u2 = sw.
+	w := new(big.Int).ModInverse(r, n)
+	u1 := new(big.Int).Mul(hashToInt(hash), w)
+	u1.Neg(u1).Mod(u1, n)
+	u2 := new(big.Int).Mul(s, w)
+	u2.Mod(u2, n)
+	x1, y1 := secp256k1.ScalarBaseMult(u1)
+	x2, y2 := secp256k1.ScalarMult(x, y, u2)
+	qx, qy := secp256k1.Add(x1, y1, x2, y2)
+	if qx.Sign() == 0 && qy.Sign() == 0 {
+		return nil, false, ErrPointNotOnCurve
+	}
+	return &secp256k1.PublicKey{X: qx, Y: qy}, compressed, nil
+}
@@ -0,0 +1,326 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"crypto/subtle"
+	"math/big"
+	"math/bits"
+)
+
+// fieldVal is an element of the field modulo P as four 64-bit limbs, least
+// significant first. Values are kept fully reduced to [0, P). The operations
+// on field elements and on projective points below have no branches or
+// memory accesses that depend on their values, so multiplying by a secret
+// scalar does not leak it through timing.
+type fieldVal [4]uint64
+
+// fieldC is 2^256 - P, so 2^256 = fieldC (mod P).
+const fieldC = 0x1000003d1
+
+var (
+	// fieldP is the field prime P.
+	fieldP = fieldVal{0xfffffffefffffc2f, 0xffffffffffffffff,
+		0xffffffffffffffff, 0xffffffffffffffff}
+
+	// fieldB3 is 3*7, three times the curve constant.
+	fieldB3 = fieldVal{21}
+
+	// fieldPMinus2 is the exponent of a field inverse, P-2, as big-endian
+	// bytes.
+	fieldPMinus2 = new(big.Int).Sub(fieldPrime, big.NewInt(2)).Bytes()
+)
+
+// setBig sets f to v mod P.
+func (f *fieldVal) setBig(v *big.Int) *fieldVal {
+	if v.Sign() < 0 || v.Cmp(fieldPrime) >= 0 {
+		v = new(big.Int).Mod(v, fieldPrime)
+	}
+	var b [32]byte
+	v.FillBytes(b[:])
+	for i := range f {
+		for _, c := range b[32-8*(i+1) : 32-8*i] {
+			f[i] = f[i]<<8 | uint64(c)
+		}
+	}
+	return f
+}
+
+// big returns f as a big integer.
+func (f *fieldVal) big() *big.Int {
+	var b [32]byte
+	for i, v := range f {
+		for j := 0; j < 8; j++ {
+			b[31-8*i-j] = byte(v >> (8 * j))
+		}
+	}
+	return new(big.Int).SetBytes(b[:])
+}
+
+// isZero reports whether f is zero. It is not constant time.
+func (f *fieldVal) isZero() bool {
+	return f[0]|f[1]|f[2]|f[3] == 0
+}
+
+// reduce subtracts P from the 257-bit value carry*2^256 + v when it is at
+// least P, which is the case exactly when carry is set or v - P does not
+// borrow.
+func (f *fieldVal) reduce(v *fieldVal, carry uint64) *fieldVal {
+	var t fieldVal
+	var b uint64
+	t[0], b = bits.Sub64(v[0], fieldP[0], 0)
+	t[1], b = bits.Sub64(v[1], fieldP[1], b)
+	t[2], b = bits.Sub64(v[2], fieldP[2], b)
+	t[3], b = bits.Sub64(v[3], fieldP[3], b)
+	mask := -(carry | (b ^ 1))
+	for i := range f {
+		f[i] = v[i] ^ (mask & (v[i] ^ t[i]))
+	}
+	return f
+}
+
+// add sets f to a + b.
+func (f *fieldVal) add(a, b *fieldVal) *fieldVal {
+	var s fieldVal
+	var c uint64
+	s[0], c = bits.Add64(a[0], b[0], 0)
+	s[1], c = bits.Add64(a[1], b[1], c)
+	s[2], c = bits.Add64(a[2], b[2], c)
+	s[3], c = bits.Add64(a[3], b[3], c)
+	return f.reduce(&s, c)
+}
+
+// sub sets f to a - b.
+func (f *fieldVal) sub(a, b *fieldVal) *fieldVal {
+	var d fieldVal
+	var c uint64
+	d[0], c = bits.Sub64(a[0], b[0], 0)
+	d[1], c = bits.Sub64(a[1], b[1], c)
+	d[2], c = bits.Sub64(a[2], b[2], c)
+	d[3], c = bits.Sub64(a[3], b[3], c)
+	// Add P back if the subtraction borrowed.
+	mask := -c
+	d[0], c = bits.Add64(d[0], fieldP[0]&mask, 0)
+	d[1], c = bits.Add64(d[1], fieldP[1]&mask, c)
+	d[2], c = bits.Add64(d[2], fieldP[2]&mask, c)
+	d[3], _ = bits.Add64(d[3], fieldP[3]&mask, c)
+	*f = d
+	return f
+}
+
+// mul sets f to a * b.
+func (f *fieldVal) mul(a, b *fieldVal) *fieldVal {
+	var t [8]uint64
+	for i := 0; i < 4; i++ {
+		var carry uint64
+		for j := 0; j < 4; j++ {
+			hi, lo := bits.Mul64(a[i], b[j])
+			var c uint64
+			lo, c = bits.Add64(lo, t[i+j], 0)
+			hi += c
+			lo, c = bits.Add64(lo, carry, 0)
+			hi += c
+			t[i+j] = lo
+			carry = hi
+		}
+		t[i+4] = carry
+	}
+
+	// Fold the high half in with 2^256 = fieldC, which leaves at most 34
+	// bits above 2^256, and fold those in again.
+	var r fieldVal
+	var carry uint64
+	for i := 0; i < 4; i++ {
+		hi, lo := bits.Mul64(t[4+i], fieldC)
+		var c uint64
+		lo, c = bits.Add64(lo, t[i], 0)
+		hi += c
+		lo, c = bits.Add64(lo, carry, 0)
+		hi += c
+		r[i] = lo
+		carry = hi
+	}
+	hi, lo := bits.Mul64(carry, fieldC)
+	var c uint64
+	r[0], c = bits.Add64(r[0], lo, 0)
+	r[1], c = bits.Add64(r[1], hi, c)
+	r[2], c = bits.Add64(r[2], 0, c)
+	r[3], c = bits.Add64(r[3], 0, c)
+	// A carry out wraps to a small value, so adding fieldC for it cannot
+	// carry again.
+	r[0], c = bits.Add64(r[0], fieldC&-c, 0)
+	r[1], c = bits.Add64(r[1], 0, c)
+	r[2], c = bits.Add64(r[2], 0, c)
+	r[3], _ = bits.Add64(r[3], 0, c)
+	return f.reduce(&r, 0)
+}
+
+// inverse sets f to 1/a, or zero if a is zero, as a^(P-2). The exponent is
+// public, so the sequence of operations does not depend on a.
+func (f *fieldVal) inverse(a *fieldVal) *fieldVal {
+	r := fieldVal{1}
+	base := *a
+	for _, b := range fieldPMinus2 {
+		for i := 7; i >= 0; i-- {
+			r.mul(&r, &r)
+			if b>>uint(i)&1 == 1 {
+				r.mul(&r, &base)
+			}
+		}
+	}
+	*f = r
+	return f
+}
+
+// projectivePoint is a point in homogeneous projective coordinates, where
+// the affine point is (X/Z, Y/Z). The point at infinity is (0, 1, 0).
+type projectivePoint struct {
+	x, y, z fieldVal
+}
+
+// newProjective returns the projective point of the affine point (x, y), or
+// the point at infinity for (0, 0).
+func newProjective(x, y *big.Int) *projectivePoint {
+	if x.Sign() == 0 && y.Sign() == 0 {
+		return &projectivePoint{y: fieldVal{1}}
+	}
+	p := &projectivePoint{z: fieldVal{1}}
+	p.x.setBig(x)
+	p.y.setBig(y)
+	return p
+}
+
+// affine converts the point to affine coordinates. The point at infinity is
+// returned as (0, 0).
+func (p *projectivePoint) affine() (x, y *big.Int) {
+	if p.z.isZero() {
+		return new(big.Int), new(big.Int)
+	}
+	var zInv, ax, ay fieldVal
+	zInv.inverse(&p.z)
+	ax.mul(&p.x, &zInv)
+	ay.mul(&p.y, &zInv)
+	return ax.big(), ay.big()
+}
+
+// add sets p to a + b with the complete addition formulas for a = 0 of
+// Renes, Costello and Batina, "Complete addition formulas for prime order
+// elliptic curves" (2016), algorithm 7. They hold for every pair of points,
+// including equal points and the point at infinity.
+func (p *projectivePoint) add(a, b *projectivePoint) *projectivePoint {
+	var t0, t1, t2, t3, t4, x3, y3, z3 fieldVal
+	t0.mul(&a.x, &b.x)
+	t1.mul(&a.y, &b.y)
+	t2.mul(&a.z, &b.z)
+	t3.add(&a.x, &a.y)
+	t4.add(&b.x, &b.y)
+	t3.mul(&t3, &t4)
+	t4.add(&t0, &t1)
+	t3.sub(&t3, &t4)
+	t4.add(&a.y, &a.z)
+	x3.add(&b.y, &b.z)
+	t4.mul(&t4, &x3)
+	x3.add(&t1, &t2)
+	t4.sub(&t4, &x3)
+	x3.add(&a.x, &a.z)
+	y3.add(&b.x, &b.z)
+	x3.mul(&x3, &y3)
+	y3.add(&t0, &t2)
+	y3.sub(&x3, &y3)
+	x3.add(&t0, &t0)
+	t0.add(&x3, &t0)
+	t2.mul(&fieldB3, &t2)
+	z3.add(&t1, &t2)
+	t1.sub(&t1, &t2)
+	y3.mul(&fieldB3, &y3)
+	x3.mul(&t4, &y3)
+	t2.mul(&t3, &t1)
+	x3.sub(&t2, &x3)
+	y3.mul(&y3, &t0)
+	t1.mul(&t1, &z3)
+	y3.add(&t1, &y3)
+	t0.mul(&t0, &t3)
+	z3.mul(&z3, &t4)
+	z3.add(&z3, &t0)
+	p.x, p.y, p.z = x3, y3, z3
+	return p
+}
+
+// double sets p to 2a with the complete doubling formulas for a = 0 of the
+// same paper, algorithm 9.
+func (p *projectivePoint) double(a *projectivePoint) *projectivePoint {
+	var t0, t1, t2, x3, y3, z3 fieldVal
+	t0.mul(&a.y, &a.y)
+	z3.add(&t0, &t0)
+	z3.add(&z3, &z3)
+	z3.add(&z3, &z3)
+	t1.mul(&a.y, &a.z)
+	t2.mul(&a.z, &a.z)
+	t2.mul(&fieldB3, &t2)
+	x3.mul(&t2, &z3)
+	y3.add(&t0, &t2)
+	z3.mul(&t1, &z3)
+	t1.add(&t2, &t2)
+	t2.add(&t1, &t2)
+	t0.sub(&t0, &t2)
+	y3.mul(&t0, &y3)
+	y3.add(&x3, &y3)
+	t1.mul(&a.x, &a.y)
+	x3.mul(&t0, &t1)
+	x3.add(&x3, &x3)
+	p.x, p.y, p.z = x3, y3, z3
+	return p
+}
+
+// multTable holds the multiples 0P, 1P, ..., 15P of a point for the 4-bit
+// windows of scalarMult.
+type multTable [16]projectivePoint
+
+// newMultTable returns the window table of p.
+func newMultTable(p *projectivePoint) *multTable {
+	t := new(multTable)
+	t[0] = projectivePoint{y: fieldVal{1}}
+	for i := 1; i < len(t); i++ {
+		t[i].add(&t[i-1], p)
+	}
+	return t
+}
+
+// baseTable is the window table of the base point.
+var baseTable = newMultTable(newProjective(gx, gy))
+
+// scalarMult returns k*P for the point P of table t. The scalar is reduced
+// modulo the group order and processed in 64 fixed 4-bit windows, each
+// selected from the table by scanning every entry, so the time taken does
+// not depend on k.
+func (t *multTable) scalarMult(k *big.Int) *projectivePoint {
+	if k.Sign() < 0 || k.Cmp(order) >= 0 {
+		k = new(big.Int).Mod(k, order)
+	}
+	var kb [32]byte
+	k.FillBytes(kb[:])
+
+	acc := &projectivePoint{y: fieldVal{1}}
+	var q projectivePoint
+	for i := 0; i < 2*len(kb); i++ {
+		w := kb[i/2] >> 4
+		if i%2 == 1 {
+			w = kb[i/2] & 0x0f
+		}
+		for j := 0; j < 4; j++ {
+			acc.double(acc)
+		}
+		for j := range t {
+			mask := -uint64(subtle.ConstantTimeByteEq(uint8(j), w))
+			for l := 0; l < 4; l++ {
+				q.x[l] ^= mask & (q.x[l] ^ t[j].x[l])
+				q.y[l] ^= mask & (q.y[l] ^ t[j].y[l])
+				q.z[l] ^= mask & (q.z[l] ^ t[j].z[l])
+			}
+		}
+		acc.add(acc, &q)
+	}
+	return acc
+}
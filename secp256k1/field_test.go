@@ -0,0 +1,146 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// fieldTestValues returns edge cases and random values in [0, P).
+func fieldTestValues(rng *rand.Rand) []*big.Int {
+	one := big.NewInt(1)
+	pm1 := new(big.Int).Sub(fieldPrime, one)
+	vals := []*big.Int{
+		new(big.Int), one, big.NewInt(2), big.NewInt(fieldC), pm1,
+		new(big.Int).Sub(fieldPrime, big.NewInt(2)),
+		new(big.Int).Rsh(fieldPrime, 1),
+		new(big.Int).Lsh(one, 64),
+		new(big.Int).Sub(new(big.Int).Lsh(one, 255), one),
+		new(big.Int).Lsh(one, 255),
+	}
+	for i := 0; i < 50; i++ {
+		vals = append(vals, new(big.Int).Rand(rng, fieldPrime))
+	}
+	return vals
+}
+
+func TestFieldArithmetic(t *testing.T) {
+	vals := fieldTestValues(rand.New(rand.NewSource(1)))
+	mod := func(v *big.Int) *big.Int { return v.Mod(v, fieldPrime) }
+	for i, a := range vals {
+		var fa fieldVal
+		fa.setBig(a)
+		if fa.big().Cmp(a) != 0 {
+			t.Fatalf("%d: expected %x, got %x", i, a, fa.big())
+		}
+		for j, b := range vals {
+			var fb, r fieldVal
+			fb.setBig(b)
+			if want := mod(new(big.Int).Add(a, b)); r.add(&fa, &fb).big().Cmp(want) != 0 {
+				t.Fatalf("%d+%d: expected %x, got %x", i, j, want, r.big())
+			}
+			if want := mod(new(big.Int).Sub(a, b)); r.sub(&fa, &fb).big().Cmp(want) != 0 {
+				t.Fatalf("%d-%d: expected %x, got %x", i, j, want, r.big())
+			}
+			if want := mod(new(big.Int).Mul(a, b)); r.mul(&fa, &fb).big().Cmp(want) != 0 {
+				t.Fatalf("%d*%d: expected %x, got %x", i, j, want, r.big())
+			}
+		}
+		var inv fieldVal
+		inv.inverse(&fa)
+		want := new(big.Int)
+		if a.Sign() != 0 {
+			want.ModInverse(a, fieldPrime)
+		}
+		if inv.big().Cmp(want) != 0 {
+			t.Fatalf("1/%d: expected %x, got %x", i, want, inv.big())
+		}
+	}
+
+	// Values outside [0, P) are reduced.
+	var f fieldVal
+	if f.setBig(new(big.Int).Add(fieldPrime, big.NewInt(5))).big().Int64() != 5 {
+		t.Errorf("expected 5, got %x", f.big())
+	}
+	if f.setBig(big.NewInt(-1)).big().Cmp(new(big.Int).Sub(fieldPrime, big.NewInt(1))) != 0 {
+		t.Errorf("expected P-1, got %x", f.big())
+	}
+}
+
+// scalarMultRef is the variable-time double-and-add multiplication in
+// Jacobian coordinates that the constant-time one replaces.
+func scalarMultRef(x, y, k *big.Int) (rx, ry *big.Int) {
+	p := newJacobian(x, y)
+	acc := &jacobianPoint{new(big.Int), new(big.Int), new(big.Int)}
+	kk := new(big.Int).Mod(k, order)
+	for i := kk.BitLen() - 1; i >= 0; i-- {
+		acc = acc.double()
+		if kk.Bit(i) == 1 {
+			acc = acc.add(p)
+		}
+	}
+	return acc.affine()
+}
+
+func TestScalarMultConstantTime(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	one := big.NewInt(1)
+	scalars := []*big.Int{
+		new(big.Int), one, big.NewInt(15), big.NewInt(16), big.NewInt(17),
+		new(big.Int).Sub(order, one), order, new(big.Int).Add(order, one),
+		new(big.Int).Lsh(one, 255), new(big.Int).Lsh(one, 256),
+		big.NewInt(-3),
+	}
+	for i := 0; i < 30; i++ {
+		scalars = append(scalars, new(big.Int).Rand(rng, order))
+	}
+	px, py := scalarMultRef(gx, gy, big.NewInt(0x1234567))
+	for i, k := range scalars {
+		wx, wy := scalarMultRef(gx, gy, k)
+		if x, y := ScalarBaseMult(k); x.Cmp(wx) != 0 || y.Cmp(wy) != 0 {
+			t.Errorf("%d: expected (%x, %x), got (%x, %x)", i, wx, wy, x, y)
+		}
+		wx, wy = scalarMultRef(px, py, k)
+		if x, y := ScalarMult(px, py, k); x.Cmp(wx) != 0 || y.Cmp(wy) != 0 {
+			t.Errorf("%d: expected (%x, %x), got (%x, %x)", i, wx, wy, x, y)
+		}
+	}
+
+	// Multiples of the point at infinity are the point at infinity.
+	if x, y := ScalarMult(new(big.Int), new(big.Int), big.NewInt(5)); x.Sign() != 0 || y.Sign() != 0 {
+		t.Errorf("expected (0, 0), got (%x, %x)", x, y)
+	}
+}
+
+func TestProjectiveAdd(t *testing.T) {
+	// The complete formulas handle doubling, inverses and the point at
+	// infinity in add.
+	g := newProjective(gx, gy)
+	inf := newProjective(new(big.Int), new(big.Int))
+	negG := newProjective(gx, new(big.Int).Sub(fieldPrime, gy))
+	var p projectivePoint
+	if x, y := p.add(g, g).affine(); x.Cmp(fromHex("c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5")) != 0 ||
+		y.Cmp(fromHex("1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a")) != 0 {
+		t.Errorf("G+G: unexpected (%x, %x)", x, y)
+	}
+	if x, y := p.add(g, inf).affine(); x.Cmp(gx) != 0 || y.Cmp(gy) != 0 {
+		t.Errorf("G+O: unexpected (%x, %x)", x, y)
+	}
+	if x, y := p.add(g, negG).affine(); x.Sign() != 0 || y.Sign() != 0 {
+		t.Errorf("G-G: unexpected (%x, %x)", x, y)
+	}
+	if x, y := p.double(inf).affine(); x.Sign() != 0 || y.Sign() != 0 {
+		t.Errorf("2O: unexpected (%x, %x)", x, y)
+	}
+}
+
+func BenchmarkScalarBaseMult(b *testing.B) {
+	k := fromHex("aa5e28d6a97a2479a65527f7290311a3624d4cc0fa1578598ee3c2613bf99522")
+	for i := 0; i < b.N; i++ {
+		ScalarBaseMult(k)
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+const (
+	// PrivKeyBytesLen is the length of a serialized private key.
+	PrivKeyBytesLen = 32
+
+	// PubKeyBytesLenCompressed is the length of a serialized compressed
+	// public key.
+	PubKeyBytesLenCompressed = 33
+
+	// PubKeyBytesLenUncompressed is the length of a serialized uncompressed
+	// public key.
+	PubKeyBytesLenUncompressed = 65
+
+	pubKeyFormatCompressedEven byte = 0x02
+	pubKeyFormatCompressedOdd  byte = 0x03
+	pubKeyFormatUncompressed   byte = 0x04
+)
+
+var (
+	// ErrInvalidPrivKey is returned for private keys that are not in the
+	// range [1, N).
+	ErrInvalidPrivKey = errors.New("secp256k1: invalid private key")
+
+	// ErrInvalidPubKey is returned for malformed public keys and points
+	// that are not on the curve.
+	ErrInvalidPubKey = errors.New("secp256k1: invalid public key")
+)
+
+// PrivateKey is a secp256k1 private key.
+type PrivateKey struct {
+	// D is the secret scalar in the range [1, N).
+	D *big.Int
+}
+
+// PrivKeyFromBytes returns the private key of the big-endian scalar b, which
+// must be at most 32 bytes and in the range [1, N).
+func PrivKeyFromBytes(b []byte) (*PrivateKey, error) {
+	d := new(big.Int).SetBytes(b)
+	if len(b) > PrivKeyBytesLen || d.Sign() == 0 || d.Cmp(order) >= 0 {
+		return nil, ErrInvalidPrivKey
+	}
+	return &PrivateKey{D: d}, nil
+}
+
+// PubKey returns the public key D*G of the private key.
+func (k *PrivateKey) PubKey() *PublicKey {
+	x, y := ScalarBaseMult(k.D)
+	return &PublicKey{X: x, Y: y}
+}
+
+// Serialize returns the private key as a 32-byte big-endian scalar.
+func (k *PrivateKey) Serialize() []byte {
+	return k.D.FillBytes(make([]byte, PrivKeyBytesLen))
+}
+
+// PublicKey is a secp256k1 public key.
+type PublicKey struct {
+	X, Y *big.Int
+}
+
+// NewPublicKey returns the public key with the given affine coordinates. It
+// does not check that the point is on the curve.
+func NewPublicKey(x, y *big.Int) *PublicKey {
+	return &PublicKey{X: new(big.Int).Set(x), Y: new(big.Int).Set(y)}
+}
+
+// ParsePubKey parses a public key in the 33-byte compressed or 65-byte
+// uncompressed format and checks that it is on the curve.
+func ParsePubKey(b []byte) (*PublicKey, error) {
+	switch {
+	case len(b) == PubKeyBytesLenCompressed &&
+		(b[0] == pubKeyFormatCompressedEven || b[0] == pubKeyFormatCompressedOdd):
+		x := new(big.Int).SetBytes(b[1:])
+		y, ok := DecompressY(x, b[0] == pubKeyFormatCompressedOdd)
+		if !ok {
+			return nil, fmt.Errorf("%w: x coordinate is not on the curve",
+				ErrInvalidPubKey)
+		}
+		return &PublicKey{X: x, Y: y}, nil
+
+	case len(b) == PubKeyBytesLenUncompressed && b[0] == pubKeyFormatUncompressed:
+		pk := &PublicKey{
+			X: new(big.Int).SetBytes(b[1:33]),
+			Y: new(big.Int).SetBytes(b[33:]),
+		}
+		if !pk.IsOnCurve() {
+			return nil, fmt.Errorf("%w: point is not on the curve",
+				ErrInvalidPubKey)
+		}
+		return pk, nil
+	}
+	return nil, fmt.Errorf("%w: unsupported format or length %d",
+		ErrInvalidPubKey, len(b))
+}
+
+// IsOnCurve reports whether the public key is a point on the curve.
+func (p *PublicKey) IsOnCurve() bool {
+	return IsOnCurve(p.X, p.Y)
+}
+
+// SerializeCompressed returns the public key in the 33-byte compressed
+// format.
+func (p *PublicKey) SerializeCompressed() []byte {
+	b := make([]byte, PubKeyBytesLenCompressed)
+	b[0] = pubKeyFormatCompressedEven
+	if p.Y.Bit(0) == 1 {
+		b[0] = pubKeyFormatCompressedOdd
+	}
+	p.X.FillBytes(b[1:])
+	return b
+}
+
+// SerializeUncompressed returns the public key in the 65-byte uncompressed
+// format.
+func (p *PublicKey) SerializeUncompressed() []byte {
+	b := make([]byte, PubKeyBytesLenUncompressed)
+	b[0] = pubKeyFormatUncompressed
+	p.X.FillBytes(b[1:33])
+	p.Y.FillBytes(b[33:])
+	return b
+}
+
+// IsEqual reports whether the two public keys are the same point.
+func (p *PublicKey) IsEqual(other *PublicKey) bool {
+	return p.X.Cmp(other.X) == 0 && p.Y.Cmp(other.Y) == 0
+}
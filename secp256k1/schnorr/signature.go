@@ -0,0 +1,252 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package schnorr implements the EC-Schnorr-DCRv0 signature scheme used by
+// Decred. Signatures are made over secp256k1 and the challenge is the
+// BLAKE-256 hash of r || m.
+//
+// Signing a 32-byte hash m with private key d proceeds as follows:
+//
+//  1. Fail if m is not 32 bytes or d is not in [1, N)
+//  2. Derive a nonce k with RFC 6979 and HMAC-SHA256
+//  3. R = kG, negating k if R.y is odd
+//  4. r = R.x
+//  5. e = BLAKE-256(r || m), retrying from step 2 if e >= N
+//  6. s = k - e*d mod N
+//
+// and verification checks that R = sG + eQ is not the point at infinity, has
+// an even y coordinate and R.x == r.
+package schnorr
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/rickiey/blake256"
+	"github.com/rickiey/blake256/secp256k1"
+)
+
+const (
+	// SignatureSize is the size of a serialized signature.
+	SignatureSize = 64
+
+	scalarSize = 32
+)
+
+// rfc6979ExtraDataV0 is the extra data passed to RFC 6979 nonce generation to
+// separate EC-Schnorr-DCRv0 nonces from those of other schemes. It is the
+// BLAKE-256 hash of "EC-Schnorr-DCRv0".
+var rfc6979ExtraDataV0 = [scalarSize]byte{
+	0x0b, 0x75, 0xf9, 0x7b, 0x60, 0xe8, 0xa5, 0x76,
+	0x28, 0x76, 0xc0, 0x04, 0x82, 0x9e, 0xe9, 0xb9,
+	0x26, 0xfa, 0x6f, 0x0d, 0x2e, 0xea, 0xec, 0x3a,
+	0x4f, 0xd1, 0x44, 0x6a, 0x76, 0x83, 0x31, 0xcb,
+}
+
+var (
+	// ErrInvalidHashLen is returned when the hash being signed or verified is
+	// not 32 bytes.
+	ErrInvalidHashLen = errors.New("schnorr: invalid hash length")
+
+	// ErrPrivateKeyIsZero is returned when signing with a zero private key.
+	ErrPrivateKeyIsZero = errors.New("schnorr: private key is zero")
+
+	// ErrSchnorrHashValue is returned when the challenge BLAKE-256(r || m) is
+	// not less than the group order.
+	ErrSchnorrHashValue = errors.New("schnorr: hash of (r || m) too big")
+
+	// ErrPubKeyNotOnCurve is returned when verifying against a public key
+	// that is not on the curve.
+	ErrPubKeyNotOnCurve = errors.New("schnorr: public key is not on the curve")
+
+	// ErrSigRYIsOdd is returned when the R point computed during verification
+	// has an odd y coordinate.
+	ErrSigRYIsOdd = errors.New("schnorr: calculated R y-value is odd")
+
+	// ErrSigRNotOnCurve is returned when the R point computed during
+	// verification is the point at infinity.
+	ErrSigRNotOnCurve = errors.New("schnorr: calculated R is the point at infinity")
+
+	// ErrUnequalRValues is returned when the x coordinate of the R point
+	// computed during verification does not equal r.
+	ErrUnequalRValues = errors.New("schnorr: calculated R does not match r")
+
+	// ErrSigTooShort is returned when a serialized signature is shorter than
+	// SignatureSize.
+	ErrSigTooShort = errors.New("schnorr: signature too short")
+
+	// ErrSigTooLong is returned when a serialized signature is longer than
+	// SignatureSize.
+	ErrSigTooLong = errors.New("schnorr: signature too long")
+
+	// ErrSigRTooBig is returned when r is not less than the field prime.
+	ErrSigRTooBig = errors.New("schnorr: signature r is not less than the field prime")
+
+	// ErrSigSTooBig is returned when s is not less than the group order.
+	ErrSigSTooBig = errors.New("schnorr: signature s is not less than the group order")
+)
+
+// Signature is an EC-Schnorr-DCRv0 signature.
+type Signature struct {
+	r, s *big.Int
+}
+
+// NewSignature returns the signature with the given r and s values.
+func NewSignature(r, s *big.Int) *Signature {
+	return &Signature{r: new(big.Int).Set(r), s: new(big.Int).Set(s)}
+}
+
+// R returns the r value of the signature.
+func (sig *Signature) R() *big.Int { return new(big.Int).Set(sig.r) }
+
+// S returns the s value of the signature.
+func (sig *Signature) S() *big.Int { return new(big.Int).Set(sig.s) }
+
+// Serialize returns the signature as r || s, each as a 32-byte big-endian
+// integer.
+func (sig *Signature) Serialize() []byte {
+	b := make([]byte, SignatureSize)
+	sig.r.FillBytes(b[:scalarSize])
+	sig.s.FillBytes(b[scalarSize:])
+	return b
+}
+
+// ParseSignature parses a signature serialized by Serialize. It requires r to
+// be less than the field prime and s to be less than the group order.
+func ParseSignature(sig []byte) (*Signature, error) {
+	switch {
+	case len(sig) < SignatureSize:
+		return nil, fmt.Errorf("%w: got %d bytes, want %d", ErrSigTooShort,
+			len(sig), SignatureSize)
+	case len(sig) > SignatureSize:
+		return nil, fmt.Errorf("%w: got %d bytes, want %d", ErrSigTooLong,
+			len(sig), SignatureSize)
+	}
+	r := new(big.Int).SetBytes(sig[:scalarSize])
+	if r.Cmp(secp256k1.FieldPrime()) >= 0 {
+		return nil, ErrSigRTooBig
+	}
+	s := new(big.Int).SetBytes(sig[scalarSize:])
+	if s.Cmp(secp256k1.Order()) >= 0 {
+		return nil, ErrSigSTooBig
+	}
+	return &Signature{r: r, s: s}, nil
+}
+
+// IsEqual reports whether the two signatures are identical.
+func (sig *Signature) IsEqual(other *Signature) bool {
+	return sig.r.Cmp(other.r) == 0 && sig.s.Cmp(other.s) == 0
+}
+
+// challenge returns e = BLAKE-256(r || m) as an integer, or an error if it is
+// not less than the group order.
+func challenge(r *big.Int, hash []byte) (*big.Int, error) {
+	var input [2 * scalarSize]byte
+	r.FillBytes(input[:scalarSize])
+	copy(input[scalarSize:], hash)
+	commitment := blake256.Sum256(input[:])
+	e := new(big.Int).SetBytes(commitment[:])
+	if e.Cmp(secp256k1.Order()) >= 0 {
+		return nil, ErrSchnorrHashValue
+	}
+	return e, nil
+}
+
+// schnorrVerify checks sig against hash and pubKey and returns an error
+// describing the first check that fails.
+func schnorrVerify(sig *Signature, hash []byte, pubKey *secp256k1.PublicKey) error {
+	if len(hash) != scalarSize {
+		return fmt.Errorf("%w: got %d bytes, want %d", ErrInvalidHashLen,
+			len(hash), scalarSize)
+	}
+	if !pubKey.IsOnCurve() {
+		return ErrPubKeyNotOnCurve
+	}
+	if sig.r.Cmp(secp256k1.FieldPrime()) >= 0 {
+		return ErrSigRTooBig
+	}
+	if sig.s.Cmp(secp256k1.Order()) >= 0 {
+		return ErrSigSTooBig
+	}
+	e, err := challenge(sig.r, hash)
+	if err != nil {
+		return err
+	}
+
+	// R = sG + eQ
+	sGx, sGy := secp256k1.ScalarBaseMult(sig.s)
+	eQx, eQy := secp256k1.ScalarMult(pubKey.X, pubKey.Y, e)
+	rx, ry := secp256k1.Add(sGx, sGy, eQx, eQy)
+	if rx.Sign() == 0 && ry.Sign() == 0 {
+		return ErrSigRNotOnCurve
+	}
+	if ry.Bit(0) == 1 {
+		return ErrSigRYIsOdd
+	}
+	if rx.Cmp(sig.r) != 0 {
+		return ErrUnequalRValues
+	}
+	return nil
+}
+
+// Verify reports whether sig is a valid signature of hash by pubKey.
+func (sig *Signature) Verify(hash []byte, pubKey *secp256k1.PublicKey) bool {
+	return schnorrVerify(sig, hash, pubKey) == nil
+}
+
+// schnorrSign signs hash with the private key d and nonce k, both of which
+// must be in [1, N). It fails with ErrSchnorrHashValue if the challenge is
+// out of range, in which case the caller should retry with a new nonce.
+func schnorrSign(d, k *big.Int, hash []byte) (*Signature, error) {
+	n := secp256k1.Order()
+
+	// R = kG, negating k if R.y is odd so that R.y is always even.
+	rx, ry := secp256k1.ScalarBaseMult(k)
+	k = new(big.Int).Set(k)
+	if ry.Bit(0) == 1 {
+		k.Sub(n, k)
+	}
+
+	e, err := challenge(rx, hash)
+	if err != nil {
+		return nil, err
+	}
+
+	// s = k - e*d mod N
+	s := new(big.Int).Mul(e, d)
+	s.Sub(k, s)
+	s.Mod(s, n)
+	return &Signature{r: rx, s: s}, nil
+}
+
+// Sign returns the EC-Schnorr-DCRv0 signature of the 32-byte hash by privKey.
+// The nonce is derived deterministically with RFC 6979, so the same key and
+// hash always yield the same signature.
+//
+// The multiplication of the nonce by the base point runs in constant time,
+// but the arithmetic modulo N that combines the nonce, the private key and
+// the hash uses math/big and is not constant time, so signing should not be
+// exposed to attackers who can measure its timing precisely.
+func Sign(privKey *secp256k1.PrivateKey, hash []byte) (*Signature, error) {
+	if len(hash) != scalarSize {
+		return nil, fmt.Errorf("%w: got %d bytes, want %d", ErrInvalidHashLen,
+			len(hash), scalarSize)
+	}
+	if privKey.D.Sign() == 0 {
+		return nil, ErrPrivateKeyIsZero
+	}
+
+	n := secp256k1.Order()
+	privKeyBytes := privKey.Serialize()
+	for iteration := uint32(0); ; iteration++ {
+		k := blake256.NonceRFC6979(n, sha256.New, privKeyBytes, hash,
+			rfc6979ExtraDataV0[:], iteration)
+		sig, err := schnorrSign(privKey.D, k, hash)
+		if err == nil {
+			return sig, nil
+		}
+	}
+}
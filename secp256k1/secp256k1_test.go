@@ -0,0 +1,129 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package secp256k1
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestScalarBaseMult(t *testing.T) {
+	tests := []struct {
+		k, x, y string
+	}{
+		{"1",
+			"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			"483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"},
+		{"2",
+			"c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee5",
+			"1ae168fea63dc339a3c58419466ceaeef7f632653266d0e1236431a950cfe52a"},
+		{"3",
+			"f9308a019258c31049344f85f89d5229b531c845836f99b08601f113bce036f9",
+			"388f7b0f632de8140fe337e62a37f3566500a99934c2231b6cb9fd7584b8e672"},
+		{"aa5e28d6a97a2479a65527f7290311a3624d4cc0fa1578598ee3c2613bf99522",
+			"34f9460f0e4f08393d192b3c5133a6ba099aa0ad9fd54ebccfacdfa239ff49c6",
+			"0b71ea9bd730fd8923f6d25a7a91e7dd7728a960686cb5a901bb419e0f2ca232"},
+		{"fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364140",
+			"79be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798",
+			"b7c52588d95c3b9aa25b0403f1eef75702e84bb7597aabe663b82f6f04ef2777"},
+	}
+	for i, test := range tests {
+		k := fromHex(test.k)
+		x, y := ScalarBaseMult(k)
+		if x.Cmp(fromHex(test.x)) != 0 || y.Cmp(fromHex(test.y)) != 0 {
+			t.Errorf("%d: expected (%s, %s), got (%x, %x)", i, test.x, test.y, x, y)
+		}
+		if !IsOnCurve(x, y) {
+			t.Errorf("%d: result is not on the curve", i)
+		}
+	}
+
+	// N*G is the point at infinity.
+	x, y := ScalarBaseMult(Order())
+	if x.Sign() != 0 || y.Sign() != 0 {
+		t.Errorf("expected point at infinity, got (%x, %x)", x, y)
+	}
+}
+
+func TestAdd(t *testing.T) {
+	gx, gy := Generator()
+	g2x, g2y := ScalarBaseMult(big.NewInt(2))
+	g3x, g3y := ScalarBaseMult(big.NewInt(3))
+
+	// G + G doubles and G + 2G = 3G.
+	if x, y := Add(gx, gy, gx, gy); x.Cmp(g2x) != 0 || y.Cmp(g2y) != 0 {
+		t.Errorf("G+G: expected (%x, %x), got (%x, %x)", g2x, g2y, x, y)
+	}
+	if x, y := Add(gx, gy, g2x, g2y); x.Cmp(g3x) != 0 || y.Cmp(g3y) != 0 {
+		t.Errorf("G+2G: expected (%x, %x), got (%x, %x)", g3x, g3y, x, y)
+	}
+
+	// G + -G is the point at infinity, which is the identity.
+	negY := new(big.Int).Sub(FieldPrime(), gy)
+	x, y := Add(gx, gy, gx, negY)
+	if x.Sign() != 0 || y.Sign() != 0 {
+		t.Errorf("G-G: expected point at infinity, got (%x, %x)", x, y)
+	}
+	if x, y := Add(x, y, g2x, g2y); x.Cmp(g2x) != 0 || y.Cmp(g2y) != 0 {
+		t.Errorf("O+2G: expected (%x, %x), got (%x, %x)", g2x, g2y, x, y)
+	}
+}
+
+func TestKeys(t *testing.T) {
+	priv, err := PrivKeyFromBytes(hexToBytes("aa5e28d6a97a2479a65527f7290311a3624d4cc0fa1578598ee3c2613bf99522"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pub := priv.PubKey()
+	compressed := pub.SerializeCompressed()
+	want := hexToBytes("0234f9460f0e4f08393d192b3c5133a6ba099aa0ad9fd54ebccfacdfa239ff49c6")
+	if !bytes.Equal(compressed, want) {
+		t.Errorf("expected %x, got %x", want, compressed)
+	}
+	for _, b := range [][]byte{compressed, pub.SerializeUncompressed()} {
+		parsed, err := ParsePubKey(b)
+		if err != nil {
+			t.Fatalf("%x: unexpected error: %v", b, err)
+		}
+		if !parsed.IsEqual(pub) {
+			t.Errorf("%x: parsed key differs", b)
+		}
+	}
+	if got := priv.Serialize(); !bytes.Equal(got, hexToBytes("aa5e28d6a97a2479a65527f7290311a3624d4cc0fa1578598ee3c2613bf99522")) {
+		t.Errorf("unexpected serialized private key %x", got)
+	}
+
+	for _, b := range [][]byte{nil, make([]byte, 32), Order().Bytes()} {
+		if _, err := PrivKeyFromBytes(b); !errors.Is(err, ErrInvalidPrivKey) {
+			t.Errorf("%x: expected %v, got %v", b, ErrInvalidPrivKey, err)
+		}
+	}
+
+	bad := [][]byte{
+		nil,
+		compressed[:32],
+		// x coordinate with no point on the curve.
+		append([]byte{0x02}, make([]byte, 31)...),
+		append([]byte{0x02}, hexToBytes("0000000000000000000000000000000000000000000000000000000000000005")...),
+		// Uncompressed point with a modified y coordinate.
+		append(pub.SerializeUncompressed()[:64], 0),
+	}
+	for _, b := range bad {
+		if _, err := ParsePubKey(b); !errors.Is(err, ErrInvalidPubKey) {
+			t.Errorf("%x: expected %v, got %v", b, ErrInvalidPubKey, err)
+		}
+	}
+}
+
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
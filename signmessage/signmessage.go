@@ -0,0 +1,122 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package signmessage implements the message signatures produced by the
+// signmessage and verified by the verifymessage RPCs of Decred wallets and
+// nodes.
+//
+// The signed digest is BLAKE-256 of the varint length-prefixed Magic string
+// followed by the varint length-prefixed message, and the signature is a
+// 65-byte compact ECDSA signature from which the signer's public key can be
+// recovered. A signature proves ownership of a pay-to-pubkey-hash address by
+// recovering a key that hashes to it. The RPCs exchange signatures as base64.
+package signmessage
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/rickiey/blake256"
+	"github.com/rickiey/blake256/address"
+	"github.com/rickiey/blake256/secp256k1"
+	"github.com/rickiey/blake256/secp256k1/ecdsa"
+)
+
+// Magic is the preamble that separates signed messages from transactions
+// and other signed data.
+const Magic = "Decred Signed Message:\n"
+
+// ErrAddressMismatch is returned when a signature is valid but was made by a
+// key other than the one of the address.
+var ErrAddressMismatch = errors.New("signmessage: signature is not by the address owner")
+
+// appendVarString appends s prefixed with its length encoded as a Decred wire
+// variable length integer.
+func appendVarString(b []byte, s string) []byte {
+	n := uint64(len(s))
+	var buf [9]byte
+	switch {
+	case n < 0xfd:
+		b = append(b, byte(n))
+	case n <= 0xffff:
+		buf[0] = 0xfd
+		binary.LittleEndian.PutUint16(buf[1:], uint16(n))
+		b = append(b, buf[:3]...)
+	case n <= 0xffffffff:
+		buf[0] = 0xfe
+		binary.LittleEndian.PutUint32(buf[1:], uint32(n))
+		b = append(b, buf[:5]...)
+	default:
+		buf[0] = 0xff
+		binary.LittleEndian.PutUint64(buf[1:], n)
+		b = append(b, buf[:]...)
+	}
+	return append(b, s...)
+}
+
+// Hash returns the digest signed for message.
+func Hash(message string) [blake256.Size]byte {
+	b := make([]byte, 0, 1+len(Magic)+9+len(message))
+	b = appendVarString(b, Magic)
+	b = appendVarString(b, message)
+	return blake256.Sum256(b)
+}
+
+// Sign returns the compact signature of message by privKey. The compressed
+// parameter selects whether the signature commits to the compressed or
+// uncompressed public key, and so which of the two addresses it proves.
+func Sign(privKey *secp256k1.PrivateKey, message string, compressed bool) []byte {
+	h := Hash(message)
+	return ecdsa.SignCompact(privKey, h[:], compressed)
+}
+
+// SignBase64 is like Sign but returns the signature base64 encoded, as
+// returned by the signmessage RPC.
+func SignBase64(privKey *secp256k1.PrivateKey, message string, compressed bool) string {
+	return base64.StdEncoding.EncodeToString(Sign(privKey, message, compressed))
+}
+
+// Recover returns the public key that signed message and whether the
+// signature commits to its compressed serialization.
+func Recover(sig []byte, message string) (*secp256k1.PublicKey, bool, error) {
+	h := Hash(message)
+	pub, compressed, err := ecdsa.RecoverCompact(sig, h[:])
+	if err != nil {
+		return nil, false, fmt.Errorf("signmessage: %w", err)
+	}
+	return pub, compressed, nil
+}
+
+// Verify checks that sig is a signature of message by the owner of the
+// pay-to-pubkey-hash address addr on the network.
+func Verify(addr string, sig []byte, message string, net *address.Params) error {
+	want, err := address.DecodePubKeyHash(addr, net)
+	if err != nil {
+		return err
+	}
+	pub, compressed, err := Recover(sig, message)
+	if err != nil {
+		return err
+	}
+	b := pub.SerializeUncompressed()
+	if compressed {
+		b = pub.SerializeCompressed()
+	}
+	if address.Hash160(b) != want {
+		return ErrAddressMismatch
+	}
+	return nil
+}
+
+// VerifyBase64 is like Verify but takes the signature base64 encoded, as
+// accepted by the verifymessage RPC.
+func VerifyBase64(addr, sig, message string, net *address.Params) error {
+	b, err := base64.StdEncoding.DecodeString(sig)
+	if err != nil {
+		return fmt.Errorf("signmessage: malformed base64 signature: %w", err)
+	}
+	return Verify(addr, b, message, net)
+}
@@ -0,0 +1,144 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package signmessage
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rickiey/blake256/address"
+	"github.com/rickiey/blake256/secp256k1"
+	"github.com/rickiey/blake256/secp256k1/ecdsa"
+)
+
+var messageTests = []struct {
+	key        string
+	message    string
+	compressed bool
+	hash       string
+	addr       string
+	sig        string
+}{
+	{
+		"01",
+		"test message",
+		true,
+		"febaa090eaa8aa82f311a1ca4f54467e8ad0e4a42cc0f1b2f2e759b963742dde",
+		"DsmcYVbP1Nmag2H4AS17UTvmWXmGeA7nLDx",
+		"H18ier4CIfSBOk0FKPjO4mggno0ES1w2P+41GpJnnyiSRWdE2n02YwE29Sw0n2ALT3M1Q1+GQW7moKqsem1COF8=",
+	},
+	{
+		"aa5e28d6a97a2479a65527f7290311a3624d4cc0fa1578598ee3c2613bf99522",
+		"I own this address",
+		false,
+		"407f659cd16e9f66bc9b265f57f6b61e40b8d7ed0f67ece67375d9500aeac8b3",
+		"DsbwM1bQw9PtbnhMvk3grNZppgd8uS72kTs",
+		"HGg27P9dAVX75e3eJJKGDT+dglGQxAg1XFKUDmnFbZo+Abg8tUP+nrHYGoQajQPkd7+PY6aXovO7/RLwluI8SvQ=",
+	},
+	{
+		// A message long enough to need a three byte length prefix.
+		"aa5e28d6a97a2479a65527f7290311a3624d4cc0fa1578598ee3c2613bf99522",
+		strings.Repeat("x", 300),
+		true,
+		"395880a914e387044372366b4c0eb9ae8af8b52d1d4a05fb9c56d42d57eafcfd",
+		"Dse52gVLeSDrn4WZgc5ZeWWvLEfUAm5epqw",
+		"ILuVehmQ8ciRHCGYOLg6SiRqUPwSjQEoFPuun0shax9vRpYMvNDO25rvupM4Sjn9YmNbYUGosYY0XpRQG6nlm2c=",
+	},
+}
+
+func TestSignVerify(t *testing.T) {
+	for i, test := range messageTests {
+		if got := fmt.Sprintf("%x", Hash(test.message)); got != test.hash {
+			t.Errorf("%d: expected %q, got %q", i, test.hash, got)
+		}
+
+		priv, err := secp256k1.PrivKeyFromBytes(hexToBytes(test.key))
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		if got := SignBase64(priv, test.message, test.compressed); got != test.sig {
+			t.Errorf("%d: expected %q, got %q", i, test.sig, got)
+		}
+		if got := address.EncodePubKey(priv.PubKey(), test.compressed, address.MainNetParams); got != test.addr {
+			t.Errorf("%d: expected %q, got %q", i, test.addr, got)
+		}
+		if err := VerifyBase64(test.addr, test.sig, test.message, address.MainNetParams); err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+		}
+	}
+}
+
+func TestVerifyErrors(t *testing.T) {
+	test := messageTests[0]
+	other := messageTests[1]
+	sig, _ := base64.StdEncoding.DecodeString(test.sig)
+
+	// The signature made for the compressed key does not prove ownership of
+	// the uncompressed key's address.
+	priv, _ := secp256k1.PrivKeyFromBytes(hexToBytes(test.key))
+	uncompressed := address.EncodePubKey(priv.PubKey(), false, address.MainNetParams)
+
+	bad := append([]byte(nil), sig...)
+	bad[0] = 0
+
+	tests := []struct {
+		addr    string
+		sig     []byte
+		message string
+		net     *address.Params
+		err     error
+	}{
+		{other.addr, sig, test.message, address.MainNetParams, ErrAddressMismatch},
+		{uncompressed, sig, test.message, address.MainNetParams, ErrAddressMismatch},
+		{test.addr, sig, test.message + ".", address.MainNetParams, ErrAddressMismatch},
+		{test.addr, sig, test.message, address.TestNet3Params, address.ErrWrongNetwork},
+		{test.addr, sig[:64], test.message, address.MainNetParams, ecdsa.ErrSigInvalidLen},
+		{test.addr, bad, test.message, address.MainNetParams, ecdsa.ErrSigInvalidRecoveryCode},
+	}
+	for i, test := range tests {
+		if err := Verify(test.addr, test.sig, test.message, test.net); !errors.Is(err, test.err) {
+			t.Errorf("%d: expected %v, got %v", i, test.err, err)
+		}
+	}
+
+	if err := VerifyBase64(test.addr, "not base64!", test.message, address.MainNetParams); err == nil {
+		t.Error("expected error for malformed base64")
+	}
+}
+
+func TestAppendVarString(t *testing.T) {
+	tests := []struct {
+		n      int
+		prefix string
+	}{
+		{0, "00"},
+		{0xfc, "fc"},
+		{0xfd, "fdfd00"},
+		{0xffff, "fdffff"},
+		{0x10000, "fe00000100"},
+	}
+	for i, test := range tests {
+		b := appendVarString(nil, strings.Repeat("a", test.n))
+		if len(b) != len(test.prefix)/2+test.n {
+			t.Errorf("%d: unexpected length %d", i, len(b))
+			continue
+		}
+		if got := fmt.Sprintf("%x", b[:len(test.prefix)/2]); got != test.prefix {
+			t.Errorf("%d: expected %q, got %q", i, test.prefix, got)
+		}
+	}
+}
+
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
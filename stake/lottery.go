@@ -0,0 +1,210 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package stake implements the Decred ticket lottery that selects the
+// tickets eligible to vote on each block.
+//
+// The lottery is driven by Hash256PRNG, a deterministic PRNG that draws
+// 32-bit big-endian words from successive BLAKE-256 hashes of a seed derived
+// from a block header. The winners that vote on a block are drawn with a
+// PRNG seeded by the header of its parent, from the parent's live tickets
+// sorted by hash. The first six bytes of the final PRNG state hash are
+// committed to by the FinalState field of the child block header.
+package stake
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/rickiey/blake256/wire"
+)
+
+// seedConst is appended to the seed before hashing it into the PRNG initial
+// state. These are the first fractional digits of pi in hex.
+var seedConst = [8]byte{0x24, 0x3F, 0x6A, 0x88, 0x85, 0xA3, 0x08, 0xD3}
+
+var (
+	// ErrNotEnoughTickets is returned when fewer tickets are available
+	// than winners requested.
+	ErrNotEnoughTickets = errors.New("stake: not enough tickets for lottery")
+
+	// ErrTooManyTickets is returned when the ticket pool cannot be indexed
+	// with 32-bit values.
+	ErrTooManyTickets = errors.New("stake: too many tickets for lottery")
+
+	// ErrTicketsNotSorted is returned when the ticket list passed to
+	// FindWinners is not sorted in strictly ascending order.
+	ErrTicketsNotSorted = errors.New("stake: tickets are not sorted")
+)
+
+// Hash256PRNG is a deterministic PRNG driven by BLAKE-256. It returns the
+// eight 32-bit words of each hash in turn, then hashes the seed with an
+// incrementing counter to produce the next hash. When the counter overflows
+// the seed itself is rehashed.
+//
+// A Hash256PRNG is not safe for concurrent use.
+type Hash256PRNG struct {
+	seed     wire.Hash // Seed used to generate hashes
+	lastHash wire.Hash // Hash words are currently drawn from
+	idx      uint64    // Counter appended to the seed for the next hash
+	hashIdx  int       // Index of the next word in lastHash
+}
+
+// CalcHash256PRNGIV returns the initial state of a Hash256PRNG for seed.
+// The lottery seeds the PRNG with the serialized block header.
+func CalcHash256PRNGIV(seed []byte) wire.Hash {
+	buf := make([]byte, len(seed)+len(seedConst))
+	copy(buf, seed)
+	copy(buf[len(seed):], seedConst[:])
+	return wire.HashH(buf)
+}
+
+// LotteryIV returns the initial state of the PRNG that selects the winners
+// voting on the child of the block with the given header.
+func LotteryIV(header *wire.BlockHeader) wire.Hash {
+	return CalcHash256PRNGIV(header.Bytes())
+}
+
+// NewHash256PRNGFromIV returns a Hash256PRNG with the given initial state,
+// as computed by CalcHash256PRNGIV.
+func NewHash256PRNGFromIV(iv wire.Hash) *Hash256PRNG {
+	return &Hash256PRNG{seed: iv, lastHash: iv}
+}
+
+// NewHash256PRNG returns a Hash256PRNG for seed.
+func NewHash256PRNG(seed []byte) *Hash256PRNG {
+	return NewHash256PRNGFromIV(CalcHash256PRNGIV(seed))
+}
+
+// StateHash returns a hash of the current state of the PRNG. The first six
+// bytes are the FinalState committed to in block headers.
+func (p *Hash256PRNG) StateHash() wire.Hash {
+	var buf [wire.HashSize + 4 + 1]byte
+	copy(buf[:], p.lastHash[:])
+	binary.BigEndian.PutUint32(buf[wire.HashSize:], uint32(p.idx))
+	buf[wire.HashSize+4] = byte(p.hashIdx)
+	return wire.HashH(buf[:])
+}
+
+// FinalState returns the first six bytes of StateHash.
+func (p *Hash256PRNG) FinalState() [6]byte {
+	var fs [6]byte
+	h := p.StateHash()
+	copy(fs[:], h[:])
+	return fs
+}
+
+// Hash256Rand returns the next 32-bit value of the PRNG.
+func (p *Hash256PRNG) Hash256Rand() uint32 {
+	offset := p.hashIdx * 4
+	r := binary.BigEndian.Uint32(p.lastHash[offset : offset+4])
+	p.hashIdx++
+
+	// Move on to the next hash once all eight words are used.
+	if p.hashIdx > 7 {
+		var buf [wire.HashSize + 4]byte
+		copy(buf[:], p.seed[:])
+		binary.BigEndian.PutUint32(buf[wire.HashSize:], uint32(p.idx))
+		p.lastHash = wire.HashH(buf[:])
+		p.idx++
+		p.hashIdx = 0
+	}
+
+	// Rehash the seed when the counter overflows.
+	if p.idx > 0xFFFFFFFF {
+		p.seed = wire.HashH(p.seed[:])
+		p.lastHash = p.seed
+		p.idx = 0
+	}
+
+	return r
+}
+
+// uniformRandom returns a value uniformly distributed in [0, upperBound),
+// discarding draws that would bias the result towards small values. It
+// mirrors arc4random_uniform.
+func (p *Hash256PRNG) uniformRandom(upperBound uint32) uint32 {
+	if upperBound < 2 {
+		return 0
+	}
+
+	// min is 2**32 % upperBound, computed without 64-bit arithmetic.
+	var min uint32
+	if upperBound > 0x80000000 {
+		min = 1 + ^upperBound
+	} else {
+		// (2**32 - (x * 2)) % x == 2**32 % x when x <= 2**31
+		min = ((0xFFFFFFFF - (upperBound * 2)) + 1) % upperBound
+	}
+
+	for {
+		r := p.Hash256Rand()
+		if r >= min {
+			return r % upperBound
+		}
+	}
+}
+
+// FindTicketIdxs draws n distinct indexes into a ticket pool of the given
+// size, in the order they are drawn. Indexes already drawn are skipped.
+func FindTicketIdxs(size int, n uint16, prng *Hash256PRNG) ([]int, error) {
+	if size < int(n) {
+		return nil, fmt.Errorf("%w: have %d, need %d", ErrNotEnoughTickets,
+			size, n)
+	}
+	if int64(size) > 0xFFFFFFFF {
+		return nil, fmt.Errorf("%w: %d", ErrTooManyTickets, size)
+	}
+
+	list := make([]int, 0, n)
+	for len(list) < int(n) {
+		r := int(prng.uniformRandom(uint32(size)))
+		if !containsInt(list, r) {
+			list = append(list, r)
+		}
+	}
+	return list, nil
+}
+
+// containsInt reports whether v is in s.
+func containsInt(s []int, v int) bool {
+	for _, x := range s {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+// SortTickets sorts ticket hashes into lottery order, ascending by their
+// serialized bytes. Note this is not the order of their display strings,
+// which are byte-reversed.
+func SortTickets(tickets []wire.Hash) {
+	sort.Slice(tickets, func(i, j int) bool {
+		return bytes.Compare(tickets[i][:], tickets[j][:]) < 0
+	})
+}
+
+// FindWinners returns the n tickets selected by prng from the live ticket
+// pool, in the order they were drawn. The pool must be sorted with
+// SortTickets and contain no duplicates.
+func FindWinners(tickets []wire.Hash, n uint16, prng *Hash256PRNG) ([]wire.Hash, error) {
+	for i := 1; i < len(tickets); i++ {
+		if bytes.Compare(tickets[i-1][:], tickets[i][:]) >= 0 {
+			return nil, fmt.Errorf("%w: index %d", ErrTicketsNotSorted, i)
+		}
+	}
+	idxs, err := FindTicketIdxs(len(tickets), n, prng)
+	if err != nil {
+		return nil, err
+	}
+	winners := make([]wire.Hash, len(idxs))
+	for i, idx := range idxs {
+		winners[i] = tickets[idx]
+	}
+	return winners, nil
+}
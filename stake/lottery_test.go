@@ -0,0 +1,180 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package stake
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/rickiey/blake256/wire"
+)
+
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
+
+func TestHash256PRNG(t *testing.T) {
+	iv := CalcHash256PRNGIV(nil)
+	wantIV := "f76b50a66e759ab44933a434a89eb9e739a7d69811d58623d0f088bb0667021d"
+	if got := hex.EncodeToString(iv[:]); got != wantIV {
+		t.Fatalf("expected %s, got %s", wantIV, got)
+	}
+
+	want := []uint32{0xf76b50a6, 0x6e759ab4, 0x4933a434, 0xa89eb9e7,
+		0x39a7d698, 0x11d58623, 0xd0f088bb, 0x0667021d, 0x009efd87,
+		0xf7905535}
+	p := NewHash256PRNG(nil)
+	for i, w := range want {
+		if got := p.Hash256Rand(); got != w {
+			t.Fatalf("%d: expected %#x, got %#x", i, w, got)
+		}
+	}
+	wantState := "0c107cfe548030989c475d1926df9487f2bafa160ab41b78c092d8a248e439d8"
+	if got := p.StateHash(); hex.EncodeToString(got[:]) != wantState {
+		t.Fatalf("expected %s, got %x", wantState, got[:])
+	}
+}
+
+func TestHash256PRNGSeedRehash(t *testing.T) {
+	iv := CalcHash256PRNGIV([]byte("decred"))
+	p := NewHash256PRNGFromIV(iv)
+	p.idx = 0xFFFFFFFF
+	p.hashIdx = 7
+	p.Hash256Rand()
+
+	reseeded := wire.HashH(iv[:])
+	if p.seed != reseeded || p.lastHash != reseeded || p.idx != 0 ||
+		p.hashIdx != 0 {
+		t.Fatalf("PRNG state not rehashed after counter overflow")
+	}
+	want := binary.BigEndian.Uint32(reseeded[:4])
+	if got := p.Hash256Rand(); got != want {
+		t.Fatalf("expected %#x, got %#x", want, got)
+	}
+}
+
+func TestFindTicketIdxs(t *testing.T) {
+	type test struct {
+		size  int
+		n     uint16
+		idxs  []int
+		state string
+	}
+	tests := []test{{
+		size:  100,
+		n:     5,
+		idxs:  []int{44, 3, 66, 75, 20},
+		state: "b458fa7a7768a3dccb257fd905d575ee9659d6a98b8568a997b2494f631cf716",
+	}}
+	// A pool just above 2^31 exercises the rejection path of uniformRandom.
+	// It only fits in an int on 64-bit platforms.
+	if strconv.IntSize == 64 {
+		var size uint64 = 0x80000001
+		tests = append(tests, test{
+			size:  int(size),
+			n:     5,
+			idxs:  []int{1863019695, 881782026, 1127172971, 757735636, 649736092},
+			state: "ae2f7eb0398cb76a017d83739995a7c4322f459684efdc7e4b276c0dd8554541",
+		})
+	}
+	for i, test := range tests {
+		p := NewHash256PRNG([]byte("decred"))
+		idxs, err := FindTicketIdxs(test.size, test.n, p)
+		if err != nil {
+			t.Fatalf("%d: %v", i, err)
+		}
+		if !reflect.DeepEqual(idxs, test.idxs) {
+			t.Errorf("%d: expected %v, got %v", i, test.idxs, idxs)
+		}
+		if got := p.StateHash(); hex.EncodeToString(got[:]) != test.state {
+			t.Errorf("%d: expected state %s, got %x", i, test.state, got[:])
+		}
+	}
+
+	// Drawing nothing from an empty pool succeeds without touching the PRNG.
+	p := NewHash256PRNG(nil)
+	state := p.StateHash()
+	idxs, err := FindTicketIdxs(0, 0, p)
+	if err != nil || len(idxs) != 0 {
+		t.Errorf("expected no indexes, got %v, %v", idxs, err)
+	}
+	if got := p.StateHash(); got != state {
+		t.Errorf("expected state %v, got %v", state, got)
+	}
+
+	if _, err := FindTicketIdxs(4, 5, p); !errors.Is(err, ErrNotEnoughTickets) {
+		t.Errorf("expected %v, got %v", ErrNotEnoughTickets, err)
+	}
+	if strconv.IntSize == 64 {
+		var size uint64 = 1 << 32
+		if _, err := FindTicketIdxs(int(size), 5, p); !errors.Is(err, ErrTooManyTickets) {
+			t.Errorf("expected %v, got %v", ErrTooManyTickets, err)
+		}
+	}
+}
+
+func TestFindWinners(t *testing.T) {
+	merkle, err := wire.NewHashFromStr("66aa7491b9adce110585ccab7e3fb5fe280de174530cca10eba2c6c3df01c10d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis := &wire.BlockHeader{
+		Version:    1,
+		MerkleRoot: *merkle,
+		Bits:       0x1b01ffff,
+		SBits:      2 * 1e8,
+		Timestamp:  time.Unix(1454954400, 0),
+	}
+
+	tickets := make([]wire.Hash, 50)
+	for i := range tickets {
+		var b [4]byte
+		binary.LittleEndian.PutUint32(b[:], uint32(i))
+		tickets[i] = wire.HashH(b[:])
+	}
+	SortTickets(tickets)
+
+	p := NewHash256PRNGFromIV(LotteryIV(genesis))
+	winners, err := FindWinners(tickets, 5, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"7da68d80280a9b162dfaa8cc8e71e255d7283e380b020578abed8d301f5d99f3",
+		"9ab362a6532019a46c14dbc3de29d7baf044142422f2cfec470b0e5a3610b61c",
+		"846170e50e181723cb79c613a37299f447bfcf138a3b4732594303139e855846",
+		"f9425287d8eafc46595bcde14d5b77774fc519f74f7ec524d56ddf6de45b5655",
+		"c423f76ccada17bf1b1c18d2a43e2f6c2979b91473b34d6582fb33e923ef022a",
+	}
+	for i, w := range winners {
+		if got := w.String(); got != want[i] {
+			t.Errorf("%d: expected %s, got %s", i, want[i], got)
+		}
+	}
+	wantFinal := hexToBytes("162bda351646")
+	if got := p.FinalState(); string(got[:]) != string(wantFinal) {
+		t.Errorf("expected final state %x, got %x", wantFinal, got)
+	}
+
+	tickets[0], tickets[1] = tickets[1], tickets[0]
+	_, err = FindWinners(tickets, 5, NewHash256PRNG(nil))
+	if !errors.Is(err, ErrTicketsNotSorted) {
+		t.Errorf("expected %v, got %v", ErrTicketsNotSorted, err)
+	}
+	tickets[0] = tickets[1]
+	_, err = FindWinners(tickets, 5, NewHash256PRNG(nil))
+	if !errors.Is(err, ErrTicketsNotSorted) {
+		t.Errorf("expected %v, got %v", ErrTicketsNotSorted, err)
+	}
+}
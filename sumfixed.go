@@ -0,0 +1,77 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+// HeaderSize is the size in bytes of a serialized Decred block header, the
+// input of Sum256Header180.
+const HeaderSize = 180
+
+// The fixed-length fast paths load the message words of each block straight
+// from the input and set the padding and the message bit length as constant
+// words, so nothing is buffered or padded byte by byte. Like block, they pass
+// compress the number of message bits up to and including each block, or
+// zero for a final block without message bits.
+
+// loadWords sets m to the big-endian words of p.
+func loadWords(m []uint32, p []byte) {
+	p = p[:4*len(m)]
+	for i := range m {
+		m[i] = uint32(p[4*i])<<24 | uint32(p[4*i+1])<<16 |
+			uint32(p[4*i+2])<<8 | uint32(p[4*i+3])
+	}
+}
+
+// Sum256x32 returns the BLAKE-256 checksum of the 32-byte data, such as when
+// rehashing a previous checksum. It is equivalent to Sum256(data[:]) but
+// compresses a single block built from the data and constant padding.
+func Sum256x32(data *[32]byte) [Size]byte {
+	h, s := iv256, [4]uint32{}
+	var m [16]uint32
+	loadWords(m[:8], data[:])
+	m[8] = 0x80000000
+	m[13] = 0x00000001
+	m[15] = 32 << 3
+	compress(&h, &s, 32<<3, &m)
+	return sum256(&h)
+}
+
+// Sum256x64 returns the BLAKE-256 checksum of the 64-byte data, such as the
+// concatenation of two child hashes in a Merkle tree. It is equivalent to
+// Sum256(data[:]) but compresses the data followed by a constant final block.
+func Sum256x64(data *[64]byte) [Size]byte {
+	h, s := iv256, [4]uint32{}
+	var m [16]uint32
+	loadWords(m[:], data[:])
+	compress(&h, &s, 64<<3, &m)
+	m = [16]uint32{0: 0x80000000, 13: 0x00000001, 15: 64 << 3}
+	compress(&h, &s, 0, &m)
+	return sum256(&h)
+}
+
+// Sum256Header180 returns the BLAKE-256 checksum of a serialized 180-byte
+// block header. It is equivalent to Sum256(header[:]) but compresses the
+// header in place, with the padding of its final block as constant words.
+func Sum256Header180(header *[HeaderSize]byte) [Size]byte {
+	h := iv256
+	compressHeaderMidstate(&h, header)
+	var s [4]uint32
+	var m [16]uint32
+	loadWords(m[:13], header[HeaderMidstateSize:])
+	m[13] = 0x80000001
+	m[15] = HeaderSize << 3
+	compress(&h, &s, HeaderSize<<3, &m)
+	return sum256(&h)
+}
+
+// compressHeaderMidstate compresses the first HeaderMidstateSize bytes of a
+// block header into the chain value h.
+func compressHeaderMidstate(h *[8]uint32, header *[HeaderSize]byte) {
+	var s [4]uint32
+	var m [16]uint32
+	loadWords(m[:], header[:BlockSize])
+	compress(h, &s, BlockSize<<3, &m)
+	loadWords(m[:], header[BlockSize:HeaderMidstateSize])
+	compress(h, &s, HeaderMidstateSize<<3, &m)
+}
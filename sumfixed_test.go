@@ -0,0 +1,80 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSumFixed(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 100; i++ {
+		var d32 [32]byte
+		var d64 [64]byte
+		var d180 [HeaderSize]byte
+		rng.Read(d32[:])
+		rng.Read(d64[:])
+		rng.Read(d180[:])
+
+		if got, want := Sum256x32(&d32), Sum256(d32[:]); got != want {
+			t.Fatalf("Sum256x32(%x): expected %x, got %x", d32, want, got)
+		}
+		if got, want := Sum256x64(&d64), Sum256(d64[:]); got != want {
+			t.Fatalf("Sum256x64(%x): expected %x, got %x", d64, want, got)
+		}
+		if got, want := Sum256Header180(&d180), Sum256(d180[:]); got != want {
+			t.Fatalf("Sum256Header180(%x): expected %x, got %x", d180, want, got)
+		}
+	}
+}
+
+func Benchmark32Fixed(b *testing.B) {
+	var data [32]byte
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		_ = Sum256x32(&data)
+	}
+}
+
+func Benchmark32Sum256(b *testing.B) {
+	var data [32]byte
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		_ = Sum256(data[:])
+	}
+}
+
+func Benchmark64Fixed(b *testing.B) {
+	var data [64]byte
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		_ = Sum256x64(&data)
+	}
+}
+
+func Benchmark64Sum256(b *testing.B) {
+	var data [64]byte
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		_ = Sum256(data[:])
+	}
+}
+
+func BenchmarkHeader180Fixed(b *testing.B) {
+	var data [HeaderSize]byte
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		_ = Sum256Header180(&data)
+	}
+}
+
+func BenchmarkHeader180Sum256(b *testing.B) {
+	var data [HeaderSize]byte
+	b.SetBytes(int64(len(data)))
+	for i := 0; i < b.N; i++ {
+		_ = Sum256(data[:])
+	}
+}
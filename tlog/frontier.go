@@ -0,0 +1,94 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tlog
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+// Frontier is the compact state of a log needed to append leaves and compute
+// its root: the roots of the complete subtrees covering its leaves, one for
+// each set bit of the size, largest first. It holds at most 64 hashes and
+// can be saved with MarshalBinary between appends.
+type Frontier struct {
+	size   uint64
+	hashes []Hash
+}
+
+// Size returns the number of leaves in the log.
+func (f *Frontier) Size() uint64 {
+	return f.size
+}
+
+// Append adds a leaf hash to the log.
+func (f *Frontier) Append(leafHash *Hash) {
+	h := *leafHash
+	// Merge with each complete subtree of the same size, smallest first.
+	for s := f.size; s&1 == 1; s >>= 1 {
+		last := len(f.hashes) - 1
+		h = NodeHash(&f.hashes[last], &h)
+		f.hashes = f.hashes[:last]
+	}
+	f.hashes = append(f.hashes, h)
+	f.size++
+}
+
+// AppendData adds a leaf with the given data to the log and returns its
+// hash.
+func (f *Frontier) AppendData(data []byte) Hash {
+	h := LeafHash(data)
+	f.Append(&h)
+	return h
+}
+
+// Root returns the root of the log.
+func (f *Frontier) Root() Hash {
+	if len(f.hashes) == 0 {
+		return EmptyRoot()
+	}
+	r := f.hashes[len(f.hashes)-1]
+	for i := len(f.hashes) - 2; i >= 0; i-- {
+		r = NodeHash(&f.hashes[i], &r)
+	}
+	return r
+}
+
+const (
+	frontierMagic     = "tlf\x01"
+	frontierHeaderLen = len(frontierMagic) + 8
+)
+
+// MarshalBinary returns the serialized frontier: a magic string, the size as
+// a big-endian uint64 and the subtree roots.
+func (f *Frontier) MarshalBinary() ([]byte, error) {
+	b := make([]byte, frontierHeaderLen, frontierHeaderLen+len(f.hashes)*HashSize)
+	copy(b, frontierMagic)
+	binary.BigEndian.PutUint64(b[len(frontierMagic):], f.size)
+	for i := range f.hashes {
+		b = append(b, f.hashes[i][:]...)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary restores a frontier returned by MarshalBinary.
+func (f *Frontier) UnmarshalBinary(b []byte) error {
+	if len(b) < frontierHeaderLen || string(b[:len(frontierMagic)]) != frontierMagic {
+		return errors.New("tlog: invalid frontier identifier")
+	}
+	size := binary.BigEndian.Uint64(b[len(frontierMagic):])
+	b = b[frontierHeaderLen:]
+	if len(b) != bits.OnesCount64(size)*HashSize {
+		return errors.New("tlog: invalid frontier size")
+	}
+	hashes := make([]Hash, len(b)/HashSize)
+	for i := range hashes {
+		copy(hashes[i][:], b[i*HashSize:])
+	}
+	f.size = size
+	f.hashes = hashes
+	return nil
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tlog
+
+import (
+	"math/bits"
+	"testing"
+)
+
+func TestFrontier(t *testing.T) {
+	leaves := testLeaves(100)
+	var f Frontier
+	if f.Root() != EmptyRoot() {
+		t.Fatalf("expected empty root, got %v", f.Root())
+	}
+	for i := range leaves {
+		f.Append(&leaves[i])
+		n := uint64(i + 1)
+		if f.Size() != n {
+			t.Fatalf("expected size %d, got %d", n, f.Size())
+		}
+		if len(f.hashes) != bits.OnesCount64(n) {
+			t.Fatalf("%d: expected %d hashes, got %d", n, bits.OnesCount64(n),
+				len(f.hashes))
+		}
+		if got, want := f.Root(), RootHash(leaves[:n]); got != want {
+			t.Fatalf("%d: expected %v, got %v", n, want, got)
+		}
+	}
+
+	var g Frontier
+	if h := g.AppendData([]byte("leaf 0")); h != leaves[0] {
+		t.Fatalf("expected %v, got %v", leaves[0], h)
+	}
+}
+
+func TestFrontierMarshal(t *testing.T) {
+	leaves := testLeaves(45)
+	var f Frontier
+	for i := range leaves[:21] {
+		f.Append(&leaves[i])
+	}
+	b, err := f.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := frontierHeaderLen + 3*HashSize; len(b) != want {
+		t.Fatalf("expected %d bytes, got %d", want, len(b))
+	}
+
+	// A restored frontier continues the log.
+	var g Frontier
+	if err := g.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	for i := range leaves[21:] {
+		f.Append(&leaves[21+i])
+		g.Append(&leaves[21+i])
+	}
+	if f.Root() != g.Root() || g.Root() != RootHash(leaves) {
+		t.Fatalf("restored frontier diverged")
+	}
+
+	var empty Frontier
+	b, err = empty.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := g.UnmarshalBinary(b); err != nil || g.Size() != 0 || g.Root() != EmptyRoot() {
+		t.Fatalf("empty frontier not restored: %v", err)
+	}
+
+	b, _ = f.MarshalBinary()
+	bad := [][]byte{
+		nil,
+		b[:frontierHeaderLen-1],
+		append([]byte("xxxx"), b[4:]...),
+		b[:len(b)-1],
+		append(append([]byte{}, b...), make([]byte, HashSize)...),
+	}
+	for i, data := range bad {
+		if err := g.UnmarshalBinary(data); err == nil {
+			t.Errorf("%d: expected error", i)
+		}
+	}
+}
+
+func BenchmarkFrontierAppend(b *testing.B) {
+	var f Frontier
+	leaf := LeafHash([]byte("leaf"))
+	for i := 0; i < b.N; i++ {
+		f.Append(&leaf)
+	}
+}
@@ -0,0 +1,245 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package tlog implements an append-only verifiable log in the style of
+// RFC 9162 (Certificate Transparency 2.0) with BLAKE-256 as the hash.
+//
+// Unlike Decred's block Merkle trees, which pair an odd last node with
+// itself, the tree over n leaves splits at the largest power of two below n,
+// so a tree is never ambiguous and every smaller tree is a prefix of every
+// larger one. Leaf and interior hashes are domain separated with a 0x00 and
+// 0x01 prefix:
+//
+//	MTH({})       = BLAKE-256()
+//	MTH({d0})     = BLAKE-256(0x00 || d0)
+//	MTH(D[n])     = BLAKE-256(0x01 || MTH(D[0:k]) || MTH(D[k:n]))
+//
+// Inclusion proofs show that a leaf is in a tree of a given size and
+// consistency proofs show that a tree is an extension of an earlier one. A
+// Frontier holds just enough of a tree to append leaves and compute its root.
+package tlog
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+
+	"github.com/rickiey/blake256"
+)
+
+// HashSize is the size of a log hash in bytes.
+const HashSize = blake256.Size
+
+// Hash is a leaf or interior node hash.
+type Hash [HashSize]byte
+
+// String returns the hash in hex.
+func (h Hash) String() string {
+	return hex.EncodeToString(h[:])
+}
+
+var (
+	// ErrInvalidIndex is returned when a leaf index is not in the tree.
+	ErrInvalidIndex = errors.New("tlog: leaf index out of range")
+
+	// ErrInvalidSize is returned when tree sizes are inconsistent.
+	ErrInvalidSize = errors.New("tlog: invalid tree size")
+
+	// ErrInvalidProof is returned when a proof does not verify.
+	ErrInvalidProof = errors.New("tlog: invalid proof")
+)
+
+// Prefixes separating leaf hashes from interior node hashes.
+const (
+	leafPrefix = 0x00
+	nodePrefix = 0x01
+)
+
+// EmptyRoot returns the root of the empty tree, BLAKE-256 of no data.
+func EmptyRoot() Hash {
+	return blake256.Sum256(nil)
+}
+
+// LeafHash returns the hash of a leaf with the given data.
+func LeafHash(data []byte) Hash {
+	d := blake256.Acquire()
+	defer blake256.Release(d)
+	d.Write([]byte{leafPrefix})
+	d.Write(data)
+	var h Hash
+	d.Sum(h[:0])
+	return h
+}
+
+// NodeHash returns the hash of an interior node with the given children.
+func NodeHash(left, right *Hash) Hash {
+	var buf [1 + 2*HashSize]byte
+	buf[0] = nodePrefix
+	copy(buf[1:], left[:])
+	copy(buf[1+HashSize:], right[:])
+	return blake256.Sum256(buf[:])
+}
+
+// splitPoint returns the largest power of two less than n, for n > 1.
+func splitPoint(n uint64) uint64 {
+	return 1 << (bits.Len64(n-1) - 1)
+}
+
+// RootHash returns the root of the tree over the given leaf hashes.
+func RootHash(leaves []Hash) Hash {
+	switch len(leaves) {
+	case 0:
+		return EmptyRoot()
+	case 1:
+		return leaves[0]
+	}
+	k := splitPoint(uint64(len(leaves)))
+	left, right := RootHash(leaves[:k]), RootHash(leaves[k:])
+	return NodeHash(&left, &right)
+}
+
+// InclusionProof returns the audit path of the leaf at index in the tree
+// over leaves. Pass a prefix of the leaves for a proof against an earlier
+// tree size.
+func InclusionProof(leaves []Hash, index uint64) ([]Hash, error) {
+	if index >= uint64(len(leaves)) {
+		return nil, fmt.Errorf("%w: %d in tree of size %d", ErrInvalidIndex,
+			index, len(leaves))
+	}
+	return path(index, leaves), nil
+}
+
+// path returns the audit path PATH(m, D[n]) of RFC 9162 with the hashes
+// nearest the leaf first.
+func path(m uint64, leaves []Hash) []Hash {
+	if len(leaves) <= 1 {
+		return nil
+	}
+	k := splitPoint(uint64(len(leaves)))
+	if m < k {
+		return append(path(m, leaves[:k]), RootHash(leaves[k:]))
+	}
+	return append(path(m-k, leaves[k:]), RootHash(leaves[:k]))
+}
+
+// ConsistencyProof returns the proof that the tree over leaves extends the
+// tree of its first oldSize leaves. The proof between equal sizes, or from
+// the empty tree, is empty.
+func ConsistencyProof(leaves []Hash, oldSize uint64) ([]Hash, error) {
+	if oldSize > uint64(len(leaves)) {
+		return nil, fmt.Errorf("%w: old size %d exceeds %d", ErrInvalidSize,
+			oldSize, len(leaves))
+	}
+	if oldSize == 0 {
+		return nil, nil
+	}
+	return subproof(oldSize, leaves, true), nil
+}
+
+// subproof returns SUBPROOF(m, D[n], b) of RFC 9162.
+func subproof(m uint64, leaves []Hash, complete bool) []Hash {
+	n := uint64(len(leaves))
+	if m == n {
+		if complete {
+			return nil
+		}
+		return []Hash{RootHash(leaves)}
+	}
+	k := splitPoint(n)
+	if m <= k {
+		return append(subproof(m, leaves[:k], complete), RootHash(leaves[k:]))
+	}
+	return append(subproof(m-k, leaves[k:], false), RootHash(leaves[:k]))
+}
+
+// VerifyInclusion checks that proof shows leafHash is at index in the tree
+// of the given size and root.
+func VerifyInclusion(index, size uint64, leafHash *Hash, proof []Hash, root *Hash) error {
+	if index >= size {
+		return fmt.Errorf("%w: %d in tree of size %d", ErrInvalidIndex, index,
+			size)
+	}
+	fn, sn := index, size-1
+	r := *leafHash
+	for i := range proof {
+		if sn == 0 {
+			return fmt.Errorf("%w: proof too long", ErrInvalidProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			r = NodeHash(&proof[i], &r)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = NodeHash(&r, &proof[i])
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return fmt.Errorf("%w: proof too short", ErrInvalidProof)
+	}
+	if r != *root {
+		return fmt.Errorf("%w: root mismatch", ErrInvalidProof)
+	}
+	return nil
+}
+
+// VerifyConsistency checks that proof shows the tree of newSize leaves and
+// root newRoot extends the tree of oldSize leaves and root oldRoot.
+func VerifyConsistency(oldSize, newSize uint64, oldRoot, newRoot *Hash, proof []Hash) error {
+	switch {
+	case oldSize > newSize:
+		return fmt.Errorf("%w: old size %d exceeds new size %d", ErrInvalidSize,
+			oldSize, newSize)
+	case oldSize == 0 || oldSize == newSize:
+		if len(proof) != 0 {
+			return fmt.Errorf("%w: expected empty proof", ErrInvalidProof)
+		}
+		if oldSize == newSize && *oldRoot != *newRoot {
+			return fmt.Errorf("%w: root mismatch", ErrInvalidProof)
+		}
+		return nil
+	case len(proof) == 0:
+		return fmt.Errorf("%w: empty proof", ErrInvalidProof)
+	}
+
+	// When the old tree is a complete subtree its root starts the path.
+	if oldSize&(oldSize-1) == 0 {
+		proof = append([]Hash{*oldRoot}, proof...)
+	}
+	fn, sn := oldSize-1, newSize-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+	fr, sr := proof[0], proof[0]
+	for i := 1; i < len(proof); i++ {
+		c := &proof[i]
+		if sn == 0 {
+			return fmt.Errorf("%w: proof too long", ErrInvalidProof)
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = NodeHash(c, &fr)
+			sr = NodeHash(c, &sr)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = NodeHash(&sr, c)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	if sn != 0 {
+		return fmt.Errorf("%w: proof too short", ErrInvalidProof)
+	}
+	if fr != *oldRoot || sr != *newRoot {
+		return fmt.Errorf("%w: root mismatch", ErrInvalidProof)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tlog
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
+
+// rfcLeaves are the leaf inputs of the RFC 6962 reference tests.
+var rfcLeaves = []string{
+	"", "00", "10", "2021", "3031", "40414243", "5051525354555657",
+	"606162636465666768696a6b6c6d6e6f",
+}
+
+// testLeaves returns the leaf hashes of n distinct leaves.
+func testLeaves(n int) []Hash {
+	leaves := make([]Hash, n)
+	for i := range leaves {
+		leaves[i] = LeafHash([]byte(fmt.Sprintf("leaf %d", i)))
+	}
+	return leaves
+}
+
+func TestRootHash(t *testing.T) {
+	roots := []string{
+		"716f6e863f744b9ac22c97ec7b76ea5f5908bc5b2f67c61510bfc4751384ea7a",
+		"0ce8d4ef4dd7cd8d62dfded9d4edb0a774ae6a41929a74da23109e8f11139c87",
+		"5926ccef94e4d6fdc7d873000c7101d9e4bbc2161b837a82fec9b878af735973",
+		"02367b404e66e47262bd7260269f336409a842404a05fde7d7ac4810fcd8ed51",
+		"15668aaf384456c5d7552a7832566be13956ca1878358bbe6d9fe3f6e464695a",
+		"1b6d078f4fd798538c58f62ed93f5520a40a8fce93c8f998356a1fa0ad0513e9",
+		"69d604a64deb02d1efb1ceefd92e889b096f19db443b912e78f4b88a8024e01d",
+		"0e715484ba3ff75829c7c749cdfa88691a1ac64181f418a374ef416325b12091",
+		"97e2e0a191c9f1b87975624a3721f221e93439c0c291cb6a169b8224d971f627",
+	}
+	var leaves []Hash
+	for n, want := range roots {
+		if n > 0 {
+			leaves = append(leaves, LeafHash(hexToBytes(rfcLeaves[n-1])))
+		}
+		if got := RootHash(leaves).String(); got != want {
+			t.Errorf("%d: expected %s, got %s", n, want, got)
+		}
+	}
+}
+
+// Proofs are not checked against sizes other than their own: the root hash
+// does not commit to the tree size, so trees of the same shape accept the same
+// proof. Logs sign the size along with the root.
+func TestInclusionProofs(t *testing.T) {
+	for size := 1; size <= 33; size++ {
+		leaves := testLeaves(size)
+		root := RootHash(leaves)
+		for i := 0; i < size; i++ {
+			idx := uint64(i)
+			proof, err := InclusionProof(leaves, idx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := VerifyInclusion(idx, uint64(size), &leaves[i], proof, &root); err != nil {
+				t.Fatalf("%d/%d: %v", i, size, err)
+			}
+
+			// Wrong leaves, positions, sizes and proofs are rejected.
+			other := LeafHash([]byte("other"))
+			if VerifyInclusion(idx, uint64(size), &other, proof, &root) == nil {
+				t.Fatalf("%d/%d: wrong leaf verified", i, size)
+			}
+			if size > 1 && VerifyInclusion((idx+1)%uint64(size), uint64(size),
+				&leaves[i], proof, &root) == nil {
+				t.Fatalf("%d/%d: wrong index verified", i, size)
+			}
+			if len(proof) > 0 {
+				bad := append([]Hash(nil), proof...)
+				bad[0][0] ^= 1
+				if VerifyInclusion(idx, uint64(size), &leaves[i], bad, &root) == nil {
+					t.Fatalf("%d/%d: tampered proof verified", i, size)
+				}
+				if VerifyInclusion(idx, uint64(size), &leaves[i], proof[1:], &root) == nil {
+					t.Fatalf("%d/%d: short proof verified", i, size)
+				}
+			}
+			long := append(append([]Hash(nil), proof...), root)
+			if VerifyInclusion(idx, uint64(size), &leaves[i], long, &root) == nil {
+				t.Fatalf("%d/%d: long proof verified", i, size)
+			}
+		}
+		if _, err := InclusionProof(leaves, uint64(size)); !errors.Is(err, ErrInvalidIndex) {
+			t.Fatalf("%d: expected %v, got %v", size, ErrInvalidIndex, err)
+		}
+		err := VerifyInclusion(uint64(size), uint64(size), &leaves[0], nil, &root)
+		if !errors.Is(err, ErrInvalidIndex) {
+			t.Fatalf("%d: expected %v, got %v", size, ErrInvalidIndex, err)
+		}
+	}
+
+	// A leaf cannot pose as an interior node: the leaf hash of the
+	// concatenated children differs from their node hash.
+	leaves := testLeaves(2)
+	root := RootHash(leaves)
+	forged := LeafHash(append(append([]byte{}, leaves[0][:]...), leaves[1][:]...))
+	if VerifyInclusion(0, 1, &forged, nil, &root) == nil {
+		t.Fatal("second preimage accepted")
+	}
+}
+
+func TestConsistencyProofs(t *testing.T) {
+	const maxSize = 33
+	leaves := testLeaves(maxSize)
+	roots := make([]Hash, maxSize+1)
+	for n := range roots {
+		roots[n] = RootHash(leaves[:n])
+	}
+	for newSize := uint64(0); newSize <= maxSize; newSize++ {
+		for oldSize := uint64(0); oldSize <= newSize; oldSize++ {
+			proof, err := ConsistencyProof(leaves[:newSize], oldSize)
+			if err != nil {
+				t.Fatal(err)
+			}
+			err = VerifyConsistency(oldSize, newSize, &roots[oldSize], &roots[newSize], proof)
+			if err != nil {
+				t.Fatalf("%d->%d: %v", oldSize, newSize, err)
+			}
+			if oldSize == 0 || oldSize == newSize {
+				if len(proof) != 0 {
+					t.Fatalf("%d->%d: expected empty proof", oldSize, newSize)
+				}
+				continue
+			}
+
+			// Wrong roots, sizes and proofs are rejected.
+			other := roots[oldSize]
+			other[0] ^= 1
+			if VerifyConsistency(oldSize, newSize, &other, &roots[newSize], proof) == nil {
+				t.Fatalf("%d->%d: wrong old root verified", oldSize, newSize)
+			}
+			if VerifyConsistency(oldSize, newSize, &roots[oldSize], &other, proof) == nil {
+				t.Fatalf("%d->%d: wrong new root verified", oldSize, newSize)
+			}
+			bad := append([]Hash(nil), proof...)
+			bad[len(bad)-1][0] ^= 1
+			if VerifyConsistency(oldSize, newSize, &roots[oldSize], &roots[newSize], bad) == nil {
+				t.Fatalf("%d->%d: tampered proof verified", oldSize, newSize)
+			}
+			if VerifyConsistency(oldSize, newSize, &roots[oldSize], &roots[newSize],
+				proof[:len(proof)-1]) == nil {
+				t.Fatalf("%d->%d: short proof verified", oldSize, newSize)
+			}
+		}
+	}
+
+	if _, err := ConsistencyProof(leaves[:3], 4); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("expected %v, got %v", ErrInvalidSize, err)
+	}
+	if err := VerifyConsistency(4, 3, &roots[4], &roots[3], nil); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("expected %v, got %v", ErrInvalidSize, err)
+	}
+	if err := VerifyConsistency(3, 3, &roots[3], &roots[4], nil); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("expected %v, got %v", ErrInvalidProof, err)
+	}
+	if err := VerifyConsistency(3, 4, &roots[3], &roots[4], nil); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("expected %v, got %v", ErrInvalidProof, err)
+	}
+	if err := VerifyConsistency(0, 4, &roots[0], &roots[4], roots[:1]); !errors.Is(err, ErrInvalidProof) {
+		t.Errorf("expected %v, got %v", ErrInvalidProof, err)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tlog
+
+import "fmt"
+
+// Tree is an in-memory log that keeps every leaf hash so it can serve
+// inclusion and consistency proofs for any of its sizes. It is not safe for
+// concurrent use.
+type Tree struct {
+	leaves   []Hash
+	frontier Frontier
+}
+
+// Size returns the number of leaves in the tree.
+func (t *Tree) Size() uint64 {
+	return uint64(len(t.leaves))
+}
+
+// Append adds a leaf with the given data and returns its index.
+func (t *Tree) Append(data []byte) uint64 {
+	h := t.frontier.AppendData(data)
+	t.leaves = append(t.leaves, h)
+	return uint64(len(t.leaves) - 1)
+}
+
+// Leaf returns the hash of the leaf at index.
+func (t *Tree) Leaf(index uint64) (Hash, error) {
+	if index >= t.Size() {
+		return Hash{}, fmt.Errorf("%w: %d in tree of size %d", ErrInvalidIndex,
+			index, t.Size())
+	}
+	return t.leaves[index], nil
+}
+
+// Root returns the root of the current tree.
+func (t *Tree) Root() Hash {
+	return t.frontier.Root()
+}
+
+// RootAt returns the root of the tree when it had size leaves.
+func (t *Tree) RootAt(size uint64) (Hash, error) {
+	if size > t.Size() {
+		return Hash{}, fmt.Errorf("%w: %d exceeds %d", ErrInvalidSize, size,
+			t.Size())
+	}
+	return RootHash(t.leaves[:size]), nil
+}
+
+// InclusionProof returns the proof that the leaf at index is in the tree of
+// the given size.
+func (t *Tree) InclusionProof(index, size uint64) ([]Hash, error) {
+	if size > t.Size() {
+		return nil, fmt.Errorf("%w: %d exceeds %d", ErrInvalidSize, size,
+			t.Size())
+	}
+	return InclusionProof(t.leaves[:size], index)
+}
+
+// ConsistencyProof returns the proof that the tree of newSize leaves extends
+// the tree of oldSize leaves.
+func (t *Tree) ConsistencyProof(oldSize, newSize uint64) ([]Hash, error) {
+	if newSize > t.Size() {
+		return nil, fmt.Errorf("%w: %d exceeds %d", ErrInvalidSize, newSize,
+			t.Size())
+	}
+	return ConsistencyProof(t.leaves[:newSize], oldSize)
+}
+
+// Frontier returns a copy of the frontier of the tree, which can continue
+// the log without the leaf hashes.
+func (t *Tree) Frontier() *Frontier {
+	return &Frontier{
+		size:   t.frontier.size,
+		hashes: append([]Hash(nil), t.frontier.hashes...),
+	}
+}
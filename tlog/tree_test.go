@@ -0,0 +1,75 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package tlog
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestTree(t *testing.T) {
+	var tree Tree
+	for i := 0; i < 20; i++ {
+		if idx := tree.Append([]byte(fmt.Sprintf("leaf %d", i))); idx != uint64(i) {
+			t.Fatalf("expected index %d, got %d", i, idx)
+		}
+	}
+	leaves := testLeaves(20)
+	if got, want := tree.Root(), RootHash(leaves); got != want {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if leaf, err := tree.Leaf(7); err != nil || leaf != leaves[7] {
+		t.Fatalf("expected %v, got %v, %v", leaves[7], leaf, err)
+	}
+
+	// Prove a leaf against an earlier size and that size against now.
+	oldRoot, err := tree.RootAt(13)
+	if err != nil {
+		t.Fatal(err)
+	}
+	proof, err := tree.InclusionProof(7, 13)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := VerifyInclusion(7, 13, &leaves[7], proof, &oldRoot); err != nil {
+		t.Fatal(err)
+	}
+	cproof, err := tree.ConsistencyProof(13, 20)
+	if err != nil {
+		t.Fatal(err)
+	}
+	root := tree.Root()
+	if err := VerifyConsistency(13, 20, &oldRoot, &root, cproof); err != nil {
+		t.Fatal(err)
+	}
+
+	// The frontier is a copy that continues the log independently.
+	f := tree.Frontier()
+	f.AppendData([]byte("leaf 20"))
+	if tree.Size() != 20 || tree.Root() != root {
+		t.Fatalf("tree changed by its frontier")
+	}
+	tree.Append([]byte("leaf 20"))
+	if f.Root() != tree.Root() {
+		t.Fatalf("expected %v, got %v", tree.Root(), f.Root())
+	}
+
+	if _, err := tree.Leaf(21); !errors.Is(err, ErrInvalidIndex) {
+		t.Errorf("expected %v, got %v", ErrInvalidIndex, err)
+	}
+	if _, err := tree.RootAt(22); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("expected %v, got %v", ErrInvalidSize, err)
+	}
+	if _, err := tree.InclusionProof(0, 22); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("expected %v, got %v", ErrInvalidSize, err)
+	}
+	if _, err := tree.InclusionProof(13, 13); !errors.Is(err, ErrInvalidIndex) {
+		t.Errorf("expected %v, got %v", ErrInvalidIndex, err)
+	}
+	if _, err := tree.ConsistencyProof(1, 22); !errors.Is(err, ErrInvalidSize) {
+		t.Errorf("expected %v, got %v", ErrInvalidSize, err)
+	}
+}
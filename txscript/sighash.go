@@ -0,0 +1,168 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package txscript implements the parts of Decred's transaction script
+// system that signing tools and watch-only wallets need: signature hashes and
+// the standard output script templates.
+package txscript
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rickiey/blake256/wire"
+)
+
+// SigHashType selects which parts of a transaction a signature commits to.
+type SigHashType byte
+
+const (
+	SigHashOld          SigHashType = 0x0
+	SigHashAll          SigHashType = 0x1
+	SigHashNone         SigHashType = 0x2
+	SigHashSingle       SigHashType = 0x3
+	SigHashAnyOneCanPay SigHashType = 0x80
+
+	// sigHashMask selects the base type, without the AnyOneCanPay flag.
+	sigHashMask = 0x1f
+)
+
+// The serialization types encoded in the upper 16 bits of the version of the
+// prefix and witness serializations hashed for a signature. The witness type
+// differs from wire.TxSerializeOnlyWitness because only the signature scripts
+// are serialized.
+const (
+	sigHashSerializePrefix  = 1
+	sigHashSerializeWitness = 3
+)
+
+var (
+	// ErrInvalidIndex is returned when the input index to sign is out of
+	// range.
+	ErrInvalidIndex = errors.New("txscript: input index out of range")
+
+	// ErrInvalidSigHashSingleIndex is returned for SigHashSingle when the
+	// input being signed has no output at the same index.
+	ErrInvalidSigHashSingleIndex = errors.New("txscript: no output for SigHashSingle input")
+)
+
+// CalcSignatureHash returns the hash signed by input idx of tx for the given
+// hash type. The script is the one the signature is checked against, usually
+// the public key script of the output being spent, with any code separators
+// already removed.
+//
+// The signature hash is BLAKE-256(hashType || prefixHash || witnessHash),
+// with the hash type as a little-endian uint32. The prefix hash commits to
+// the version, the selected inputs and outputs, the lock time and the expiry,
+// and the witness hash commits to the script at the position of the signed
+// input:
+//
+//   - SigHashAll commits to all outputs. Unknown base types behave the same.
+//   - SigHashNone commits to no outputs, and the sequence of every input
+//     other than the signed one is replaced with 0.
+//   - SigHashSingle commits to the outputs up to the index of the signed
+//     input, with those before it replaced by a value of -1 and an empty
+//     script. Sequences are replaced as for SigHashNone.
+//   - SigHashAnyOneCanPay can be combined with the above and commits only to
+//     the signed input.
+//
+// For SigHashAll without SigHashAnyOneCanPay the prefix hash is the
+// transaction hash, so cachedPrefix may pass a precomputed tx.TxHash() to
+// avoid rehashing it for each input. It is ignored when nil.
+func CalcSignatureHash(script []byte, hashType SigHashType, tx *wire.MsgTx, idx int, cachedPrefix *wire.Hash) ([]byte, error) {
+	if idx < 0 || idx >= len(tx.TxIn) {
+		return nil, fmt.Errorf("%w: %d with %d inputs", ErrInvalidIndex, idx,
+			len(tx.TxIn))
+	}
+	base := hashType & sigHashMask
+	if base == SigHashSingle && idx >= len(tx.TxOut) {
+		return nil, fmt.Errorf("%w: input %d with %d outputs",
+			ErrInvalidSigHashSingleIndex, idx, len(tx.TxOut))
+	}
+
+	txIns := tx.TxIn
+	signIdx := idx
+	if hashType&SigHashAnyOneCanPay != 0 {
+		txIns = tx.TxIn[idx : idx+1]
+		signIdx = 0
+	}
+
+	var prefixHash wire.Hash
+	if base == SigHashAll && hashType&SigHashAnyOneCanPay == 0 && cachedPrefix != nil {
+		prefixHash = *cachedPrefix
+	} else {
+		txOuts := tx.TxOut
+		switch base {
+		case SigHashNone:
+			txOuts = nil
+		case SigHashSingle:
+			txOuts = tx.TxOut[:idx+1]
+		}
+		prefixHash = sigHashPrefix(tx, hashType, txIns, txOuts, signIdx, idx)
+	}
+
+	// Witness: version, input count and the script for the signed input
+	// with empty scripts for the others.
+	b := make([]byte, 0, 4+wire.VarIntSerializeSize(uint64(len(txIns)))+
+		len(txIns)+wire.VarIntSerializeSize(uint64(len(script)))+len(script))
+	b = appendUint32(b, uint32(tx.Version)|sigHashSerializeWitness<<16)
+	b = wire.AppendVarInt(b, uint64(len(txIns)))
+	for i := range txIns {
+		if i == signIdx {
+			b = wire.AppendVarBytes(b, script)
+		} else {
+			b = wire.AppendVarInt(b, 0)
+		}
+	}
+	witnessHash := wire.HashH(b)
+
+	b = make([]byte, 0, 4+2*wire.HashSize)
+	b = appendUint32(b, uint32(hashType))
+	b = append(b, prefixHash[:]...)
+	b = append(b, witnessHash[:]...)
+	h := wire.HashH(b)
+	return h[:], nil
+}
+
+// sigHashPrefix returns the prefix hash committing to txIns and txOuts with
+// the substitutions of the hash type applied. signIdx is the position of the
+// signed input in txIns and idx its position in the transaction.
+func sigHashPrefix(tx *wire.MsgTx, hashType SigHashType, txIns []*wire.TxIn, txOuts []*wire.TxOut, signIdx, idx int) wire.Hash {
+	base := hashType & sigHashMask
+	b := make([]byte, 0, 4+9+len(txIns)*(wire.HashSize+9)+9+len(txOuts)*19+8)
+	b = appendUint32(b, uint32(tx.Version)|sigHashSerializePrefix<<16)
+
+	b = wire.AppendVarInt(b, uint64(len(txIns)))
+	for i, ti := range txIns {
+		prevOut := &ti.PreviousOutPoint
+		b = append(b, prevOut.Hash[:]...)
+		b = appendUint32(b, prevOut.Index)
+		b = append(b, byte(prevOut.Tree))
+		sequence := ti.Sequence
+		if (base == SigHashNone || base == SigHashSingle) && i != signIdx {
+			sequence = 0
+		}
+		b = appendUint32(b, sequence)
+	}
+
+	b = wire.AppendVarInt(b, uint64(len(txOuts)))
+	for i, to := range txOuts {
+		value, pkScript := to.Value, to.PkScript
+		if base == SigHashSingle && i != idx {
+			value, pkScript = -1, nil
+		}
+		b = appendUint32(b, uint32(value))
+		b = appendUint32(b, uint32(uint64(value)>>32))
+		b = append(b, byte(to.Version), byte(to.Version>>8))
+		b = wire.AppendVarBytes(b, pkScript)
+	}
+
+	b = appendUint32(b, tx.LockTime)
+	b = appendUint32(b, tx.Expiry)
+	return wire.HashH(b)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/rickiey/blake256/wire"
+)
+
+var testPkScript = hexToBytes("76a914000102030405060708090a0b0c0d0e0f1011121388ac")
+
+// genesisCoinbaseHex is the coinbase transaction of the Decred mainnet genesis
+// block.
+const genesisCoinbaseHex = "0100000001000000000000000000000000000000000000000000000000000000" +
+	"0000000000ffffffff00ffffffff010000000000000000000020801679e98561ada96c" +
+	"aec2949a5d41c4cab3851eb740d951c10ecbcf265c1fd9000000000000000001ffff" +
+	"ffffffffffff00000000ffffffff020000"
+
+// testTx returns a transaction with several inputs and outputs for the tests
+// of what each hash type commits to.
+func testTx() *wire.MsgTx {
+	tx := wire.NewMsgTx()
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: wire.HashH([]byte("prev0")), Index: 2},
+		Sequence:         wire.MaxTxInSequenceNum,
+		ValueIn:          500000000,
+		BlockHeight:      123456,
+		BlockIndex:       7,
+		SignatureScript:  []byte{1, 2, 3},
+	})
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: wire.HashH([]byte("prev1")), Tree: wire.TxTreeStake},
+		Sequence:         0xfffffffe,
+		ValueIn:          25000,
+		BlockIndex:       wire.NullBlockIndex,
+	})
+	tx.AddTxIn(&wire.TxIn{
+		PreviousOutPoint: wire.OutPoint{Hash: wire.HashH([]byte("prev2")), Index: 5},
+		Sequence:         10,
+		ValueIn:          wire.NullValueIn,
+		BlockHeight:      99,
+		BlockIndex:       1,
+		SignatureScript:  bytes.Repeat([]byte{0x51}, 300),
+	})
+	tx.AddTxOut(wire.NewTxOut(100000000, testPkScript))
+	tx.AddTxOut(wire.NewTxOut(1, append([]byte{0x6a}, bytes.Repeat([]byte{'x'}, 299)...)))
+	tx.AddTxOut(&wire.TxOut{Value: 0, Version: 1})
+	tx.LockTime = 0x12345678
+	tx.Expiry = 1000
+	return tx
+}
+
+// TestCalcSignatureHash checks the signature hashes of the mainnet genesis
+// coinbase against a pay-to-pubkey-hash script. The expected hashes were
+// computed with an independent implementation of the Decred algorithm.
+func TestCalcSignatureHash(t *testing.T) {
+	tests := []struct {
+		hashType SigHashType
+		want     string
+	}{
+		{0x01, "5b5d5b875fd2094c9156f2be7b8f0d4eaacc641a49e52b987437bce8d36f2510"},
+		{0x02, "d2d056c1fd75eed8a49789e5da5ebe1d15aa39c6fc2fc8975f320190a22222b9"},
+		{0x03, "085a1d7e064274ca92fd3b41f8414fcc72a721b2cdd26668e61be34cfa033ebe"},
+		{0x81, "c6a3dd2cfba705779055700fe5dca1167eb3471bcdc0dda44ec9c1e1bfcf213f"},
+		{0x82, "eba9f449b0ef14f5d8de2027c562b119be50eb7a7a853bac3f03042ad2055d38"},
+		{0x83, "f4917c46201cdf1a72900727d2baa887cdc0c0d088b12b35aadef653cd311b41"},
+		{0x00, "7cd386d1792ea5bf114e6fcb8cdfa7363e9406920e22f04f202bcb47713ab648"},
+		{0x1f, "a37bb5658cea5550ac44c402a88053a4c05324fbcef0d2adabae9b63fb4d2a56"},
+	}
+
+	var tx wire.MsgTx
+	if err := tx.FromBytes(hexToBytes(genesisCoinbaseHex)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	prefix := tx.TxHash()
+	for _, test := range tests {
+		h, err := CalcSignatureHash(testPkScript, test.hashType, &tx, 0, nil)
+		if err != nil {
+			t.Fatalf("0x%02x: unexpected error: %v", test.hashType, err)
+		}
+		if got := fmt.Sprintf("%x", h); got != test.want {
+			t.Errorf("0x%02x: expected %q, got %q", test.hashType, test.want, got)
+		}
+
+		// The cached prefix hash must not change the result for any hash
+		// type.
+		h, _ = CalcSignatureHash(testPkScript, test.hashType, &tx, 0, &prefix)
+		if got := fmt.Sprintf("%x", h); got != test.want {
+			t.Errorf("0x%02x: cached: expected %q, got %q", test.hashType, test.want, got)
+		}
+	}
+}
+
+// TestCalcSignatureHashReference runs the signature hash vectors of dcrd in
+// testdata/sighash.json. Each vector is a raw transaction, the script, the
+// input index, the hash type, the expected hash, the expected result and an
+// optional comment. Entries with a single field are comments.
+func TestCalcSignatureHashReference(t *testing.T) {
+	file, err := os.ReadFile(filepath.Join("testdata", "sighash.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tests [][]interface{}
+	if err := json.Unmarshal(file, &tests); err != nil {
+		t.Fatal(err)
+	}
+
+	for i, test := range tests {
+		if len(test) == 1 {
+			continue
+		}
+		if len(test) < 6 {
+			t.Fatalf("%d: malformed vector %v", i, test)
+		}
+		var tx wire.MsgTx
+		if err := tx.FromBytes(hexToBytes(test[0].(string))); err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		script := hexToBytes(test[1].(string))
+		idx := int(test[2].(float64))
+		hashType := SigHashType(test[3].(float64))
+		var wantErr error
+		switch test[5].(string) {
+		case "OK":
+		case "SIGHASH_SINGLE_IDX":
+			wantErr = ErrInvalidSigHashSingleIndex
+		default:
+			t.Fatalf("%d: unknown result %q", i, test[5])
+		}
+
+		h, err := CalcSignatureHash(script, hashType, &tx, idx, nil)
+		if !errors.Is(err, wantErr) {
+			t.Errorf("%d: expected error %v, got %v", i, wantErr, err)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if got := fmt.Sprintf("%x", h); got != test[4] {
+			t.Errorf("%d: expected %q, got %q", i, test[4], got)
+		}
+
+		prefix := tx.TxHash()
+		h, _ = CalcSignatureHash(script, hashType, &tx, idx, &prefix)
+		if got := fmt.Sprintf("%x", h); got != test[4] {
+			t.Errorf("%d: cached: expected %q, got %q", i, test[4], got)
+		}
+	}
+}
+
+// TestCalcSignatureHashCommitments checks which changes to a transaction
+// change the signature hash of each input for each hash type.
+func TestCalcSignatureHashCommitments(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(tx *wire.MsgTx, idx int)
+		// commits reports whether the hash of input idx for the hash type
+		// commits to the change.
+		commits func(hashType SigHashType, idx int) bool
+	}{{
+		name:    "signed input sequence",
+		change:  func(tx *wire.MsgTx, idx int) { tx.TxIn[idx].Sequence ^= 1 },
+		commits: func(SigHashType, int) bool { return true },
+	}, {
+		name: "other input sequence",
+		change: func(tx *wire.MsgTx, idx int) {
+			tx.TxIn[(idx+1)%len(tx.TxIn)].Sequence ^= 1
+		},
+		commits: func(hashType SigHashType, idx int) bool {
+			return hashType == SigHashAll
+		},
+	}, {
+		name: "other input outpoint",
+		change: func(tx *wire.MsgTx, idx int) {
+			tx.TxIn[(idx+1)%len(tx.TxIn)].PreviousOutPoint.Index++
+		},
+		commits: func(hashType SigHashType, idx int) bool {
+			return hashType&SigHashAnyOneCanPay == 0
+		},
+	}, {
+		name: "witness data",
+		change: func(tx *wire.MsgTx, idx int) {
+			for _, ti := range tx.TxIn {
+				ti.ValueIn++
+				ti.SignatureScript = append(ti.SignatureScript, 0)
+			}
+		},
+		commits: func(SigHashType, int) bool { return false },
+	}, {
+		name:   "output at signed index",
+		change: func(tx *wire.MsgTx, idx int) { tx.TxOut[idx].Value++ },
+		commits: func(hashType SigHashType, idx int) bool {
+			return hashType&sigHashMask != SigHashNone
+		},
+	}, {
+		name:   "last output",
+		change: func(tx *wire.MsgTx, idx int) { tx.TxOut[2].PkScript = []byte{0x6a} },
+		commits: func(hashType SigHashType, idx int) bool {
+			base := hashType & sigHashMask
+			return base == SigHashAll || base == SigHashSingle && idx == 2
+		},
+	}, {
+		name: "value and script of earlier output",
+		change: func(tx *wire.MsgTx, idx int) {
+			tx.TxOut[0].Value++
+			tx.TxOut[0].PkScript = nil
+		},
+		commits: func(hashType SigHashType, idx int) bool {
+			base := hashType & sigHashMask
+			return base == SigHashAll || base == SigHashSingle && idx == 0
+		},
+	}, {
+		name:   "version of earlier output",
+		change: func(tx *wire.MsgTx, idx int) { tx.TxOut[0].Version++ },
+		commits: func(hashType SigHashType, idx int) bool {
+			return hashType&sigHashMask != SigHashNone
+		},
+	}, {
+		name:    "expiry",
+		change:  func(tx *wire.MsgTx, idx int) { tx.Expiry++ },
+		commits: func(SigHashType, int) bool { return true },
+	}}
+
+	hashTypes := []SigHashType{SigHashAll, SigHashNone, SigHashSingle,
+		SigHashAll | SigHashAnyOneCanPay, SigHashNone | SigHashAnyOneCanPay,
+		SigHashSingle | SigHashAnyOneCanPay}
+	for _, test := range tests {
+		for _, hashType := range hashTypes {
+			for idx := range testTx().TxIn {
+				want, err := CalcSignatureHash(testPkScript, hashType, testTx(), idx, nil)
+				if err != nil {
+					t.Fatalf("%s: 0x%02x/%d: unexpected error: %v", test.name, hashType, idx, err)
+				}
+				tx := testTx()
+				test.change(tx, idx)
+				got, err := CalcSignatureHash(testPkScript, hashType, tx, idx, nil)
+				if err != nil {
+					t.Fatalf("%s: 0x%02x/%d: unexpected error: %v", test.name, hashType, idx, err)
+				}
+				if commits := test.commits(hashType, idx); bytes.Equal(got, want) == commits {
+					t.Errorf("%s: 0x%02x/%d: expected commitment %v", test.name, hashType, idx, commits)
+				}
+			}
+		}
+	}
+}
+
+func TestCalcSignatureHashErrors(t *testing.T) {
+	tx := testTx()
+	for _, idx := range []int{-1, 3} {
+		if _, err := CalcSignatureHash(testPkScript, SigHashAll, tx, idx, nil); !errors.Is(err, ErrInvalidIndex) {
+			t.Errorf("%d: expected %v, got %v", idx, ErrInvalidIndex, err)
+		}
+	}
+
+	tx.TxOut = tx.TxOut[:2]
+	for _, hashType := range []SigHashType{SigHashSingle, SigHashSingle | SigHashAnyOneCanPay} {
+		if _, err := CalcSignatureHash(testPkScript, hashType, tx, 2, nil); !errors.Is(err, ErrInvalidSigHashSingleIndex) {
+			t.Errorf("0x%02x: expected %v, got %v", hashType, ErrInvalidSigHashSingleIndex, err)
+		}
+	}
+	if _, err := CalcSignatureHash(testPkScript, SigHashSingle, tx, 1, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}
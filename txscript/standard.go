@@ -0,0 +1,245 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/rickiey/blake256/address"
+)
+
+// Opcodes used by the standard output script templates.
+const (
+	OP_DATA_20     = 0x14
+	OP_DUP         = 0x76
+	OP_EQUAL       = 0x87
+	OP_EQUALVERIFY = 0x88
+	OP_HASH160     = 0xa9
+	OP_CHECKSIG    = 0xac
+	OP_SSTX        = 0xba
+	OP_SSGEN       = 0xbb
+	OP_SSRTX       = 0xbc
+	OP_SSTXCHANGE  = 0xbd
+	OP_TGEN        = 0xc3
+)
+
+// Sizes of the standard output scripts.
+const (
+	// P2PKHScriptSize is the size of a pay-to-pubkey-hash script:
+	// OP_DUP OP_HASH160 OP_DATA_20 <hash> OP_EQUALVERIFY OP_CHECKSIG.
+	P2PKHScriptSize = 25
+
+	// P2SHScriptSize is the size of a pay-to-script-hash script:
+	// OP_HASH160 OP_DATA_20 <hash> OP_EQUAL.
+	P2SHScriptSize = 23
+)
+
+// ScriptClass identifies a standard output script template. The stake
+// classes are a P2PKH or P2SH script prefixed with a stake opcode.
+type ScriptClass byte
+
+const (
+	NonStandardTy ScriptClass = iota
+	PubKeyHashTy
+	ScriptHashTy
+	StakeSubmissionPubKeyHashTy
+	StakeSubmissionScriptHashTy
+	StakeGenPubKeyHashTy
+	StakeGenScriptHashTy
+	StakeRevocationPubKeyHashTy
+	StakeRevocationScriptHashTy
+	StakeSubChangePubKeyHashTy
+	StakeSubChangeScriptHashTy
+	TreasuryGenPubKeyHashTy
+	TreasuryGenScriptHashTy
+
+	numScriptClasses
+)
+
+// scriptClassNames are the names of the script classes.
+var scriptClassNames = [numScriptClasses]string{
+	NonStandardTy:               "nonstandard",
+	PubKeyHashTy:                "pubkeyhash",
+	ScriptHashTy:                "scripthash",
+	StakeSubmissionPubKeyHashTy: "stakesubmission-pubkeyhash",
+	StakeSubmissionScriptHashTy: "stakesubmission-scripthash",
+	StakeGenPubKeyHashTy:        "stakegen-pubkeyhash",
+	StakeGenScriptHashTy:        "stakegen-scripthash",
+	StakeRevocationPubKeyHashTy: "stakerevoke-pubkeyhash",
+	StakeRevocationScriptHashTy: "stakerevoke-scripthash",
+	StakeSubChangePubKeyHashTy:  "stakechange-pubkeyhash",
+	StakeSubChangeScriptHashTy:  "stakechange-scripthash",
+	TreasuryGenPubKeyHashTy:     "treasurygen-pubkeyhash",
+	TreasuryGenScriptHashTy:     "treasurygen-scripthash",
+}
+
+// stakeOpcodes maps stake opcodes to their P2PKH class. The P2SH class
+// follows it.
+var stakeOpcodes = map[byte]ScriptClass{
+	OP_SSTX:       StakeSubmissionPubKeyHashTy,
+	OP_SSGEN:      StakeGenPubKeyHashTy,
+	OP_SSRTX:      StakeRevocationPubKeyHashTy,
+	OP_SSTXCHANGE: StakeSubChangePubKeyHashTy,
+	OP_TGEN:       TreasuryGenPubKeyHashTy,
+}
+
+// String returns the name of the class.
+func (c ScriptClass) String() string {
+	if c >= numScriptClasses {
+		return fmt.Sprintf("ScriptClass(%d)", byte(c))
+	}
+	return scriptClassNames[c]
+}
+
+// IsScriptHash reports whether scripts of the class pay to a script hash.
+func (c ScriptClass) IsScriptHash() bool {
+	return c < numScriptClasses && c != NonStandardTy && c%2 == 0
+}
+
+// IsStake reports whether scripts of the class carry a stake opcode.
+func (c ScriptClass) IsStake() bool {
+	return c >= StakeSubmissionPubKeyHashTy && c < numScriptClasses
+}
+
+// stakeOpcode returns the stake opcode of a stake class.
+func (c ScriptClass) stakeOpcode() byte {
+	base := c
+	if c.IsScriptHash() {
+		base--
+	}
+	for op, class := range stakeOpcodes {
+		if class == base {
+			return op
+		}
+	}
+	return 0
+}
+
+var (
+	// ErrNotStakeClass is returned when building a stake script for a class
+	// that has no stake opcode.
+	ErrNotStakeClass = errors.New("txscript: not a stake script class")
+
+	// ErrUnsupportedAddress is returned when an address is neither a
+	// pay-to-pubkey-hash nor a pay-to-script-hash address of the network.
+	ErrUnsupportedAddress = errors.New("txscript: unsupported address")
+)
+
+// ScriptHash returns the hash committed to by a pay-to-script-hash output
+// for a redeem script, RIPEMD-160(BLAKE-256(redeemScript)).
+func ScriptHash(redeemScript []byte) [address.Hash160Size]byte {
+	return address.Hash160(redeemScript)
+}
+
+// PayToPubKeyHashScript returns a script paying to a public key hash.
+func PayToPubKeyHashScript(hash *[address.Hash160Size]byte) []byte {
+	script := make([]byte, 0, P2PKHScriptSize)
+	script = append(script, OP_DUP, OP_HASH160, OP_DATA_20)
+	script = append(script, hash[:]...)
+	return append(script, OP_EQUALVERIFY, OP_CHECKSIG)
+}
+
+// PayToScriptHashScript returns a script paying to a script hash.
+func PayToScriptHashScript(hash *[address.Hash160Size]byte) []byte {
+	script := make([]byte, 0, P2SHScriptSize)
+	script = append(script, OP_HASH160, OP_DATA_20)
+	script = append(script, hash[:]...)
+	return append(script, OP_EQUAL)
+}
+
+// PayToStakeScript returns a script of a stake class paying to hash, which
+// is a script hash for the script hash classes and a public key hash
+// otherwise.
+func PayToStakeScript(class ScriptClass, hash *[address.Hash160Size]byte) ([]byte, error) {
+	if !class.IsStake() {
+		return nil, fmt.Errorf("%w: %v", ErrNotStakeClass, class)
+	}
+	script := []byte{class.stakeOpcode()}
+	if class.IsScriptHash() {
+		return append(script, PayToScriptHashScript(hash)...), nil
+	}
+	return append(script, PayToPubKeyHashScript(hash)...), nil
+}
+
+// PayToAddrScript returns a script paying to a pay-to-pubkey-hash or
+// pay-to-script-hash address of the network.
+func PayToAddrScript(addr string, net *address.Params) ([]byte, error) {
+	if hash, err := address.DecodePubKeyHash(addr, net); err == nil {
+		return PayToPubKeyHashScript(&hash), nil
+	}
+	hash, err := address.DecodeScriptHash(addr, net)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrUnsupportedAddress, addr, err)
+	}
+	return PayToScriptHashScript(&hash), nil
+}
+
+// isPubKeyHash reports whether script is a P2PKH script.
+func isPubKeyHash(script []byte) bool {
+	return len(script) == P2PKHScriptSize &&
+		script[0] == OP_DUP &&
+		script[1] == OP_HASH160 &&
+		script[2] == OP_DATA_20 &&
+		script[23] == OP_EQUALVERIFY &&
+		script[24] == OP_CHECKSIG
+}
+
+// isScriptHash reports whether script is a P2SH script.
+func isScriptHash(script []byte) bool {
+	return len(script) == P2SHScriptSize &&
+		script[0] == OP_HASH160 &&
+		script[1] == OP_DATA_20 &&
+		script[22] == OP_EQUAL
+}
+
+// ExtractHash160 returns the class of an output script and the public key
+// or script hash it pays to. Only version 0 scripts are standard; any other
+// version, or a script matching no template, is NonStandardTy with a zero
+// hash.
+func ExtractHash160(version uint16, script []byte) (ScriptClass, [address.Hash160Size]byte) {
+	var hash [address.Hash160Size]byte
+	if version != 0 {
+		return NonStandardTy, hash
+	}
+
+	class := PubKeyHashTy
+	if len(script) > 0 {
+		if c, ok := stakeOpcodes[script[0]]; ok {
+			class = c
+			script = script[1:]
+		}
+	}
+	switch {
+	case isPubKeyHash(script):
+		copy(hash[:], script[3:23])
+	case isScriptHash(script):
+		class++
+		copy(hash[:], script[2:22])
+	default:
+		return NonStandardTy, hash
+	}
+	return class, hash
+}
+
+// GetScriptClass returns the class of an output script.
+func GetScriptClass(version uint16, script []byte) ScriptClass {
+	class, _ := ExtractHash160(version, script)
+	return class
+}
+
+// ExtractAddress returns the class of an output script and the address on
+// the network it pays to. The address is empty for non-standard scripts.
+func ExtractAddress(version uint16, script []byte, net *address.Params) (ScriptClass, string) {
+	class, hash := ExtractHash160(version, script)
+	switch {
+	case class == NonStandardTy:
+		return class, ""
+	case class.IsScriptHash():
+		return class, address.EncodeScriptHash(&hash, net)
+	default:
+		return class, address.EncodePubKeyHash(&hash, net)
+	}
+}
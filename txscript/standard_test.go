@@ -0,0 +1,191 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package txscript
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/rickiey/blake256/address"
+)
+
+func TestScriptHash(t *testing.T) {
+	// 2-of-2 multisig redeem script of the public keys of 1 and 2.
+	redeem := hexToBytes("52210279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f817982102c6047f9441ed7d6d3045406e95c07cd85c778e4b8cef3ca7abac09b95c709ee552ae")
+	hash := ScriptHash(redeem)
+	want := hexToBytes("909ba48467560971cd27bc1c99ff66e9f89b196e")
+	if !bytes.Equal(hash[:], want) {
+		t.Fatalf("expected %x, got %x", want, hash)
+	}
+	script := PayToScriptHashScript(&hash)
+	tests := []struct {
+		net  *address.Params
+		addr string
+	}{
+		{address.MainNetParams, "DckeCpH1pUrpZqERFynfefZLcKGxSWX8hFh"},
+		{address.TestNet3Params, "TckhRoQXDGuvgBun5NQpoEacCREt15fuAKz"},
+	}
+	for _, test := range tests {
+		class, addr := ExtractAddress(0, script, test.net)
+		if class != ScriptHashTy || addr != test.addr {
+			t.Errorf("%s: expected %v %s, got %v %s", test.net.Name,
+				ScriptHashTy, test.addr, class, addr)
+		}
+		got, err := PayToAddrScript(test.addr, test.net)
+		if err != nil {
+			t.Fatalf("%s: %v", test.net.Name, err)
+		}
+		if !bytes.Equal(got, script) {
+			t.Errorf("%s: expected %x, got %x", test.net.Name, script, got)
+		}
+	}
+}
+
+func TestPayToPubKeyHash(t *testing.T) {
+	var hash [address.Hash160Size]byte
+	copy(hash[:], hexToBytes("e280cb6e66b96679aec288b1fbdbd4db08077a1b"))
+	script := PayToPubKeyHashScript(&hash)
+	want := hexToBytes("76a914e280cb6e66b96679aec288b1fbdbd4db08077a1b88ac")
+	if !bytes.Equal(script, want) {
+		t.Fatalf("expected %x, got %x", want, script)
+	}
+
+	tests := []struct {
+		net  *address.Params
+		addr string
+	}{
+		{address.MainNetParams, "DsmcYVbP1Nmag2H4AS17UTvmWXmGeA7nLDx"},
+		{address.TestNet3Params, "TsmfmUitQApgnNxQypdGd2x36djCCpDpERU"},
+	}
+	for _, test := range tests {
+		class, addr := ExtractAddress(0, script, test.net)
+		if class != PubKeyHashTy || addr != test.addr {
+			t.Errorf("%s: expected %v %s, got %v %s", test.net.Name,
+				PubKeyHashTy, test.addr, class, addr)
+		}
+		got, err := PayToAddrScript(test.addr, test.net)
+		if err != nil {
+			t.Fatalf("%s: %v", test.net.Name, err)
+		}
+		if !bytes.Equal(got, script) {
+			t.Errorf("%s: expected %x, got %x", test.net.Name, script, got)
+		}
+	}
+
+	_, err := PayToAddrScript(tests[0].addr, address.TestNet3Params)
+	if !errors.Is(err, ErrUnsupportedAddress) {
+		t.Errorf("expected %v, got %v", ErrUnsupportedAddress, err)
+	}
+}
+
+func TestStakeScripts(t *testing.T) {
+	var hash [address.Hash160Size]byte
+	copy(hash[:], hexToBytes("e280cb6e66b96679aec288b1fbdbd4db08077a1b"))
+	p2pkh := PayToPubKeyHashScript(&hash)
+	p2sh := PayToScriptHashScript(&hash)
+
+	tests := []struct {
+		class  ScriptClass
+		opcode byte
+		inner  []byte
+	}{
+		{StakeSubmissionPubKeyHashTy, OP_SSTX, p2pkh},
+		{StakeSubmissionScriptHashTy, OP_SSTX, p2sh},
+		{StakeGenPubKeyHashTy, OP_SSGEN, p2pkh},
+		{StakeGenScriptHashTy, OP_SSGEN, p2sh},
+		{StakeRevocationPubKeyHashTy, OP_SSRTX, p2pkh},
+		{StakeRevocationScriptHashTy, OP_SSRTX, p2sh},
+		{StakeSubChangePubKeyHashTy, OP_SSTXCHANGE, p2pkh},
+		{StakeSubChangeScriptHashTy, OP_SSTXCHANGE, p2sh},
+		{TreasuryGenPubKeyHashTy, OP_TGEN, p2pkh},
+		{TreasuryGenScriptHashTy, OP_TGEN, p2sh},
+	}
+	for _, test := range tests {
+		script, err := PayToStakeScript(test.class, &hash)
+		if err != nil {
+			t.Fatalf("%v: %v", test.class, err)
+		}
+		want := append([]byte{test.opcode}, test.inner...)
+		if !bytes.Equal(script, want) {
+			t.Errorf("%v: expected %x, got %x", test.class, want, script)
+		}
+		if !test.class.IsStake() {
+			t.Errorf("%v: expected stake class", test.class)
+		}
+
+		class, got := ExtractHash160(0, script)
+		if class != test.class || got != hash {
+			t.Errorf("%v: expected %v %x, got %v %x", test.class, test.class,
+				hash, class, got)
+		}
+		_, addr := ExtractAddress(0, script, address.MainNetParams)
+		wantAddr := address.EncodePubKeyHash(&hash, address.MainNetParams)
+		if test.class.IsScriptHash() {
+			wantAddr = address.EncodeScriptHash(&hash, address.MainNetParams)
+		}
+		if addr != wantAddr {
+			t.Errorf("%v: expected %s, got %s", test.class, wantAddr, addr)
+		}
+	}
+
+	for _, class := range []ScriptClass{NonStandardTy, PubKeyHashTy, ScriptHashTy} {
+		if _, err := PayToStakeScript(class, &hash); !errors.Is(err, ErrNotStakeClass) {
+			t.Errorf("%v: expected %v, got %v", class, ErrNotStakeClass, err)
+		}
+	}
+}
+
+func TestNonStandardScripts(t *testing.T) {
+	var hash [address.Hash160Size]byte
+	p2pkh := PayToPubKeyHashScript(&hash)
+
+	tests := []struct {
+		name    string
+		version uint16
+		script  []byte
+	}{
+		{"empty", 0, nil},
+		{"script version", 1, p2pkh},
+		{"truncated", 0, p2pkh[:24]},
+		{"trailing byte", 0, append(append([]byte{}, p2pkh...), 0)},
+		{"wrong push", 0, append([]byte{OP_DUP, OP_HASH160, 0x13}, p2pkh[3:]...)},
+		{"unknown tag", 0, append([]byte{0xbe}, p2pkh...)},
+		{"double tag", 0, append([]byte{OP_SSTX, OP_SSTX}, p2pkh...)},
+		{"tag only", 0, []byte{OP_SSGEN}},
+	}
+	for _, test := range tests {
+		class, addr := ExtractAddress(test.version, test.script, address.MainNetParams)
+		if class != NonStandardTy || addr != "" {
+			t.Errorf("%s: expected nonstandard, got %v %q", test.name, class, addr)
+		}
+		if class := GetScriptClass(test.version, test.script); class != NonStandardTy {
+			t.Errorf("%s: expected nonstandard, got %v", test.name, class)
+		}
+	}
+}
+
+func TestScriptClassString(t *testing.T) {
+	tests := []struct {
+		class ScriptClass
+		want  string
+		sh    bool
+	}{
+		{NonStandardTy, "nonstandard", false},
+		{PubKeyHashTy, "pubkeyhash", false},
+		{ScriptHashTy, "scripthash", true},
+		{StakeGenScriptHashTy, "stakegen-scripthash", true},
+		{TreasuryGenPubKeyHashTy, "treasurygen-pubkeyhash", false},
+		{ScriptClass(200), "ScriptClass(200)", false},
+	}
+	for _, test := range tests {
+		if got := test.class.String(); got != test.want {
+			t.Errorf("%d: expected %q, got %q", test.class, test.want, got)
+		}
+		if got := test.class.IsScriptHash(); got != test.sh {
+			t.Errorf("%v: expected IsScriptHash %v, got %v", test.class, test.sh, got)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+)
+
+// HashingReader hashes the data read through it.
+type HashingReader struct {
+	r io.Reader
+	h hash.Hash
+}
+
+// NewHashingReader returns a reader that reads from r and writes everything
+// it reads to h, which is typically created with New, New224, NewSalt or
+// New224Salt.
+func NewHashingReader(r io.Reader, h hash.Hash) *HashingReader {
+	return &HashingReader{r: r, h: h}
+}
+
+// Read reads from the underlying reader and hashes the data read.
+func (hr *HashingReader) Read(p []byte) (int, error) {
+	n, err := hr.r.Read(p)
+	hr.h.Write(p[:n])
+	return n, err
+}
+
+// Sum appends the checksum of the data read so far to b.
+func (hr *HashingReader) Sum(b []byte) []byte {
+	return hr.h.Sum(b)
+}
+
+// HashingWriter hashes the data written through it.
+type HashingWriter struct {
+	w io.Writer
+	h hash.Hash
+}
+
+// NewHashingWriter returns a writer that writes to w and hashes everything
+// successfully written with h, which is typically created with New, New224,
+// NewSalt or New224Salt.
+func NewHashingWriter(w io.Writer, h hash.Hash) *HashingWriter {
+	return &HashingWriter{w: w, h: h}
+}
+
+// Write writes to the underlying writer and hashes the data written.
+func (hw *HashingWriter) Write(p []byte) (int, error) {
+	n, err := hw.w.Write(p)
+	hw.h.Write(p[:n])
+	return n, err
+}
+
+// Sum appends the checksum of the data written so far to b.
+func (hw *HashingWriter) Sum(b []byte) []byte {
+	return hw.h.Sum(b)
+}
+
+// ErrDigestMismatch is matched by errors.Is for the *DigestMismatchError of a
+// verifying reader.
+var ErrDigestMismatch = errors.New("blake256: digest mismatch")
+
+// DigestMismatchError is returned by verifying readers when the checksum of
+// the stream does not match the expected checksum.
+type DigestMismatchError struct {
+	Expected []byte
+	Actual   []byte
+}
+
+func (e *DigestMismatchError) Error() string {
+	return fmt.Sprintf("%v: expected %x, got %x", ErrDigestMismatch,
+		e.Expected, e.Actual)
+}
+
+// Unwrap returns ErrDigestMismatch.
+func (e *DigestMismatchError) Unwrap() error { return ErrDigestMismatch }
+
+type verifyingReader struct {
+	hr       HashingReader
+	expected []byte
+	err      error
+}
+
+// VerifyingReader returns a reader that reads from r and, once r returns
+// io.EOF, returns io.EOF only if the BLAKE-256 checksum of the data read
+// matches expected. Otherwise it returns a *DigestMismatchError.
+//
+// Data returned before the end of the stream is not verified, so callers must
+// not act on it until io.EOF is seen.
+func VerifyingReader(r io.Reader, expected [Size]byte) io.Reader {
+	return NewVerifyingReader(r, New(), expected[:])
+}
+
+// NewVerifyingReader is like VerifyingReader but hashes with h, such as one
+// created with New224, NewSalt or New224Salt, and compares against expected,
+// which must be h.Size() bytes.
+func NewVerifyingReader(r io.Reader, h hash.Hash, expected []byte) io.Reader {
+	if len(expected) != h.Size() {
+		panic("expected digest length must match the hash size")
+	}
+	return &verifyingReader{
+		hr:       HashingReader{r: r, h: h},
+		expected: append([]byte(nil), expected...),
+	}
+}
+
+func (vr *verifyingReader) Read(p []byte) (int, error) {
+	if vr.err != nil {
+		return 0, vr.err
+	}
+	n, err := vr.hr.Read(p)
+	if err == io.EOF {
+		if sum := vr.hr.Sum(nil); !bytes.Equal(sum, vr.expected) {
+			err = &DigestMismatchError{Expected: vr.expected, Actual: sum}
+		}
+	}
+	if err != nil {
+		vr.err = err
+	}
+	return n, err
+}
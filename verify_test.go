@@ -0,0 +1,87 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package blake256
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestHashingReaderWriter(t *testing.T) {
+	for i, v := range vectors256 {
+		hr := NewHashingReader(strings.NewReader(v.in), New())
+		var buf bytes.Buffer
+		hw := NewHashingWriter(&buf, New())
+		if _, err := io.Copy(hw, iotest.HalfReader(hr)); err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		if buf.String() != v.in {
+			t.Errorf("%d: data was not passed through", i)
+		}
+		for _, sum := range [][]byte{hr.Sum(nil), hw.Sum(nil)} {
+			if res := fmt.Sprintf("%x", sum); res != v.out {
+				t.Errorf("%d: expected %q, got %q", i, v.out, res)
+			}
+		}
+	}
+}
+
+func TestVerifyingReader(t *testing.T) {
+	for i, v := range vectors256 {
+		want := Sum256([]byte(v.in))
+		r := VerifyingReader(iotest.OneByteReader(strings.NewReader(v.in)), want)
+		data, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		if string(data) != v.in {
+			t.Errorf("%d: data was not passed through", i)
+		}
+
+		// Flip a bit of the expected digest.
+		want[0] ^= 1
+		r = VerifyingReader(strings.NewReader(v.in), want)
+		_, err = io.ReadAll(r)
+		var mismatch *DigestMismatchError
+		if !errors.As(err, &mismatch) || !errors.Is(err, ErrDigestMismatch) {
+			t.Fatalf("%d: expected digest mismatch, got %v", i, err)
+		}
+		if !bytes.Equal(mismatch.Expected, want[:]) || fmt.Sprintf("%x", mismatch.Actual) != v.out {
+			t.Errorf("%d: unexpected mismatch %v", i, mismatch)
+		}
+		// The error is sticky.
+		if _, err := r.Read(make([]byte, 1)); err != mismatch {
+			t.Errorf("%d: expected %v on subsequent read, got %v", i, mismatch, err)
+		}
+	}
+
+	for i, v := range vectors224 {
+		want := Sum224([]byte(v.in))
+		r := NewVerifyingReader(strings.NewReader(v.in), New224(), want[:])
+		if _, err := io.ReadAll(r); err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+		}
+	}
+
+	for i, v := range vectors256salt {
+		want, _ := hex.DecodeString(v.out)
+		r := NewVerifyingReader(strings.NewReader(v.in), NewSalt([]byte(v.salt)), want)
+		if _, err := io.ReadAll(r); err != nil {
+			t.Errorf("%d: unexpected error: %v", i, err)
+		}
+	}
+
+	// Read errors other than EOF are passed through.
+	r := VerifyingReader(iotest.TimeoutReader(strings.NewReader("data")), [Size]byte{})
+	if _, err := io.ReadAll(r); err != iotest.ErrTimeout {
+		t.Errorf("expected %v, got %v", iotest.ErrTimeout, err)
+	}
+}
@@ -0,0 +1,161 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/rickiey/blake256"
+)
+
+// MaxBlockHeaderPayload is the size of a serialized block header.
+const MaxBlockHeaderPayload = blake256.HeaderSize
+
+// ErrInvalidHeaderLen is returned when a serialized header does not have
+// the expected size.
+var ErrInvalidHeaderLen = errors.New("wire: invalid block header length")
+
+// BlockHeader is a Decred block header.
+type BlockHeader struct {
+	// Version of the block.
+	Version int32
+
+	// PrevBlock is the hash of the previous block header.
+	PrevBlock Hash
+
+	// MerkleRoot commits to the regular transaction tree. After DCP-0005
+	// it commits to both transaction trees.
+	MerkleRoot Hash
+
+	// StakeRoot commits to the stake transaction tree. After DCP-0005 it
+	// holds the header commitment root.
+	StakeRoot Hash
+
+	// VoteBits are the votes of the block on the previous block and
+	// agendas.
+	VoteBits uint16
+
+	// FinalState is the final state of the ticket lottery PRNG.
+	FinalState [6]byte
+
+	// Voters is the number of votes in the block.
+	Voters uint16
+
+	// FreshStake is the number of ticket purchases in the block.
+	FreshStake uint8
+
+	// Revocations is the number of ticket revocations in the block.
+	Revocations uint8
+
+	// PoolSize is the number of live tickets.
+	PoolSize uint32
+
+	// Bits is the proof of work difficulty target in compact form.
+	Bits uint32
+
+	// SBits is the stake difficulty, the price of a ticket in atoms.
+	SBits int64
+
+	// Height is the height of the block.
+	Height uint32
+
+	// Size is the serialized size of the block.
+	Size uint32
+
+	// Timestamp is the creation time of the block, with second precision.
+	Timestamp time.Time
+
+	// Nonce is varied by miners to find a solution.
+	Nonce uint32
+
+	// ExtraData is available to miners as additional nonce space.
+	ExtraData [32]byte
+
+	// StakeVersion is the stake version reported by the block.
+	StakeVersion uint32
+}
+
+// Bytes returns the 180-byte serialization of the header.
+func (h *BlockHeader) Bytes() []byte {
+	b := make([]byte, 0, MaxBlockHeaderPayload)
+	b = appendUint32(b, uint32(h.Version))
+	b = append(b, h.PrevBlock[:]...)
+	b = append(b, h.MerkleRoot[:]...)
+	b = append(b, h.StakeRoot[:]...)
+	b = appendUint16(b, h.VoteBits)
+	b = append(b, h.FinalState[:]...)
+	b = appendUint16(b, h.Voters)
+	b = append(b, h.FreshStake, h.Revocations)
+	b = appendUint32(b, h.PoolSize)
+	b = appendUint32(b, h.Bits)
+	b = appendUint64(b, uint64(h.SBits))
+	b = appendUint32(b, h.Height)
+	b = appendUint32(b, h.Size)
+	b = appendUint32(b, uint32(h.Timestamp.Unix()))
+	b = appendUint32(b, h.Nonce)
+	b = append(b, h.ExtraData[:]...)
+	return appendUint32(b, h.StakeVersion)
+}
+
+// Serialize writes the serialized header to w.
+func (h *BlockHeader) Serialize(w io.Writer) error {
+	_, err := w.Write(h.Bytes())
+	return err
+}
+
+// Deserialize reads a serialized header from r, replacing the contents of h.
+func (h *BlockHeader) Deserialize(r io.Reader) error {
+	var b [MaxBlockHeaderPayload]byte
+	if _, err := io.ReadFull(r, b[:]); err != nil {
+		return err
+	}
+	h.FromArray(&b)
+	return nil
+}
+
+// FromBytes deserializes the header in b, which must be exactly
+// MaxBlockHeaderPayload bytes.
+func (h *BlockHeader) FromBytes(b []byte) error {
+	if len(b) != MaxBlockHeaderPayload {
+		return fmt.Errorf("%w: header is %d bytes, want %d",
+			ErrInvalidHeaderLen, len(b), MaxBlockHeaderPayload)
+	}
+	h.FromArray((*[MaxBlockHeaderPayload]byte)(b))
+	return nil
+}
+
+// FromArray deserializes the header in b.
+func (h *BlockHeader) FromArray(b *[MaxBlockHeaderPayload]byte) {
+	le := binary.LittleEndian
+	h.Version = int32(le.Uint32(b[0:]))
+	copy(h.PrevBlock[:], b[4:36])
+	copy(h.MerkleRoot[:], b[36:68])
+	copy(h.StakeRoot[:], b[68:100])
+	h.VoteBits = le.Uint16(b[100:])
+	copy(h.FinalState[:], b[102:108])
+	h.Voters = le.Uint16(b[108:])
+	h.FreshStake = b[110]
+	h.Revocations = b[111]
+	h.PoolSize = le.Uint32(b[112:])
+	h.Bits = le.Uint32(b[116:])
+	h.SBits = int64(le.Uint64(b[120:]))
+	h.Height = le.Uint32(b[128:])
+	h.Size = le.Uint32(b[132:])
+	h.Timestamp = time.Unix(int64(le.Uint32(b[136:])), 0)
+	h.Nonce = le.Uint32(b[140:])
+	copy(h.ExtraData[:], b[144:176])
+	h.StakeVersion = le.Uint32(b[176:])
+}
+
+// BlockHash returns the BLAKE-256 hash of the serialized header, which
+// identifies the block.
+func (h *BlockHeader) BlockHash() Hash {
+	b := h.Bytes()
+	return Hash(blake256.Sum256Header180((*[MaxBlockHeaderPayload]byte)(b)))
+}
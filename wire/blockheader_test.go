@@ -0,0 +1,95 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+)
+
+// genesisHeader returns the Decred mainnet genesis block header.
+func genesisHeader() *BlockHeader {
+	merkle, err := NewHashFromStr("66aa7491b9adce110585ccab7e3fb5fe280de174530cca10eba2c6c3df01c10d")
+	if err != nil {
+		panic(err)
+	}
+	return &BlockHeader{
+		Version:    1,
+		MerkleRoot: *merkle,
+		Bits:       0x1b01ffff,
+		SBits:      2 * 1e8,
+		Timestamp:  time.Unix(1454954400, 0),
+	}
+}
+
+func TestBlockHeaderGenesisHash(t *testing.T) {
+	h := genesisHeader()
+	want := "298e5cc3d985bfe7f81dc135f360abe089edd4396b86d2de66b0cef42b21d980"
+	if got := h.BlockHash().String(); got != want {
+		t.Fatalf("expected %s, got %s", want, got)
+	}
+	if got := HashH(h.Bytes()); got != h.BlockHash() {
+		t.Fatalf("expected %v, got %v", h.BlockHash(), got)
+	}
+}
+
+func TestBlockHeaderRoundTrip(t *testing.T) {
+	h := genesisHeader()
+	h.PrevBlock[0] = 1
+	h.StakeRoot[31] = 2
+	h.VoteBits = 0x0102
+	h.FinalState = [6]byte{1, 2, 3, 4, 5, 6}
+	h.Voters = 5
+	h.FreshStake = 20
+	h.Revocations = 3
+	h.PoolSize = 40960
+	h.Height = 123456
+	h.Size = 7890
+	h.Nonce = 0xdeadbeef
+	h.ExtraData[0] = 0xff
+	h.StakeVersion = 9
+
+	b := h.Bytes()
+	if len(b) != MaxBlockHeaderPayload {
+		t.Fatalf("expected %d bytes, got %d", MaxBlockHeaderPayload, len(b))
+	}
+	var buf bytes.Buffer
+	if err := h.Serialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), b) {
+		t.Fatalf("Serialize and Bytes differ")
+	}
+
+	var got BlockHeader
+	if err := got.Deserialize(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if got != *h {
+		t.Fatalf("expected %+v, got %+v", *h, got)
+	}
+	var got2 BlockHeader
+	if err := got2.FromBytes(b); err != nil {
+		t.Fatal(err)
+	}
+	if got2 != *h {
+		t.Fatalf("expected %+v, got %+v", *h, got2)
+	}
+}
+
+func TestBlockHeaderFromBytesLength(t *testing.T) {
+	var h BlockHeader
+	for _, n := range []int{0, MaxBlockHeaderPayload - 1, MaxBlockHeaderPayload + 1} {
+		err := h.FromBytes(make([]byte, n))
+		if !errors.Is(err, ErrInvalidHeaderLen) {
+			t.Errorf("%d: expected %v, got %v", n, ErrInvalidHeaderLen, err)
+		}
+	}
+	if err := h.Deserialize(bytes.NewReader(make([]byte, 10))); err == nil {
+		t.Errorf("expected error on short read")
+	}
+}
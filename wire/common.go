@@ -0,0 +1,189 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+// Package wire implements the subset of the Decred wire protocol needed to
+// serialize transactions and block headers and to compute their BLAKE-256
+// hashes: variable length integers, transactions with their prefix and
+// witness sections, and hashes.
+package wire
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rickiey/blake256"
+)
+
+// MaxMessagePayload is the maximum size of a serialized message. It bounds
+// the counts and lengths accepted while decoding.
+const MaxMessagePayload = 1024 * 1024 * 32
+
+// HashSize is the size of a Hash.
+const HashSize = blake256.Size
+
+var (
+	// ErrNonCanonicalVarInt is returned when decoding a variable length
+	// integer that is not encoded in its shortest form.
+	ErrNonCanonicalVarInt = errors.New("wire: non-canonical varint")
+
+	// ErrTooLarge is returned when a decoded count or length exceeds what
+	// a message can hold.
+	ErrTooLarge = errors.New("wire: count or length too large")
+
+	// ErrInvalidHashStr is returned by NewHashFromStr for strings that are
+	// not at most 64 hexadecimal characters.
+	ErrInvalidHashStr = errors.New("wire: invalid hash string")
+)
+
+// Hash is a BLAKE-256 hash as used to identify transactions and blocks.
+type Hash [HashSize]byte
+
+// HashH returns the BLAKE-256 hash of b.
+func HashH(b []byte) Hash {
+	return Hash(blake256.Sum256(b))
+}
+
+// String returns the hash as the hexadecimal string of its bytes in reverse
+// order, which is how Decred displays transaction and block hashes.
+func (h Hash) String() string {
+	for i := 0; i < HashSize/2; i++ {
+		h[i], h[HashSize-1-i] = h[HashSize-1-i], h[i]
+	}
+	return hex.EncodeToString(h[:])
+}
+
+// NewHashFromStr parses a hash in the byte-reversed hexadecimal format
+// returned by String. Short strings are zero extended.
+func NewHashFromStr(s string) (*Hash, error) {
+	if len(s) > 2*HashSize {
+		return nil, ErrInvalidHashStr
+	}
+	if len(s)%2 != 0 {
+		s = "0" + s
+	}
+	var rev [HashSize]byte
+	if _, err := hex.Decode(rev[HashSize-len(s)/2:], []byte(s)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidHashStr, err)
+	}
+	var h Hash
+	for i, b := range rev {
+		h[HashSize-1-i] = b
+	}
+	return &h, nil
+}
+
+// VarIntSerializeSize returns the number of bytes needed to encode v as a
+// variable length integer.
+func VarIntSerializeSize(v uint64) int {
+	switch {
+	case v < 0xfd:
+		return 1
+	case v <= 0xffff:
+		return 3
+	case v <= 0xffffffff:
+		return 5
+	}
+	return 9
+}
+
+// AppendVarInt appends v encoded as a variable length integer: a single byte
+// below 0xfd, otherwise a 0xfd, 0xfe or 0xff marker followed by a
+// little-endian uint16, uint32 or uint64.
+func AppendVarInt(b []byte, v uint64) []byte {
+	switch {
+	case v < 0xfd:
+		return append(b, byte(v))
+	case v <= 0xffff:
+		return append(b, 0xfd, byte(v), byte(v>>8))
+	case v <= 0xffffffff:
+		return append(b, 0xfe, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+	}
+	b = append(b, 0xff)
+	var buf [8]byte
+	binary.LittleEndian.PutUint64(buf[:], v)
+	return append(b, buf[:]...)
+}
+
+// WriteVarInt writes v encoded as a variable length integer to w.
+func WriteVarInt(w io.Writer, v uint64) error {
+	var buf [9]byte
+	_, err := w.Write(AppendVarInt(buf[:0], v))
+	return err
+}
+
+// ReadVarInt reads a variable length integer from r. It rejects encodings
+// that are longer than necessary.
+func ReadVarInt(r io.Reader) (uint64, error) {
+	var buf [8]byte
+	if _, err := io.ReadFull(r, buf[:1]); err != nil {
+		return 0, err
+	}
+	var v, min uint64
+	switch buf[0] {
+	case 0xff:
+		if _, err := io.ReadFull(r, buf[:8]); err != nil {
+			return 0, err
+		}
+		v, min = binary.LittleEndian.Uint64(buf[:]), 0x100000000
+	case 0xfe:
+		if _, err := io.ReadFull(r, buf[:4]); err != nil {
+			return 0, err
+		}
+		v, min = uint64(binary.LittleEndian.Uint32(buf[:])), 0x10000
+	case 0xfd:
+		if _, err := io.ReadFull(r, buf[:2]); err != nil {
+			return 0, err
+		}
+		v, min = uint64(binary.LittleEndian.Uint16(buf[:])), 0xfd
+	default:
+		return uint64(buf[0]), nil
+	}
+	if v < min {
+		return 0, fmt.Errorf("%w: %d encoded with a 0x%x marker",
+			ErrNonCanonicalVarInt, v, buf[0])
+	}
+	return v, nil
+}
+
+// AppendVarBytes appends b prefixed with its length as a variable length
+// integer.
+func AppendVarBytes(dst, b []byte) []byte {
+	return append(AppendVarInt(dst, uint64(len(b))), b...)
+}
+
+// WriteVarBytes writes b prefixed with its length as a variable length
+// integer to w.
+func WriteVarBytes(w io.Writer, b []byte) error {
+	if err := WriteVarInt(w, uint64(len(b))); err != nil {
+		return err
+	}
+	_, err := w.Write(b)
+	return err
+}
+
+// WriteVarString is like WriteVarBytes for a string.
+func WriteVarString(w io.Writer, s string) error {
+	return WriteVarBytes(w, []byte(s))
+}
+
+// ReadVarBytes reads a byte slice written by WriteVarBytes. The length may
+// not exceed maxAllowed; fieldName describes the field in that error.
+func ReadVarBytes(r io.Reader, maxAllowed uint32, fieldName string) ([]byte, error) {
+	n, err := ReadVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+	if n > uint64(maxAllowed) {
+		return nil, fmt.Errorf("%w: %s is %d bytes, max %d", ErrTooLarge,
+			fieldName, n, maxAllowed)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
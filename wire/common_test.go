@@ -0,0 +1,107 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestVarInt(t *testing.T) {
+	tests := []struct {
+		v   uint64
+		enc string
+	}{
+		{0, "00"},
+		{0xfc, "fc"},
+		{0xfd, "fdfd00"},
+		{0xffff, "fdffff"},
+		{0x10000, "fe00000100"},
+		{0xffffffff, "feffffffff"},
+		{0x100000000, "ff0000000001000000"},
+		{0xffffffffffffffff, "ffffffffffffffffff"},
+	}
+	for i, test := range tests {
+		var buf bytes.Buffer
+		if err := WriteVarInt(&buf, test.v); err != nil {
+			t.Fatalf("%d: unexpected error: %v", i, err)
+		}
+		if got := fmt.Sprintf("%x", buf.Bytes()); got != test.enc {
+			t.Errorf("%d: expected %q, got %q", i, test.enc, got)
+		}
+		if n := VarIntSerializeSize(test.v); n != buf.Len() {
+			t.Errorf("%d: expected size %d, got %d", i, buf.Len(), n)
+		}
+		v, err := ReadVarInt(&buf)
+		if err != nil || v != test.v {
+			t.Errorf("%d: expected %d, got %d (%v)", i, test.v, v, err)
+		}
+	}
+}
+
+func TestVarIntErrors(t *testing.T) {
+	for _, enc := range []string{"fdfc00", "feffff0000", "ffffffffff00000000"} {
+		_, err := ReadVarInt(bytes.NewReader(hexToBytes(enc)))
+		if !errors.Is(err, ErrNonCanonicalVarInt) {
+			t.Errorf("%s: expected %v, got %v", enc, ErrNonCanonicalVarInt, err)
+		}
+	}
+	for _, enc := range []string{"", "fd00", "fe000000", "ff00000000000000"} {
+		_, err := ReadVarInt(bytes.NewReader(hexToBytes(enc)))
+		if err != io.EOF && err != io.ErrUnexpectedEOF {
+			t.Errorf("%s: expected EOF, got %v", enc, err)
+		}
+	}
+}
+
+func TestVarBytes(t *testing.T) {
+	var buf bytes.Buffer
+	data := bytes.Repeat([]byte{0xab}, 300)
+	if err := WriteVarBytes(&buf, data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), AppendVarBytes(nil, data)) {
+		t.Error("WriteVarBytes and AppendVarBytes differ")
+	}
+	enc := buf.Bytes()
+	got, err := ReadVarBytes(bytes.NewReader(enc), 300, "data")
+	if err != nil || !bytes.Equal(got, data) {
+		t.Errorf("unexpected result %x (%v)", got, err)
+	}
+	if _, err := ReadVarBytes(bytes.NewReader(enc), 299, "data"); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected %v, got %v", ErrTooLarge, err)
+	}
+	if _, err := ReadVarBytes(bytes.NewReader(enc[:100]), 300, "data"); err != io.ErrUnexpectedEOF {
+		t.Errorf("expected %v, got %v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func TestHashString(t *testing.T) {
+	const s = "298e5cc3d985bfe7f81dc135f360abe089edd4396b86d2de66b0cef42b21d980"
+	h, err := NewHashFromStr(s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if h[0] != 0x80 || h[31] != 0x29 {
+		t.Errorf("hash is not byte reversed: %x", h[:])
+	}
+	if got := h.String(); got != s {
+		t.Errorf("expected %q, got %q", s, got)
+	}
+
+	h, err = NewHashFromStr("1")
+	if err != nil || h[0] != 1 || h.String() != fmt.Sprintf("%064x", 1) {
+		t.Errorf("unexpected result %v (%v)", h, err)
+	}
+
+	for _, s := range []string{s + "00", "xy"} {
+		if _, err := NewHashFromStr(s); !errors.Is(err, ErrInvalidHashStr) {
+			t.Errorf("%q: expected %v, got %v", s, ErrInvalidHashStr, err)
+		}
+	}
+}
@@ -0,0 +1,402 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+const (
+	// TxVersion is the current transaction version.
+	TxVersion uint16 = 1
+
+	// MaxTxInSequenceNum is the maximum sequence number of an input.
+	MaxTxInSequenceNum uint32 = 0xffffffff
+
+	// TxTreeRegular and TxTreeStake identify the transaction tree of an
+	// outpoint.
+	TxTreeRegular int8 = 0
+	TxTreeStake   int8 = 1
+
+	// NullValueIn, NullBlockHeight and NullBlockIndex are the witness
+	// values of an input whose previous output is not known.
+	NullValueIn     int64  = -1
+	NullBlockHeight uint32 = 0x00000000
+	NullBlockIndex  uint32 = 0xffffffff
+
+	// Minimum serialized sizes of an input prefix, an input witness and an
+	// output, used to bound the counts accepted while decoding.
+	minTxInPrefixSize  = HashSize + 4 + 1 + 4
+	minTxInWitnessSize = 8 + 4 + 4 + 1
+	minTxOutSize       = 8 + 2 + 1
+
+	maxTxInPerMessage  = MaxMessagePayload/minTxInPrefixSize + 1
+	maxTxOutPerMessage = MaxMessagePayload/minTxOutSize + 1
+)
+
+// TxSerializeType selects which sections of a transaction are serialized.
+// It is encoded in the upper 16 bits of the serialized version.
+type TxSerializeType uint16
+
+const (
+	// TxSerializeFull serializes the prefix followed by the witness.
+	TxSerializeFull TxSerializeType = iota
+
+	// TxSerializeNoWitness serializes only the prefix.
+	TxSerializeNoWitness
+
+	// TxSerializeOnlyWitness serializes only the witness.
+	TxSerializeOnlyWitness
+)
+
+// ErrWitnessCount is returned when decoding a full transaction whose witness
+// section does not have one entry per input.
+var ErrWitnessCount = errors.New("wire: witness count does not match input count")
+
+// ErrUnknownSerType is returned when decoding a transaction with an unknown
+// serialization type.
+var ErrUnknownSerType = errors.New("wire: unknown transaction serialization type")
+
+// OutPoint identifies a transaction output.
+type OutPoint struct {
+	Hash  Hash
+	Index uint32
+	Tree  int8
+}
+
+// String returns the outpoint as hash:index.
+func (o OutPoint) String() string {
+	return fmt.Sprintf("%v:%d", o.Hash, o.Index)
+}
+
+// TxIn is a transaction input. The outpoint and sequence belong to the
+// prefix, the remaining fields to the witness.
+type TxIn struct {
+	// Prefix.
+	PreviousOutPoint OutPoint
+	Sequence         uint32
+
+	// Witness.
+	ValueIn         int64
+	BlockHeight     uint32
+	BlockIndex      uint32
+	SignatureScript []byte
+}
+
+// NewTxIn returns an input spending prevOut with the maximum sequence number.
+func NewTxIn(prevOut *OutPoint, valueIn int64, signatureScript []byte) *TxIn {
+	return &TxIn{
+		PreviousOutPoint: *prevOut,
+		Sequence:         MaxTxInSequenceNum,
+		ValueIn:          valueIn,
+		BlockHeight:      NullBlockHeight,
+		BlockIndex:       NullBlockIndex,
+		SignatureScript:  signatureScript,
+	}
+}
+
+// TxOut is a transaction output.
+type TxOut struct {
+	Value    int64
+	Version  uint16
+	PkScript []byte
+}
+
+// NewTxOut returns a version 0 output paying value to pkScript.
+func NewTxOut(value int64, pkScript []byte) *TxOut {
+	return &TxOut{Value: value, PkScript: pkScript}
+}
+
+// MsgTx is a Decred transaction.
+type MsgTx struct {
+	SerType  TxSerializeType
+	Version  uint16
+	TxIn     []*TxIn
+	TxOut    []*TxOut
+	LockTime uint32
+	Expiry   uint32
+}
+
+// NewMsgTx returns an empty transaction with the current version and full
+// serialization.
+func NewMsgTx() *MsgTx {
+	return &MsgTx{SerType: TxSerializeFull, Version: TxVersion}
+}
+
+// AddTxIn appends an input to the transaction.
+func (tx *MsgTx) AddTxIn(ti *TxIn) { tx.TxIn = append(tx.TxIn, ti) }
+
+// AddTxOut appends an output to the transaction.
+func (tx *MsgTx) AddTxOut(to *TxOut) { tx.TxOut = append(tx.TxOut, to) }
+
+func appendUint16(b []byte, v uint16) []byte {
+	return append(b, byte(v), byte(v>>8))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	return append(b, byte(v), byte(v>>8), byte(v>>16), byte(v>>24))
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	return appendUint32(appendUint32(b, uint32(v)), uint32(v>>32))
+}
+
+func (tx *MsgTx) appendPrefix(b []byte) []byte {
+	b = AppendVarInt(b, uint64(len(tx.TxIn)))
+	for _, ti := range tx.TxIn {
+		b = append(b, ti.PreviousOutPoint.Hash[:]...)
+		b = appendUint32(b, ti.PreviousOutPoint.Index)
+		b = append(b, byte(ti.PreviousOutPoint.Tree))
+		b = appendUint32(b, ti.Sequence)
+	}
+	b = AppendVarInt(b, uint64(len(tx.TxOut)))
+	for _, to := range tx.TxOut {
+		b = appendUint64(b, uint64(to.Value))
+		b = appendUint16(b, to.Version)
+		b = AppendVarBytes(b, to.PkScript)
+	}
+	b = appendUint32(b, tx.LockTime)
+	return appendUint32(b, tx.Expiry)
+}
+
+func (tx *MsgTx) appendWitness(b []byte) []byte {
+	b = AppendVarInt(b, uint64(len(tx.TxIn)))
+	for _, ti := range tx.TxIn {
+		b = appendUint64(b, uint64(ti.ValueIn))
+		b = appendUint32(b, ti.BlockHeight)
+		b = appendUint32(b, ti.BlockIndex)
+		b = AppendVarBytes(b, ti.SignatureScript)
+	}
+	return b
+}
+
+func (tx *MsgTx) prefixSize() int {
+	n := VarIntSerializeSize(uint64(len(tx.TxIn))) +
+		len(tx.TxIn)*minTxInPrefixSize +
+		VarIntSerializeSize(uint64(len(tx.TxOut))) + 8
+	for _, to := range tx.TxOut {
+		n += 8 + 2 + VarIntSerializeSize(uint64(len(to.PkScript))) +
+			len(to.PkScript)
+	}
+	return n
+}
+
+func (tx *MsgTx) witnessSize() int {
+	n := VarIntSerializeSize(uint64(len(tx.TxIn)))
+	for _, ti := range tx.TxIn {
+		n += 8 + 4 + 4 + VarIntSerializeSize(uint64(len(ti.SignatureScript))) +
+			len(ti.SignatureScript)
+	}
+	return n
+}
+
+// SerializeSize returns the size of the transaction serialized with its
+// SerType.
+func (tx *MsgTx) SerializeSize() int {
+	switch tx.SerType {
+	case TxSerializeNoWitness:
+		return 4 + tx.prefixSize()
+	case TxSerializeOnlyWitness:
+		return 4 + tx.witnessSize()
+	}
+	return 4 + tx.prefixSize() + tx.witnessSize()
+}
+
+// appendSerialized appends the serialization of the transaction with the
+// given type: the version with the type in its upper 16 bits followed by the
+// selected sections.
+func (tx *MsgTx) appendSerialized(b []byte, serType TxSerializeType) []byte {
+	b = appendUint32(b, uint32(tx.Version)|uint32(serType)<<16)
+	switch serType {
+	case TxSerializeNoWitness:
+		b = tx.appendPrefix(b)
+	case TxSerializeOnlyWitness:
+		b = tx.appendWitness(b)
+	default:
+		b = tx.appendPrefix(b)
+		b = tx.appendWitness(b)
+	}
+	return b
+}
+
+// Bytes returns the transaction serialized with its SerType.
+func (tx *MsgTx) Bytes() []byte {
+	return tx.appendSerialized(make([]byte, 0, tx.SerializeSize()), tx.SerType)
+}
+
+// Serialize writes the transaction serialized with its SerType to w.
+func (tx *MsgTx) Serialize(w io.Writer) error {
+	_, err := w.Write(tx.Bytes())
+	return err
+}
+
+// TxHash returns the hash of the transaction prefix, which identifies the
+// transaction and is not affected by signatures.
+func (tx *MsgTx) TxHash() Hash {
+	b := make([]byte, 0, 4+tx.prefixSize())
+	return HashH(tx.appendSerialized(b, TxSerializeNoWitness))
+}
+
+// TxHashWitness returns the hash of the transaction witness.
+func (tx *MsgTx) TxHashWitness() Hash {
+	b := make([]byte, 0, 4+tx.witnessSize())
+	return HashH(tx.appendSerialized(b, TxSerializeOnlyWitness))
+}
+
+// TxHashFull returns the hash committing to both the prefix and the witness,
+// BLAKE-256(TxHash || TxHashWitness).
+func (tx *MsgTx) TxHashFull() Hash {
+	var b [2 * HashSize]byte
+	prefix, witness := tx.TxHash(), tx.TxHashWitness()
+	copy(b[:], prefix[:])
+	copy(b[HashSize:], witness[:])
+	return HashH(b[:])
+}
+
+func readCount(r io.Reader, max uint64, fieldName string) (int, error) {
+	n, err := ReadVarInt(r)
+	if err != nil {
+		return 0, err
+	}
+	if n > max {
+		return 0, fmt.Errorf("%w: %s is %d, max %d", ErrTooLarge, fieldName,
+			n, max)
+	}
+	return int(n), nil
+}
+
+func (tx *MsgTx) decodePrefix(r io.Reader) error {
+	n, err := readCount(r, maxTxInPerMessage, "input count")
+	if err != nil {
+		return err
+	}
+	tx.TxIn = make([]*TxIn, n)
+	var buf [8]byte
+	for i := range tx.TxIn {
+		ti := new(TxIn)
+		op := &ti.PreviousOutPoint
+		if _, err := io.ReadFull(r, op.Hash[:]); err != nil {
+			return err
+		}
+		if _, err := io.ReadFull(r, buf[:4+1]); err != nil {
+			return err
+		}
+		op.Index = binary.LittleEndian.Uint32(buf[:])
+		op.Tree = int8(buf[4])
+		if _, err := io.ReadFull(r, buf[:4]); err != nil {
+			return err
+		}
+		ti.Sequence = binary.LittleEndian.Uint32(buf[:])
+		tx.TxIn[i] = ti
+	}
+
+	n, err = readCount(r, maxTxOutPerMessage, "output count")
+	if err != nil {
+		return err
+	}
+	tx.TxOut = make([]*TxOut, n)
+	for i := range tx.TxOut {
+		to := new(TxOut)
+		if _, err := io.ReadFull(r, buf[:8]); err != nil {
+			return err
+		}
+		to.Value = int64(binary.LittleEndian.Uint64(buf[:]))
+		if _, err := io.ReadFull(r, buf[:2]); err != nil {
+			return err
+		}
+		to.Version = binary.LittleEndian.Uint16(buf[:])
+		if to.PkScript, err = ReadVarBytes(r, MaxMessagePayload, "output script"); err != nil {
+			return err
+		}
+		tx.TxOut[i] = to
+	}
+
+	if _, err := io.ReadFull(r, buf[:8]); err != nil {
+		return err
+	}
+	tx.LockTime = binary.LittleEndian.Uint32(buf[:])
+	tx.Expiry = binary.LittleEndian.Uint32(buf[4:])
+	return nil
+}
+
+// decodeWitness reads the witness section. If the prefix was already read
+// the witness fields are filled into its inputs, otherwise inputs holding
+// only witness data are created.
+func (tx *MsgTx) decodeWitness(r io.Reader, hasPrefix bool) error {
+	n, err := readCount(r, MaxMessagePayload/minTxInWitnessSize+1, "witness count")
+	if err != nil {
+		return err
+	}
+	if hasPrefix && n != len(tx.TxIn) {
+		return fmt.Errorf("%w: %d witnesses for %d inputs", ErrWitnessCount,
+			n, len(tx.TxIn))
+	}
+	if !hasPrefix {
+		tx.TxIn = make([]*TxIn, n)
+		for i := range tx.TxIn {
+			tx.TxIn[i] = new(TxIn)
+		}
+	}
+	var buf [16]byte
+	for _, ti := range tx.TxIn {
+		if _, err := io.ReadFull(r, buf[:]); err != nil {
+			return err
+		}
+		ti.ValueIn = int64(binary.LittleEndian.Uint64(buf[:]))
+		ti.BlockHeight = binary.LittleEndian.Uint32(buf[8:])
+		ti.BlockIndex = binary.LittleEndian.Uint32(buf[12:])
+		ti.SignatureScript, err = ReadVarBytes(r, MaxMessagePayload, "signature script")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Deserialize reads a transaction serialized with any of the serialization
+// types from r, replacing the contents of tx.
+func (tx *MsgTx) Deserialize(r io.Reader) error {
+	var buf [4]byte
+	if _, err := io.ReadFull(r, buf[:]); err != nil {
+		return err
+	}
+	v := binary.LittleEndian.Uint32(buf[:])
+	*tx = MsgTx{SerType: TxSerializeType(v >> 16), Version: uint16(v)}
+
+	var err error
+	switch tx.SerType {
+	case TxSerializeFull:
+		if err = tx.decodePrefix(r); err == nil {
+			err = tx.decodeWitness(r, true)
+		}
+	case TxSerializeNoWitness:
+		err = tx.decodePrefix(r)
+	case TxSerializeOnlyWitness:
+		err = tx.decodeWitness(r, false)
+	default:
+		return fmt.Errorf("%w: %d", ErrUnknownSerType, tx.SerType)
+	}
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// FromBytes deserializes the transaction in b, which must not have trailing
+// data.
+func (tx *MsgTx) FromBytes(b []byte) error {
+	r := bytes.NewReader(b)
+	if err := tx.Deserialize(r); err != nil {
+		return err
+	}
+	if r.Len() != 0 {
+		return fmt.Errorf("wire: %d trailing bytes after transaction", r.Len())
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2019 The Decred developers
+// Use of this source code is governed by an ISC
+// license that can be found in the LICENSE file.
+
+package wire
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/rickiey/blake256"
+)
+
+// testTx returns a transaction exercising trees, sequences, null witness
+// values, multi-byte varints and a non-zero output script version. The
+// expected values in the tests were computed with an independent
+// implementation.
+func testTx() *MsgTx {
+	p2pkh := append(append(hexToBytes("76a914"), hexToBytes("000102030405060708090a0b0c0d0e0f10111213")...), 0x88, 0xac)
+	tx := NewMsgTx()
+	tx.AddTxIn(&TxIn{
+		PreviousOutPoint: OutPoint{Hash: HashH([]byte("prev0")), Index: 2, Tree: TxTreeRegular},
+		Sequence:         MaxTxInSequenceNum,
+		ValueIn:          500000000,
+		BlockHeight:      123456,
+		BlockIndex:       7,
+		SignatureScript:  []byte{1, 2, 3},
+	})
+	tx.AddTxIn(&TxIn{
+		PreviousOutPoint: OutPoint{Hash: HashH([]byte("prev1")), Index: 0, Tree: TxTreeStake},
+		Sequence:         0xfffffffe,
+		ValueIn:          25000,
+		BlockHeight:      NullBlockHeight,
+		BlockIndex:       NullBlockIndex,
+		SignatureScript:  []byte{},
+	})
+	tx.AddTxIn(&TxIn{
+		PreviousOutPoint: OutPoint{Hash: HashH([]byte("prev2")), Index: 5, Tree: TxTreeRegular},
+		Sequence:         10,
+		ValueIn:          NullValueIn,
+		BlockHeight:      99,
+		BlockIndex:       1,
+		SignatureScript:  bytes.Repeat([]byte{0x51}, 300),
+	})
+	tx.AddTxOut(NewTxOut(100000000, p2pkh))
+	tx.AddTxOut(NewTxOut(1, append([]byte{0x6a}, bytes.Repeat([]byte{'x'}, 299)...)))
+	tx.AddTxOut(&TxOut{Value: 0, Version: 1, PkScript: []byte{}})
+	tx.LockTime = 0x12345678
+	tx.Expiry = 1000
+	return tx
+}
+
+func TestTxHashes(t *testing.T) {
+	tx := testTx()
+	tests := []struct {
+		name string
+		hash Hash
+		want string
+	}{
+		{"TxHash", tx.TxHash(), "342dd3bf371639fedda354532b6172e2b5fccfecc351513a9ed78b67507d8af6"},
+		{"TxHashWitness", tx.TxHashWitness(), "b9deda87d3ecaec3ecdac2ced407cf2d8ca306413e4b37f186f78889789b9f20"},
+		{"TxHashFull", tx.TxHashFull(), "43add16ca02eebbb0bcee5d008d183f6650dd43aa8b4090f1e1fe17c4c959d56"},
+	}
+	for _, test := range tests {
+		if got := test.hash.String(); got != test.want {
+			t.Errorf("%s: expected %q, got %q", test.name, test.want, got)
+		}
+	}
+
+	b := tx.Bytes()
+	if len(b) != 854 || len(b) != tx.SerializeSize() {
+		t.Errorf("unexpected size %d, SerializeSize %d", len(b), tx.SerializeSize())
+	}
+	const want = "5454e584e3df23b7441e43f02ef833684f6228f1a5a0c9f3958d1772b8e56ae4"
+	if got := fmt.Sprintf("%x", blake256.Sum256(b)); got != want {
+		t.Errorf("serialization: expected hash %q, got %q", want, got)
+	}
+}
+
+// genesisCoinbaseHex is the coinbase transaction of the Decred mainnet genesis
+// block. Its full hash is the merkle root of the genesis header.
+const genesisCoinbaseHex = "0100000001000000000000000000000000000000000000000000000000000000" +
+	"0000000000ffffffff00ffffffff010000000000000000000020801679e98561ada96c" +
+	"aec2949a5d41c4cab3851eb740d951c10ecbcf265c1fd9000000000000000001ffff" +
+	"ffffffffffff00000000ffffffff020000"
+
+func TestTxHashesMainnet(t *testing.T) {
+	var tx MsgTx
+	if err := tx.FromBytes(hexToBytes(genesisCoinbaseHex)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := tx.TxHashFull(); got != genesisHeader().MerkleRoot {
+		t.Errorf("TxHashFull: expected %v, got %v", genesisHeader().MerkleRoot, got)
+	}
+	const want = "e7dfbceac9fccd6025c70a1dfa9302b3e7b5aa22fa51c98a69164ad403d60a2c"
+	if got := tx.TxHash().String(); got != want {
+		t.Errorf("TxHash: expected %q, got %q", want, got)
+	}
+	if got := fmt.Sprintf("%x", tx.Bytes()); got != genesisCoinbaseHex {
+		t.Errorf("serialization: expected %q, got %q", genesisCoinbaseHex, got)
+	}
+}
+
+func TestTxSerializeTypes(t *testing.T) {
+	full := testTx()
+	sizes := map[TxSerializeType]int{
+		TxSerializeFull:        854,
+		TxSerializeNoWitness:   497,
+		TxSerializeOnlyWitness: 361,
+	}
+	for serType, size := range sizes {
+		tx := testTx()
+		tx.SerType = serType
+		b := tx.Bytes()
+		if len(b) != size || tx.SerializeSize() != size {
+			t.Errorf("%d: expected size %d, got %d (SerializeSize %d)", serType,
+				size, len(b), tx.SerializeSize())
+		}
+
+		var got MsgTx
+		if err := got.FromBytes(b); err != nil {
+			t.Fatalf("%d: unexpected error: %v", serType, err)
+		}
+		if !bytes.Equal(got.Bytes(), b) {
+			t.Errorf("%d: reserialization differs", serType)
+		}
+
+		// Each type keeps only its fields, so the hashes it covers match
+		// those of the full transaction.
+		switch serType {
+		case TxSerializeFull:
+			if !reflect.DeepEqual(&got, full) {
+				t.Errorf("%d: decoded transaction differs", serType)
+			}
+		case TxSerializeNoWitness:
+			if got.TxHash() != full.TxHash() {
+				t.Errorf("%d: prefix hash differs", serType)
+			}
+		case TxSerializeOnlyWitness:
+			if got.TxHashWitness() != full.TxHashWitness() {
+				t.Errorf("%d: witness hash differs", serType)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := full.Serialize(&buf); err != nil || !bytes.Equal(buf.Bytes(), full.Bytes()) {
+		t.Errorf("Serialize differs from Bytes (%v)", err)
+	}
+}
+
+func TestTxDecodeErrors(t *testing.T) {
+	b := testTx().Bytes()
+
+	// Every truncation fails with an unexpected EOF.
+	for _, n := range []int{0, 2, 4, 5, 40, 200, 497, 500, len(b) - 1} {
+		var tx MsgTx
+		err := tx.FromBytes(b[:n])
+		if n == 0 && err == io.EOF {
+			continue
+		}
+		if err != io.ErrUnexpectedEOF {
+			t.Errorf("%d: expected %v, got %v", n, io.ErrUnexpectedEOF, err)
+		}
+	}
+
+	var tx MsgTx
+	if err := tx.FromBytes(append(b, 0)); err == nil {
+		t.Error("expected error for trailing data")
+	}
+
+	// Serialization type 3 is not defined.
+	bad := append([]byte(nil), b...)
+	bad[2] = 3
+	if err := tx.FromBytes(bad); !errors.Is(err, ErrUnknownSerType) {
+		t.Errorf("expected %v, got %v", ErrUnknownSerType, err)
+	}
+
+	// Witness count 2 for 3 inputs; the prefix is 497-4 bytes after the
+	// version.
+	bad = append([]byte(nil), b...)
+	bad[497] = 2
+	if err := tx.FromBytes(bad); !errors.Is(err, ErrWitnessCount) {
+		t.Errorf("expected %v, got %v", ErrWitnessCount, err)
+	}
+
+	// An input count larger than a message can hold.
+	huge := hexToBytes("01000000fe00000001")
+	if err := tx.FromBytes(huge); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("expected %v, got %v", ErrTooLarge, err)
+	}
+}
+
+func hexToBytes(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic("invalid hex in source file: " + s)
+	}
+	return b
+}